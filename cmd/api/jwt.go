@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"articles/internal/auth"
+	"articles/internal/config"
+)
+
+// jwksFetchTimeout bounds a JWKS request, which runs while a request waits
+// to be authenticated.
+const jwksFetchTimeout = 5 * time.Second
+
+// newTokenVerifier builds the bearer token verifier cfg describes. A JWKS is
+// loaded on first use, so an issuer that is briefly down does not keep the
+// service from starting.
+func newTokenVerifier(cfg config.JWT) (*auth.JWTVerifier, error) {
+	var keys auth.KeySource
+	switch {
+	case cfg.JWKSURL != "":
+		keys = auth.JWKSFromURL(&http.Client{Timeout: jwksFetchTimeout}, cfg.JWKSURL, cfg.JWKSRefresh)
+	case cfg.JWKSFile != "":
+		keys = auth.JWKSFromFile(cfg.JWKSFile, cfg.JWKSRefresh)
+	case cfg.PublicKeyFile != "":
+		data, err := os.ReadFile(cfg.PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("read JWT_PUBLIC_KEY_FILE: %w", err)
+		}
+		key, err := auth.ParsePublicKeyPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("parse JWT_PUBLIC_KEY_FILE: %w", err)
+		}
+		keys = auth.StaticKey(key)
+	default:
+		keys = auth.StaticKey([]byte(cfg.Secret))
+	}
+
+	opts := []auth.JWTOption{
+		auth.WithIssuer(cfg.Issuer),
+		auth.WithAudience(cfg.Audience),
+		auth.WithRoleClaim(cfg.RolesClaim),
+		auth.WithLeeway(cfg.Leeway),
+	}
+	if len(cfg.RoleMap) > 0 {
+		roles := make(map[string]auth.Role, len(cfg.RoleMap))
+		for from, to := range cfg.RoleMap {
+			role, err := auth.ParseRole(to)
+			if err != nil {
+				return nil, fmt.Errorf("JWT_ROLE_MAP %s=%s: %w", from, to, err)
+			}
+			roles[from] = role
+		}
+		opts = append(opts, auth.WithRoleMap(roles))
+	}
+	return auth.NewJWTVerifier(keys, opts...), nil
+}
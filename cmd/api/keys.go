@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"articles/internal/auth"
+	"articles/internal/config"
+)
+
+const keysTimeout = 30 * time.Second
+
+const keysUsage = "usage: api keys create -name NAME [-scopes articles:read,articles:write] | list | revoke ID"
+
+// runKeys manages API keys. A minted key is printed once, on stdout; only
+// its hash is stored.
+func runKeys(cfg config.Config, keys auth.KeyStore, args []string) error {
+	if cfg.Storage == config.StorageMemory {
+		return errors.New("keys needs a database; unset STORAGE=memory")
+	}
+	if len(args) == 0 {
+		return errors.New(keysUsage)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), keysTimeout)
+	defer cancel()
+
+	switch args[0] {
+	case "create":
+		flags := flag.NewFlagSet("keys create", flag.ContinueOnError)
+		name := flags.String("name", "", "what the key is for, shown in listings")
+		rawScopes := flags.String("scopes", "articles:read,articles:write", "comma separated scopes to grant")
+		if err := flags.Parse(args[1:]); err != nil || flags.NArg() > 0 {
+			return errors.New(keysUsage)
+		}
+		scopes, err := auth.ParseScopes(*rawScopes)
+		if err != nil {
+			return err
+		}
+
+		secret, key, err := auth.NewAPIKey(*name, scopes, time.Now())
+		if err != nil {
+			return err
+		}
+		created, err := keys.CreateKey(ctx, key)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "created key %d (%s); it is shown only once:\n", created.ID, auth.FormatScopes(created.Scopes))
+		fmt.Println(secret)
+	case "list":
+		list, err := keys.ListKeys(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tPREFIX\tSCOPES\tCREATED\tREVOKED")
+		for _, key := range list {
+			revoked := "-"
+			if key.Revoked() {
+				revoked = key.RevokedAt.UTC().Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", key.ID, key.Name, key.Prefix,
+				auth.FormatScopes(key.Scopes), key.CreatedAt.UTC().Format(time.RFC3339), revoked)
+		}
+		return w.Flush()
+	case "revoke":
+		if len(args) != 2 {
+			return errors.New(keysUsage)
+		}
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("revoke expects a numeric key ID, got %q", args[1])
+		}
+		if err := keys.RevokeKey(ctx, id, time.Now()); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "revoked key %d\n", id)
+	default:
+		return errors.New(keysUsage)
+	}
+
+	return nil
+}
@@ -3,114 +3,219 @@ package main
 import (
 	"context"
 	"errors"
-	"log"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
-	gormpostgres "gorm.io/driver/postgres"
-	"gorm.io/gorm"
-
 	httpadapter "articles/internal/adapter/http"
-	"articles/internal/adapter/storage/postgres"
+	"articles/internal/adapter/storage/memory"
+	"articles/internal/config"
+	"articles/internal/idempotency"
+	"articles/internal/logging"
+	"articles/internal/metrics"
+	"articles/internal/ratelimit"
 	"articles/internal/server"
+	"articles/internal/tracing"
 	"articles/internal/usecase"
 )
 
-type Config struct {
-	HTTPPort    string
-	DatabaseURL string
-}
+const purgeTimeout = time.Minute
 
-const (
-	readHeaderTimeout = 5 * time.Second
-	shutdownTimeout   = 5 * time.Second
-)
+// idempotencySweepInterval is how often expired Idempotency-Key responses
+// are deleted while serving.
+const idempotencySweepInterval = time.Hour
 
 func main() {
-	cfg, err := loadConfig()
+	if err := run(); err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	cfg, err := config.Load(os.Getenv)
 	if err != nil {
-		log.Fatalf("invalid configuration: %v", err)
+		return fmt.Errorf("invalid configuration:\n%w", err)
 	}
+	slog.SetDefault(logging.New(os.Stderr, cfg.LogLevel))
 
-	db, cleanup, err := openDB(cfg.DatabaseURL)
+	command, args := "serve", []string(nil)
+	if len(os.Args) > 1 {
+		command, args = os.Args[1], os.Args[2:]
+	}
+	if command == "migrate" {
+		return runMigrate(cfg, args)
+	}
+
+	store, err := openStorage(cfg)
 	if err != nil {
-		log.Fatalf("failed to build server: %v", err)
+		return fmt.Errorf("failed to build server: %w", err)
 	}
 	defer func() {
-		if err := cleanup(); err != nil {
-			log.Printf("failed to close database: %v", err)
+		if err := store.close(); err != nil {
+			slog.Error("failed to close storage", "error", err)
 		}
 	}()
 
-	articleRepo := postgres.NewArticleRepository(db)
-	articleService := usecase.NewArticleService(articleRepo)
-	articleHandler := httpadapter.NewArticleHandler(articleService)
-	router := server.NewRouter(articleHandler, func(ctx context.Context) error {
-		return db.WithContext(ctx).Exec("SELECT 1").Error
-	})
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		return fmt.Errorf("failed to set up tracing: %w", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Error("failed to flush traces", "error", err)
+		}
+	}()
+
+	articleService := usecase.NewArticleService(store.articles,
+		usecase.WithRevisions(store.revisions),
+		usecase.WithTransactor(store.tx),
+	)
+
+	switch command {
+	case "serve":
+		return serve(cfg, articleService, store)
+	case "purge":
+		// Hard deletes are an operator task, so they are only reachable from
+		// the binary and never over HTTP.
+		if err := purge(articleService, cfg.SoftDeleteRetention); err != nil {
+			return fmt.Errorf("purge failed: %w", err)
+		}
+		return nil
+	case "export":
+		return runExport(articleService, args)
+	case "import":
+		return runImport(articleService, args)
+	case "keys":
+		return runKeys(cfg, store.apiKeys, args)
+	default:
+		return fmt.Errorf("unknown command %q (expected serve, migrate, purge, export, import or keys)", command)
+	}
+}
+
+func serve(cfg config.Config, articleService *usecase.ArticleService, store storage) error {
+	appMetrics := metrics.New()
+	if store.sqlDB != nil {
+		if err := appMetrics.RegisterDB("articles", store.sqlDB); err != nil {
+			return fmt.Errorf("register database metrics: %w", err)
+		}
+	}
+
+	articleHandler := httpadapter.NewArticleHandler(articleService, httpadapter.WithErrorObserver(appMetrics.ObserveDomainError))
+	rateLimits := memory.NewRateLimitStore()
+	routerOpts := []server.Option{
+		server.WithMetrics(appMetrics),
+		server.WithTracing(tracing.ServiceName),
+		server.WithIdempotency(store.idempotency, cfg.IdempotencyTTL),
+		server.WithTrustedProxies(cfg.HTTP.TrustedProxies),
+		server.WithRateLimit(rateLimits,
+			ratelimit.Limit{Burst: cfg.RateLimit.Read, Period: cfg.RateLimit.Window},
+			ratelimit.Limit{Burst: cfg.RateLimit.Write, Period: cfg.RateLimit.Window},
+		),
+		server.WithAuthRateLimit(rateLimits, ratelimit.Limit{Burst: cfg.RateLimit.Auth, Period: cfg.RateLimit.Window}),
+	}
+	if cfg.Auth.Enabled {
+		if cfg.Storage != config.StorageMemory {
+			routerOpts = append(routerOpts, server.WithAPIKeys(store.apiKeys))
+		}
+		if cfg.Auth.JWT.Configured() {
+			verifier, err := newTokenVerifier(cfg.Auth.JWT)
+			if err != nil {
+				return err
+			}
+			routerOpts = append(routerOpts, server.WithBearerTokens(verifier))
+		}
+	} else {
+		slog.Warn("authentication is disabled; anyone who can reach the API can change articles")
+	}
+	router := server.NewRouter(articleHandler, store.healthCheck, routerOpts...)
 	httpServer := &http.Server{
-		Addr:              ":" + cfg.HTTPPort,
+		Addr:              ":" + cfg.HTTP.Port,
 		Handler:           router,
-		ReadHeaderTimeout: readHeaderTimeout,
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
 	}
 
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
+	go sweepIdempotencyKeys(ctx, store.idempotency)
+	go publishScheduledArticles(ctx, articleService, cfg.PublishInterval)
+
 	go func() {
-		log.Printf("HTTP server listening on :%s", cfg.HTTPPort)
+		slog.Info("HTTP server listening", "addr", httpServer.Addr)
 		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			log.Fatalf("server error: %v", err)
+			slog.Error("server error", "error", err)
+			os.Exit(1)
 		}
 	}()
 
 	<-ctx.Done()
 
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
 	defer cancel()
 
-	log.Printf("shutting down...")
+	slog.Info("shutting down")
 	if err := httpServer.Shutdown(shutdownCtx); err != nil {
-		log.Printf("graceful shutdown failed: %v", err)
+		slog.Error("graceful shutdown failed", "error", err)
 	}
+	return nil
 }
 
-func loadConfig() (Config, error) {
-	dbURL := os.Getenv("DATABASE_URL")
-	if dbURL == "" {
-		return Config{}, errors.New("DATABASE_URL is required")
-	}
-
-	port := os.Getenv("HTTP_PORT")
-	if port == "" {
-		port = "8080"
+// sweepIdempotencyKeys deletes expired Idempotency-Key responses until ctx
+// is done. Reserve already ignores expired rows; this only keeps the table
+// small.
+func sweepIdempotencyKeys(ctx context.Context, store idempotency.Store) {
+	ticker := time.NewTicker(idempotencySweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := store.DeleteExpired(ctx, time.Now())
+			if err != nil {
+				slog.ErrorContext(ctx, "delete expired idempotency keys failed", "error", err)
+				continue
+			}
+			slog.DebugContext(ctx, "deleted expired idempotency keys", "count", deleted)
+		}
 	}
-
-	return Config{HTTPPort: port, DatabaseURL: dbURL}, nil
 }
 
-func openDB(databaseURL string) (*gorm.DB, func() error, error) {
-	db, err := gorm.Open(gormpostgres.Open(databaseURL), &gorm.Config{})
-	if err != nil {
-		return nil, nil, err
-	}
-
-	sqlDB, err := db.DB()
-	if err != nil {
-		return nil, nil, err
-	}
-
-	if err := sqlDB.Ping(); err != nil {
-		_ = sqlDB.Close()
-		return nil, nil, err
+// publishScheduledArticles publishes in_review articles whose publish_at
+// has passed, every interval until ctx is done.
+func publishScheduledArticles(ctx context.Context, articleService *usecase.ArticleService, interval time.Duration) {
+	ctx = usecase.WithActor(ctx, "scheduler")
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			published, err := articleService.PublishDueArticles(ctx, time.Now())
+			if err != nil {
+				slog.ErrorContext(ctx, "publish scheduled articles failed", "error", err)
+			}
+			if published > 0 {
+				slog.InfoContext(ctx, "published scheduled articles", "count", published)
+			}
+		}
 	}
+}
 
-	cleanup := func() error {
-		return sqlDB.Close()
-	}
+func purge(articleService *usecase.ArticleService, retention time.Duration) error {
+	ctx, cancel := context.WithTimeout(cliContext(), purgeTimeout)
+	defer cancel()
 
-	return db, cleanup, nil
+	_, err := articleService.PurgeDeletedArticles(ctx, retention)
+	return err
 }
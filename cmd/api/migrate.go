@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+
+	dbmigrations "articles/db"
+	"articles/internal/adapter/storage/gormstore"
+	"articles/internal/adapter/storage/sqlite"
+	"articles/internal/config"
+	"articles/internal/migrate"
+)
+
+const migrateTimeout = 5 * time.Minute
+
+const migrateUsage = "usage: api migrate up | down [N] | status | force VERSION | backfill-slugs"
+
+// slugMigration is the version that gave existing articles placeholder
+// slugs; migrating past it replaces them, see gormstore.BackfillSlugs.
+const slugMigration = 9
+
+// newMigrator returns the migrator for the database db is opened on. Up
+// backfills slugs when it starts below slugMigration, while it still holds
+// the schema lock.
+func newMigrator(cfg config.Database, db *gorm.DB) (*migrate.Migrator, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	backfill := migrate.WithAfterUp(func(ctx context.Context, conn *sql.Conn, from int64) error {
+		if from >= slugMigration {
+			return nil
+		}
+		if err := backfillSlugs(ctx, db, conn); err != nil {
+			return fmt.Errorf("%w; run api migrate backfill-slugs to retry", err)
+		}
+		return nil
+	})
+	if cfg.IsSQLite() {
+		return migrate.New(sqlDB, migrate.SQLite, sqlite.Migrations, backfill)
+	}
+	return migrate.New(sqlDB, migrate.Postgres, dbmigrations.Migrations, backfill)
+}
+
+// migrateUp brings the schema up to date on startup. On Postgres the
+// migrator holds an advisory lock, so replicas starting together apply each
+// migration and the slug backfill once.
+func migrateUp(cfg config.Database, db *gorm.DB) error {
+	migrator, err := newMigrator(cfg, db)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), migrateTimeout)
+	defer cancel()
+
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		return fmt.Errorf("migrate up: %w", err)
+	}
+	if applied > 0 {
+		slog.Info("applied migrations", "count", applied)
+	}
+	return nil
+}
+
+// backfillSlugs runs gormstore.BackfillSlugs on conn, the connection holding
+// the schema lock; SQLite has no other to give out.
+func backfillSlugs(ctx context.Context, db *gorm.DB, conn *sql.Conn) error {
+	changed, err := gormstore.BackfillSlugs(gormstore.OnConn(ctx, db, conn))
+	if err != nil {
+		return fmt.Errorf("backfill slugs: %w", err)
+	}
+	if changed > 0 {
+		slog.Info("backfilled slugs", "count", changed)
+	}
+	return nil
+}
+
+func runMigrate(cfg config.Config, args []string) error {
+	if cfg.Storage == config.StorageMemory {
+		return errors.New("migrate needs a database; unset STORAGE=memory")
+	}
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	db, cleanup, err := openDB(cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := cleanup(); err != nil {
+			slog.Error("failed to close database", "error", err)
+		}
+	}()
+
+	migrator, err := newMigrator(cfg.Database, db)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), migrateTimeout)
+	defer cancel()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		slog.Info("applied migrations", "count", applied)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps <= 0 {
+				return fmt.Errorf("down expects a positive number of steps, got %q", args[1])
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		slog.Info("reverted migrations", "count", reverted)
+	case "status":
+		status, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("version: %d (dirty: %t)\n", status.Version, status.Dirty)
+		for _, migration := range status.Applied {
+			fmt.Printf("  applied  %04d_%s\n", migration.Version, migration.Name)
+		}
+		for _, migration := range status.Pending {
+			fmt.Printf("  pending  %04d_%s\n", migration.Version, migration.Name)
+		}
+	case "force":
+		if len(args) < 2 {
+			return errors.New("force expects a version")
+		}
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("force expects a numeric version, got %q", args[1])
+		}
+		if err := migrator.Force(ctx, version); err != nil {
+			return err
+		}
+		slog.Info("forced schema version", "version", version)
+	case "backfill-slugs":
+		return migrator.WithLock(ctx, func(conn *sql.Conn) error {
+			return backfillSlugs(ctx, db, conn)
+		})
+	default:
+		return errors.New(migrateUsage)
+	}
+
+	return nil
+}
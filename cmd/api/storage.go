@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	gormpostgres "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"articles/internal/adapter/storage/gormstore"
+	"articles/internal/adapter/storage/memory"
+	"articles/internal/adapter/storage/postgres"
+	"articles/internal/adapter/storage/sqlite"
+	"articles/internal/auth"
+	"articles/internal/config"
+	"articles/internal/domain"
+	"articles/internal/idempotency"
+)
+
+const slowQueryThreshold = 200 * time.Millisecond
+
+type storage struct {
+	articles  domain.ArticleRepository
+	revisions domain.RevisionRepository
+	// tx is nil for in-memory storage, whose revisions cannot fail to save.
+	tx          domain.Transactor
+	idempotency idempotency.Store
+	apiKeys     auth.KeyStore
+	healthCheck func(context.Context) error
+	close       func() error
+	// sqlDB is the pool behind articles, nil for in-memory storage.
+	sqlDB *sql.DB
+}
+
+func openStorage(cfg config.Config) (storage, error) {
+	if cfg.Storage == config.StorageMemory {
+		return storage{
+			articles:    memory.NewArticleRepository(),
+			revisions:   memory.NewRevisionRepository(),
+			idempotency: memory.NewIdempotencyStore(),
+			apiKeys:     memory.NewAPIKeyStore(),
+			close:       func() error { return nil },
+		}, nil
+	}
+
+	db, cleanup, err := openDB(cfg.Database)
+	if err != nil {
+		return storage{}, err
+	}
+
+	// SQLite files usually live next to the binary with nobody to run
+	// migrations by hand, so they are always kept up to date.
+	if cfg.Database.AutoMigrate || cfg.Database.IsSQLite() {
+		if err := migrateUp(cfg.Database, db); err != nil {
+			_ = cleanup()
+			return storage{}, err
+		}
+	}
+
+	var (
+		articles  domain.ArticleRepository
+		revisions domain.RevisionRepository
+		keys      idempotency.Store
+		apiKeys   auth.KeyStore
+	)
+	if cfg.Database.IsSQLite() {
+		articles = sqlite.NewArticleRepository(db, sqlite.WithQueryTimeout(cfg.Database.QueryTimeout))
+		revisions = sqlite.NewRevisionRepository(db, cfg.Database.QueryTimeout)
+		keys = sqlite.NewIdempotencyStore(db, cfg.Database.QueryTimeout)
+		apiKeys = sqlite.NewAPIKeyStore(db, cfg.Database.QueryTimeout)
+	} else {
+		articles = postgres.NewArticleRepository(db,
+			postgres.WithSearchLanguage(cfg.SearchLanguage),
+			postgres.WithQueryTimeout(cfg.Database.QueryTimeout),
+		)
+		revisions = postgres.NewRevisionRepository(db, cfg.Database.QueryTimeout)
+		keys = postgres.NewIdempotencyStore(db, cfg.Database.QueryTimeout)
+		apiKeys = postgres.NewAPIKeyStore(db, cfg.Database.QueryTimeout)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		_ = cleanup()
+		return storage{}, err
+	}
+
+	return storage{
+		articles:    articles,
+		revisions:   revisions,
+		tx:          gormstore.NewTransactor(db),
+		idempotency: keys,
+		apiKeys:     apiKeys,
+		healthCheck: func(ctx context.Context) error {
+			return db.WithContext(ctx).Exec("SELECT 1").Error
+		},
+		close: cleanup,
+		sqlDB: sqlDB,
+	}, nil
+}
+
+// openDB picks the driver from the URL scheme: sqlite:///path/articles.db
+// opens a SQLite file, anything else is handed to Postgres with the pool
+// sized from cfg.
+func openDB(cfg config.Database) (*gorm.DB, func() error, error) {
+	var (
+		db  *gorm.DB
+		err error
+	)
+	if cfg.IsSQLite() {
+		db, err = sqlite.Open(context.Background(), cfg.SQLitePath())
+	} else {
+		db, err = gorm.Open(gormpostgres.Open(cfg.URL), &gorm.Config{})
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Only failures and slow statements are logged; both carry the request
+	// ID through the statement's context. Bind values are left out.
+	db.Logger = gormlogger.NewSlogLogger(slog.Default(), gormlogger.Config{
+		LogLevel:                  gormlogger.Warn,
+		SlowThreshold:             slowQueryThreshold,
+		IgnoreRecordNotFoundError: true,
+		ParameterizedQueries:      true,
+	})
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// sqlite.Open pins SQLite to a single connection; only Postgres gets a
+	// real pool.
+	if !cfg.IsSQLite() {
+		sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+		sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+
+	if err := sqlDB.Ping(); err != nil {
+		_ = sqlDB.Close()
+		return nil, nil, err
+	}
+
+	cleanup := func() error {
+		return sqlDB.Close()
+	}
+
+	return db, cleanup, nil
+}
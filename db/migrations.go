@@ -0,0 +1,14 @@
+// Package db embeds the PostgreSQL schema migrations so the binary can apply
+// them without the external migrate CLI.
+package db
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed migrations/*.sql
+var embedded embed.FS
+
+// Migrations holds the NNNN_name.up.sql / NNNN_name.down.sql files at its root.
+var Migrations, _ = fs.Sub(embedded, "migrations")
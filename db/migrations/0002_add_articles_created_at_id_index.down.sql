@@ -0,0 +1 @@
+DROP INDEX IF EXISTS articles_created_at_id_idx;
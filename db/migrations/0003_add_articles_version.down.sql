@@ -0,0 +1,3 @@
+ALTER TABLE articles
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS version;
@@ -0,0 +1,6 @@
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- Listing only ever reads live rows, so the keyset index can skip deleted ones.
+DROP INDEX IF EXISTS articles_created_at_id_idx;
+CREATE INDEX IF NOT EXISTS articles_live_created_at_id_idx ON articles (created_at DESC, id DESC) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS articles_deleted_at_idx ON articles (deleted_at) WHERE deleted_at IS NOT NULL;
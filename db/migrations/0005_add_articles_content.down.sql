@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS articles_author_id_idx;
+
+ALTER TABLE articles
+    DROP COLUMN IF EXISTS author_id,
+    DROP COLUMN IF EXISTS summary,
+    DROP COLUMN IF EXISTS body;
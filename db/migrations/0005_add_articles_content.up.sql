@@ -0,0 +1,6 @@
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS body TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS summary TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS author_id TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS articles_author_id_idx ON articles (author_id) WHERE author_id <> '';
@@ -0,0 +1,10 @@
+-- Keep the configuration in sync with indexedSearchLanguage in
+-- internal/adapter/storage/postgres/search.go.
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', title), 'A') ||
+        setweight(to_tsvector('english', summary), 'B') ||
+        setweight(to_tsvector('english', body), 'C')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS articles_search_vector_idx ON articles USING GIN (search_vector);
@@ -0,0 +1,13 @@
+-- Responses remembered for Idempotency-Key retries. status_code stays 0
+-- while the first request with the key is still being served.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    idempotency_key TEXT PRIMARY KEY,
+    fingerprint     TEXT NOT NULL,
+    status_code     INTEGER NOT NULL DEFAULT 0,
+    header          JSONB NOT NULL DEFAULT '{}',
+    body            BYTEA,
+    created_at      TIMESTAMPTZ NOT NULL,
+    expires_at      TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
@@ -0,0 +1,14 @@
+-- Tags are stored once, normalized (lowercase), and linked to articles
+-- through article_tags. Links go with the article when it is purged.
+CREATE TABLE IF NOT EXISTS tags (
+    id   BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS article_tags (
+    article_id BIGINT NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    tag_id     BIGINT NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (article_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS article_tags_tag_id_idx ON article_tags (tag_id);
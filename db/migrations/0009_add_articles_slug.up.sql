@@ -0,0 +1,21 @@
+-- article_slugs holds every slug an article has had, so a slug is never
+-- handed to another article; those other than articles.slug redirect to it.
+-- Existing articles get a placeholder slug: SQL cannot derive one from the
+-- title the way domain.Slugify does, so `api` replaces it right after
+-- migrating (see gormstore.BackfillSlugs).
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS slug TEXT;
+
+UPDATE articles SET slug = 'article-' || id WHERE slug IS NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS articles_slug_key ON articles (slug);
+
+CREATE TABLE IF NOT EXISTS article_slugs (
+    slug       TEXT PRIMARY KEY,
+    article_id BIGINT NOT NULL REFERENCES articles (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS article_slugs_article_id_idx ON article_slugs (article_id);
+
+INSERT INTO article_slugs (slug, article_id)
+SELECT slug, id FROM articles
+ON CONFLICT (slug) DO NOTHING;
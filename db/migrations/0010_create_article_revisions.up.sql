@@ -0,0 +1,24 @@
+-- Every change to an article is kept as a revision numbered by the article
+-- version it produced. Tags are a JSON array of names. Existing articles
+-- start with their current state as their first revision.
+CREATE TABLE IF NOT EXISTS article_revisions (
+    article_id BIGINT NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    number     BIGINT NOT NULL,
+    title      TEXT NOT NULL,
+    slug       TEXT NOT NULL DEFAULT '',
+    body       TEXT NOT NULL DEFAULT '',
+    summary    TEXT NOT NULL DEFAULT '',
+    tags       TEXT NOT NULL DEFAULT '[]',
+    changed_by TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (article_id, number)
+);
+
+INSERT INTO article_revisions (article_id, number, title, slug, body, summary, tags, created_at)
+SELECT a.id, a.version, a.title, a.slug, a.body, a.summary,
+       COALESCE((SELECT json_agg(t.name ORDER BY t.name)::text
+                 FROM article_tags at JOIN tags t ON t.id = at.tag_id
+                 WHERE at.article_id = a.id), '[]'),
+       a.updated_at
+FROM articles a
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS articles_scheduled_idx;
+ALTER TABLE articles
+    DROP COLUMN IF EXISTS publish_at,
+    DROP COLUMN IF EXISTS status;
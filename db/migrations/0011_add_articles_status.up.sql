@@ -0,0 +1,12 @@
+-- Articles written before statuses existed were all live, so they become
+-- published as of their creation; new articles start as drafts.
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'draft'
+        CHECK (status IN ('draft', 'in_review', 'published', 'archived')),
+    ADD COLUMN IF NOT EXISTS publish_at TIMESTAMPTZ;
+
+UPDATE articles SET status = 'published', publish_at = created_at;
+
+-- The scheduler looks for reviewed articles whose publish_at has passed.
+CREATE INDEX IF NOT EXISTS articles_scheduled_idx ON articles (publish_at)
+    WHERE status = 'in_review' AND publish_at IS NOT NULL AND deleted_at IS NULL;
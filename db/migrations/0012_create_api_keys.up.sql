@@ -0,0 +1,12 @@
+-- API keys are stored as the SHA-256 of the key; prefix is the first
+-- characters of the key, kept in clear to tell keys apart. scopes is a space
+-- separated list.
+CREATE TABLE IF NOT EXISTS api_keys (
+    id         BIGSERIAL PRIMARY KEY,
+    name       TEXT NOT NULL,
+    prefix     TEXT NOT NULL,
+    key_hash   TEXT NOT NULL UNIQUE,
+    scopes     TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ
+);
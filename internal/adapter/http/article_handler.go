@@ -1,10 +1,13 @@
 package httpadapter
 
 import (
+	"encoding/json"
 	"errors"
-	"log"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -14,37 +17,148 @@ import (
 )
 
 type ArticleHandler struct {
-	service *usecase.ArticleService
+	service       *usecase.ArticleService
+	observeErrors func(code string)
 }
 
-func NewArticleHandler(service *usecase.ArticleService) *ArticleHandler {
-	return &ArticleHandler{service: service}
+type Option func(*ArticleHandler)
+
+// WithErrorObserver reports the problem code of every error handleError
+// returns to a client, for example to count them in metrics.
+func WithErrorObserver(observe func(code string)) Option {
+	return func(h *ArticleHandler) {
+		h.observeErrors = observe
+	}
+}
+
+func NewArticleHandler(service *usecase.ArticleService, opts ...Option) *ArticleHandler {
+	h := &ArticleHandler{service: service, observeErrors: func(string) {}}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 type createArticleRequest struct {
-	Title string `json:"title"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	Summary   string     `json:"summary"`
+	AuthorID  string     `json:"author_id"`
+	Tags      []string   `json:"tags"`
+	Status    string     `json:"status"`
+	PublishAt *time.Time `json:"publish_at"`
+}
+
+func (r createArticleRequest) input() domain.ArticleInput {
+	return domain.ArticleInput{
+		Title:     r.Title,
+		Body:      r.Body,
+		Summary:   r.Summary,
+		AuthorID:  r.AuthorID,
+		Tags:      r.Tags,
+		Status:    domain.ArticleStatus(r.Status),
+		PublishAt: r.PublishAt,
+	}
+}
+
+// replaceArticleRequest omits author_id: the author is fixed at creation.
+// Tags, status and publish_at are left as they are when the body has none.
+type replaceArticleRequest struct {
+	Title     string       `json:"title"`
+	Body      string       `json:"body"`
+	Summary   string       `json:"summary"`
+	Tags      *[]string    `json:"tags"`
+	Status    *string      `json:"status"`
+	PublishAt nullableTime `json:"publish_at"`
+}
+
+type updateArticleRequest struct {
+	Title     *string      `json:"title"`
+	Body      *string      `json:"body"`
+	Summary   *string      `json:"summary"`
+	Tags      *[]string    `json:"tags"`
+	Status    *string      `json:"status"`
+	PublishAt nullableTime `json:"publish_at"`
+}
+
+// nullableTime tells a missing time apart from an explicit null, which clears
+// it.
+type nullableTime struct {
+	Set  bool
+	Time *time.Time
+}
+
+func (t *nullableTime) UnmarshalJSON(data []byte) error {
+	t.Set = true
+	return json.Unmarshal(data, &t.Time)
+}
+
+// patch maps t to an ArticlePatch field, where the zero time means clear.
+func (t nullableTime) patch() *time.Time {
+	switch {
+	case !t.Set:
+		return nil
+	case t.Time == nil:
+		return &time.Time{}
+	default:
+		return t.Time
+	}
+}
+
+// statusPatch maps an optional status from a request body to an ArticlePatch
+// field.
+func statusPatch(raw *string) *domain.ArticleStatus {
+	if raw == nil {
+		return nil
+	}
+	status := domain.ArticleStatus(*raw)
+	return &status
 }
 
 type articleResponse struct {
-	ID        int64     `json:"id"`
-	Title     string    `json:"title"`
-	CreatedAt time.Time `json:"created_at"`
+	ID        int64      `json:"id"`
+	Title     string     `json:"title"`
+	Slug      string     `json:"slug"`
+	Body      string     `json:"body"`
+	Summary   string     `json:"summary"`
+	AuthorID  string     `json:"author_id"`
+	Tags      []string   `json:"tags"`
+	Status    string     `json:"status"`
+	PublishAt *time.Time `json:"publish_at"`
+	Version   int64      `json:"version"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+type searchResultResponse struct {
+	articleResponse
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+type searchArticlesResponse struct {
+	Items []searchResultResponse `json:"items"`
+}
+
+type listArticlesResponse struct {
+	Items      []articleResponse `json:"items"`
+	NextCursor string            `json:"next_cursor,omitempty"`
 }
 
 func (h *ArticleHandler) CreateArticle(c *gin.Context) {
 	var req createArticleRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	article, err := h.service.CreateArticle(c.Request.Context(), req.Title)
+	article, err := h.service.CreateArticle(c.Request.Context(), req.input())
 	if err != nil {
-		log.Printf("create article failed: %v", err)
+		logFailure(c, "create article failed", err)
 		h.handleError(c, err)
 		return
 	}
 
+	setETag(c, article)
 	c.JSON(http.StatusCreated, toResponse(article))
 }
 
@@ -58,31 +172,293 @@ func (h *ArticleHandler) GetArticle(c *gin.Context) {
 
 	article, err := h.service.GetArticle(c.Request.Context(), id)
 	if err != nil {
-		log.Printf("get article failed: %v", err)
+		logFailure(c, "get article failed", err)
+		h.handleError(c, err)
+		return
+	}
+
+	setETag(c, article)
+	c.JSON(http.StatusOK, toResponse(article))
+}
+
+// GetArticleBySlug handles GET /article/by-slug/:slug. A former slug is
+// answered with a permanent redirect to the current one.
+func (h *ArticleHandler) GetArticleBySlug(c *gin.Context) {
+	slug := c.Param("slug")
+	article, err := h.service.GetArticleBySlug(c.Request.Context(), slug)
+	if err != nil {
+		logFailure(c, "get article by slug failed", err)
+		h.handleError(c, err)
+		return
+	}
+
+	if article.Slug != slug {
+		c.Redirect(http.StatusMovedPermanently, "/article/by-slug/"+url.PathEscape(article.Slug))
+		return
+	}
+
+	setETag(c, article)
+	c.JSON(http.StatusOK, toResponse(article))
+}
+
+// ReplaceArticle handles PUT: every field is taken from the body.
+func (h *ArticleHandler) ReplaceArticle(c *gin.Context) {
+	var req replaceArticleRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	h.updateArticle(c, domain.ArticlePatch{
+		Title:     &req.Title,
+		Body:      &req.Body,
+		Summary:   &req.Summary,
+		Tags:      req.Tags,
+		Status:    statusPatch(req.Status),
+		PublishAt: req.PublishAt.patch(),
+	})
+}
+
+// PatchArticle handles PATCH: fields missing from the body are left as is.
+func (h *ArticleHandler) PatchArticle(c *gin.Context) {
+	var req updateArticleRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	h.updateArticle(c, domain.ArticlePatch{
+		Title:     req.Title,
+		Body:      req.Body,
+		Summary:   req.Summary,
+		Tags:      req.Tags,
+		Status:    statusPatch(req.Status),
+		PublishAt: req.PublishAt.patch(),
+	})
+}
+
+func (h *ArticleHandler) updateArticle(c *gin.Context, patch domain.ArticlePatch) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		h.handleError(c, domain.ErrInvalidID)
+		return
+	}
+
+	expectedVersion, ok := h.requireIfMatch(c)
+	if !ok {
+		return
+	}
+
+	article, err := h.service.UpdateArticle(c.Request.Context(), id, expectedVersion, patch)
+	if err != nil {
+		logFailure(c, "update article failed", err)
+		h.handleError(c, err)
+		return
+	}
+
+	setETag(c, article)
+	c.JSON(http.StatusOK, toResponse(article))
+}
+
+// ListArticles handles GET /article. Repeated tag parameters restrict the
+// listing to articles with all of those tags, or any of them with
+// tag_match=any.
+func (h *ArticleHandler) ListArticles(c *gin.Context) {
+	limit, err := queryLimit(c)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	filter := domain.TagFilter{Tags: c.QueryArray("tag"), Match: domain.TagMatch(c.Query("tag_match"))}
+	page, err := h.service.ListArticles(c.Request.Context(), limit, c.Query("cursor"), filter)
+	if err != nil {
+		logFailure(c, "list articles failed", err)
+		h.handleError(c, err)
+		return
+	}
+
+	resp := listArticlesResponse{Items: make([]articleResponse, 0, len(page.Items))}
+	for _, article := range page.Items {
+		resp.Items = append(resp.Items, toResponse(article))
+	}
+	if page.NextCursor != nil {
+		resp.NextCursor = page.NextCursor.Encode()
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *ArticleHandler) SearchArticles(c *gin.Context) {
+	limit, err := queryLimit(c)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	results, err := h.service.SearchArticles(c.Request.Context(), c.Query("q"), limit)
+	if err != nil {
+		logFailure(c, "search articles failed", err)
 		h.handleError(c, err)
 		return
 	}
 
+	resp := searchArticlesResponse{Items: make([]searchResultResponse, 0, len(results))}
+	for _, result := range results {
+		resp.Items = append(resp.Items, searchResultResponse{
+			articleResponse: toResponse(result.Article),
+			Rank:            result.Rank,
+			Snippet:         result.Snippet,
+		})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *ArticleHandler) DeleteArticle(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		h.handleError(c, domain.ErrInvalidID)
+		return
+	}
+
+	if err := h.service.DeleteArticle(c.Request.Context(), id); err != nil {
+		logFailure(c, "delete article failed", err)
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *ArticleHandler) RestoreArticle(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		h.handleError(c, domain.ErrInvalidID)
+		return
+	}
+
+	article, err := h.service.RestoreArticle(c.Request.Context(), id)
+	if err != nil {
+		logFailure(c, "restore article failed", err)
+		h.handleError(c, err)
+		return
+	}
+
+	setETag(c, article)
 	c.JSON(http.StatusOK, toResponse(article))
 }
 
+// logFailure logs a failed call; errors the client caused are warnings, only
+// unexpected ones are logged as errors.
+func logFailure(c *gin.Context, msg string, err error) {
+	level := slog.LevelWarn
+	if problemFor(err).Status >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+	slog.Log(c.Request.Context(), level, msg, "error", err)
+}
+
 func (h *ArticleHandler) handleError(c *gin.Context, err error) {
-	switch {
-	case errors.Is(err, domain.ErrInvalidTitle),
-		errors.Is(err, domain.ErrInvalidID),
-		errors.Is(err, domain.ErrTitleTooLong):
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-	case errors.Is(err, domain.ErrArticleNotFound):
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-	default:
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	problem := problemFor(err)
+	h.observeErrors(problem.Code)
+	WriteProblem(c, problem)
+}
+
+// bindJSON decodes the request body into dst and answers the request itself
+// when that fails.
+func bindJSON(c *gin.Context, dst any) bool {
+	err := c.ShouldBindJSON(dst)
+	if err == nil {
+		return true
+	}
+
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		WriteProblem(c, NewProblem(http.StatusRequestEntityTooLarge, CodeBodyTooLarge, "Request body too large", "request body too large"))
+		return false
 	}
+	WriteProblem(c, NewProblem(http.StatusBadRequest, CodeInvalidBody, "Invalid request body", "invalid request body"))
+	return false
 }
 
 func toResponse(article domain.Article) articleResponse {
 	return articleResponse{
 		ID:        article.ID,
 		Title:     article.Title,
+		Slug:      article.Slug,
+		Body:      article.Body,
+		Summary:   article.Summary,
+		AuthorID:  article.AuthorID,
+		Tags:      nonNilTags(article.Tags),
+		Status:    string(article.Status),
+		PublishAt: article.PublishAt,
+		Version:   article.Version,
 		CreatedAt: article.CreatedAt,
+		UpdatedAt: article.UpdatedAt,
+	}
+}
+
+// nonNilTags makes tags encode as [] rather than null.
+func nonNilTags(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
+// queryLimit parses the optional limit query parameter; 0 means the
+// service default.
+func queryLimit(c *gin.Context) (int, error) {
+	rawLimit := c.Query("limit")
+	if rawLimit == "" {
+		return 0, nil
 	}
+
+	limit, err := strconv.Atoi(rawLimit)
+	if err != nil {
+		return 0, domain.ErrInvalidLimit
+	}
+
+	return limit, nil
+}
+
+// requireIfMatch returns the version the If-Match header expects, answering
+// the request itself when the header is missing or malformed.
+func (h *ArticleHandler) requireIfMatch(c *gin.Context) (int64, bool) {
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch == "" {
+		WriteProblem(c, NewProblem(http.StatusPreconditionRequired, CodeIfMatchRequired, "If-Match required", "If-Match header is required"))
+		return 0, false
+	}
+	expectedVersion, ok := parseETag(ifMatch)
+	if !ok {
+		h.handleError(c, domain.ErrVersionConflict)
+		return 0, false
+	}
+	return expectedVersion, true
+}
+
+func setETag(c *gin.Context, article domain.Article) {
+	c.Header("ETag", strconv.Quote(strconv.FormatInt(article.Version, 10)))
+}
+
+// parseETag extracts the version from an If-Match value. "*" matches any
+// version and is reported as 0. Weak validators are refused: If-Match uses
+// strong comparison, so W/"3" never matches.
+func parseETag(value string) (int64, bool) {
+	value = strings.TrimSpace(value)
+	if value == "*" {
+		return 0, true
+	}
+
+	unquoted, err := strconv.Unquote(value)
+	if err != nil {
+		return 0, false
+	}
+
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, false
+	}
+
+	return version, true
 }
@@ -18,35 +18,109 @@ import (
 )
 
 type stubRepo struct {
-	saveFn    func(ctx context.Context, article domain.Article) (domain.Article, error)
-	getByIDFn func(ctx context.Context, id int64) (domain.Article, error)
+	saveFn      func(ctx context.Context, article domain.Article) (domain.Article, error)
+	saveManyFn  func(ctx context.Context, articles []domain.Article) ([]domain.Article, error)
+	getByIDFn   func(ctx context.Context, id int64) (domain.Article, error)
+	getByIDsFn  func(ctx context.Context, ids []int64) ([]domain.Article, error)
+	getBySlugFn func(ctx context.Context, slug string) (domain.Article, error)
+	listFn      func(ctx context.Context, query domain.ListArticlesQuery) ([]domain.Article, error)
+	listDueFn   func(ctx context.Context, due time.Time, limit int) ([]domain.Article, error)
+	updateFn    func(ctx context.Context, article domain.Article) (domain.Article, error)
+	deleteFn    func(ctx context.Context, id int64) error
+	restoreFn   func(ctx context.Context, id int64) (domain.Article, error)
+	purgeFn     func(ctx context.Context, deletedBefore time.Time) (int64, error)
+	listTagsFn  func(ctx context.Context, status domain.ArticleStatus) ([]domain.TagCount, error)
 }
 
 func (s *stubRepo) Save(ctx context.Context, article domain.Article) (domain.Article, error) {
 	return s.saveFn(ctx, article)
 }
 
+func (s *stubRepo) SaveMany(ctx context.Context, articles []domain.Article) ([]domain.Article, error) {
+	return s.saveManyFn(ctx, articles)
+}
+
 func (s *stubRepo) GetByID(ctx context.Context, id int64) (domain.Article, error) {
 	return s.getByIDFn(ctx, id)
 }
 
-func setupRouter(t *testing.T, repo domain.ArticleRepository) *gin.Engine {
+func (s *stubRepo) GetByIDs(ctx context.Context, ids []int64) ([]domain.Article, error) {
+	return s.getByIDsFn(ctx, ids)
+}
+
+func (s *stubRepo) GetBySlug(ctx context.Context, slug string) (domain.Article, error) {
+	return s.getBySlugFn(ctx, slug)
+}
+
+func (s *stubRepo) List(ctx context.Context, query domain.ListArticlesQuery) ([]domain.Article, error) {
+	return s.listFn(ctx, query)
+}
+
+func (s *stubRepo) ListScheduled(ctx context.Context, due time.Time, limit int) ([]domain.Article, error) {
+	return s.listDueFn(ctx, due, limit)
+}
+
+func (s *stubRepo) Update(ctx context.Context, article domain.Article) (domain.Article, error) {
+	return s.updateFn(ctx, article)
+}
+
+func (s *stubRepo) Delete(ctx context.Context, id int64) error {
+	return s.deleteFn(ctx, id)
+}
+
+func (s *stubRepo) Restore(ctx context.Context, id int64) (domain.Article, error) {
+	return s.restoreFn(ctx, id)
+}
+
+func (s *stubRepo) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	return s.purgeFn(ctx, deletedBefore)
+}
+
+func (s *stubRepo) ListTags(ctx context.Context, status domain.ArticleStatus) ([]domain.TagCount, error) {
+	return s.listTagsFn(ctx, status)
+}
+
+func setupRouter(t *testing.T, repo domain.ArticleRepository, opts ...usecase.Option) *gin.Engine {
 	t.Helper()
 	gin.SetMode(gin.TestMode)
 
-	service := usecase.NewArticleService(repo)
+	service := usecase.NewArticleService(repo, opts...)
 	handler := NewArticleHandler(service)
 
 	router := gin.New()
 	router.POST("/article", handler.CreateArticle)
+	router.GET("/article", handler.ListArticles)
+	router.GET("/article/search", handler.SearchArticles)
+	router.GET("/article/export", handler.ExportArticles)
+	router.POST("/article/import", handler.ImportArticles)
+	router.GET("/article/by-slug/:slug", handler.GetArticleBySlug)
 	router.GET("/article/:id", handler.GetArticle)
+	router.PUT("/article/:id", handler.ReplaceArticle)
+	router.PATCH("/article/:id", handler.PatchArticle)
+	router.DELETE("/article/:id", handler.DeleteArticle)
+	router.POST("/article/:id/restore", handler.RestoreArticle)
+	router.GET("/article/:id/revisions", handler.ListRevisions)
+	router.GET("/article/:id/revisions/diff", handler.DiffRevisions)
+	router.GET("/article/:id/revisions/:rev", handler.GetRevision)
+	router.POST("/article/:id/revisions/:rev/revert", handler.RevertArticle)
+	router.GET("/articles", handler.GetArticles)
+	router.GET("/tags", handler.ListTags)
+	// Mirrors the server's /articles:method route without the dispatch.
+	router.POST("/articles:method", handler.BatchCreateArticles)
 
 	return router
 }
 
 func performRequest(r http.Handler, method, path string, body []byte) *httptest.ResponseRecorder {
+	return performRequestWithHeaders(r, method, path, body, nil)
+}
+
+func performRequestWithHeaders(r http.Handler, method, path string, body []byte, headers map[string]string) *httptest.ResponseRecorder {
 	req := httptest.NewRequest(method, path, bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 	return w
@@ -79,6 +153,46 @@ func TestCreateArticle_Success(t *testing.T) {
 	}
 }
 
+func TestCreateArticle_WithContent(t *testing.T) {
+	router := setupRouter(t, &stubRepo{
+		saveFn: func(_ context.Context, article domain.Article) (domain.Article, error) {
+			if article.Body != "# Hi" || article.Summary != "Short" || article.AuthorID != "user-1" {
+				t.Fatalf("unexpected article passed to repo: %+v", article)
+			}
+			article.ID = 1
+			return article, nil
+		},
+	})
+
+	rec := performRequest(router, http.MethodPost, "/article", []byte(`{"title":"Hello","body":"# Hi","summary":"Short","author_id":"user-1"}`))
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+
+	var resp articleResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Body != "# Hi" || resp.Summary != "Short" || resp.AuthorID != "user-1" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestCreateArticle_SummaryTooLong(t *testing.T) {
+	router := setupRouter(t, &stubRepo{})
+	longSummary := strings.Repeat("a", domain.MaxSummaryLength+1)
+
+	rec := performRequest(router, http.MethodPost, "/article", []byte(`{"title":"Hello","summary":"`+longSummary+`"}`))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+	if !bytes.Contains(rec.Body.Bytes(), []byte(domain.ErrSummaryTooLong.Error())) {
+		t.Fatalf("expected error message, got %s", rec.Body.String())
+	}
+}
+
 func TestCreateArticle_BadJSON(t *testing.T) {
 	router := setupRouter(t, &stubRepo{})
 
@@ -139,7 +253,7 @@ func TestCreateArticle_RepoError(t *testing.T) {
 }
 
 func TestGetArticle_Success(t *testing.T) {
-	expected := domain.Article{ID: 2, Title: "Hello", CreatedAt: time.Unix(0, 0)}
+	expected := domain.Article{ID: 2, Title: "Hello", Status: domain.StatusPublished, CreatedAt: time.Unix(0, 0)}
 	router := setupRouter(t, &stubRepo{
 		saveFn: nil,
 		getByIDFn: func(_ context.Context, id int64) (domain.Article, error) {
@@ -214,3 +328,302 @@ func TestGetArticle_RepoError(t *testing.T) {
 		t.Fatalf("unexpected body: %s", rec.Body.String())
 	}
 }
+
+func TestListArticles_Success(t *testing.T) {
+	router := setupRouter(t, &stubRepo{
+		listFn: func(_ context.Context, query domain.ListArticlesQuery) ([]domain.Article, error) {
+			if query.Limit != 2 {
+				t.Fatalf("expected repo limit 2, got %d", query.Limit)
+			}
+			return []domain.Article{
+				{ID: 2, Title: "Second", CreatedAt: time.Unix(20, 0)},
+				{ID: 1, Title: "First", CreatedAt: time.Unix(10, 0)},
+			}, nil
+		},
+	})
+
+	rec := performRequest(router, http.MethodGet, "/article?limit=1", nil)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var resp listArticlesResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Items) != 1 || resp.Items[0].ID != 2 {
+		t.Fatalf("unexpected items: %+v", resp.Items)
+	}
+	cursor, err := domain.ParseArticleCursor(resp.NextCursor)
+	if err != nil {
+		t.Fatalf("expected a valid next_cursor, got %q: %v", resp.NextCursor, err)
+	}
+	if cursor.ID != 2 {
+		t.Fatalf("expected cursor to point at id 2, got %+v", cursor)
+	}
+}
+
+func TestListArticles_Empty(t *testing.T) {
+	router := setupRouter(t, &stubRepo{
+		listFn: func(_ context.Context, _ domain.ListArticlesQuery) ([]domain.Article, error) {
+			return nil, nil
+		},
+	})
+
+	rec := performRequest(router, http.MethodGet, "/article", nil)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if rec.Body.String() != `{"items":[]}` {
+		t.Fatalf("unexpected body: %s", rec.Body.String())
+	}
+}
+
+func TestListArticles_InvalidLimit(t *testing.T) {
+	router := setupRouter(t, &stubRepo{})
+
+	rec := performRequest(router, http.MethodGet, "/article?limit=abc", nil)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+	if !bytes.Contains(rec.Body.Bytes(), []byte(domain.ErrInvalidLimit.Error())) {
+		t.Fatalf("expected invalid limit message, got %s", rec.Body.String())
+	}
+}
+
+func TestListArticles_InvalidCursor(t *testing.T) {
+	router := setupRouter(t, &stubRepo{})
+
+	rec := performRequest(router, http.MethodGet, "/article?cursor=garbage", nil)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+	if !bytes.Contains(rec.Body.Bytes(), []byte(domain.ErrInvalidCursor.Error())) {
+		t.Fatalf("expected invalid cursor message, got %s", rec.Body.String())
+	}
+}
+
+func updateStubRepo(t *testing.T, current domain.Article) *stubRepo {
+	t.Helper()
+	return &stubRepo{
+		getByIDFn: func(_ context.Context, _ int64) (domain.Article, error) {
+			return current, nil
+		},
+		updateFn: func(_ context.Context, article domain.Article) (domain.Article, error) {
+			article.Version++
+			return article, nil
+		},
+	}
+}
+
+func TestGetArticle_SetsETag(t *testing.T) {
+	router := setupRouter(t, &stubRepo{
+		getByIDFn: func(_ context.Context, _ int64) (domain.Article, error) {
+			return domain.Article{ID: 2, Title: "Hello", Status: domain.StatusPublished, Version: 5}, nil
+		},
+	})
+
+	rec := performRequest(router, http.MethodGet, "/article/2", nil)
+
+	if got := rec.Header().Get("ETag"); got != `"5"` {
+		t.Fatalf("expected ETag %q, got %q", `"5"`, got)
+	}
+}
+
+func TestReplaceArticle_Success(t *testing.T) {
+	router := setupRouter(t, updateStubRepo(t, domain.Article{ID: 2, Title: "Helo", Version: 1}))
+
+	rec := performRequestWithHeaders(router, http.MethodPut, "/article/2", []byte(`{"title":"Hello"}`), map[string]string{"If-Match": `"1"`})
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("ETag"); got != `"2"` {
+		t.Fatalf("expected ETag %q, got %q", `"2"`, got)
+	}
+
+	var resp articleResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Title != "Hello" || resp.Version != 2 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestReplaceArticle_MissingTitle(t *testing.T) {
+	router := setupRouter(t, updateStubRepo(t, domain.Article{ID: 2, Title: "Hello", Version: 1}))
+
+	rec := performRequestWithHeaders(router, http.MethodPut, "/article/2", []byte(`{}`), map[string]string{"If-Match": `"1"`})
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+	if !bytes.Contains(rec.Body.Bytes(), []byte(domain.ErrInvalidTitle.Error())) {
+		t.Fatalf("expected error message, got %s", rec.Body.String())
+	}
+}
+
+func TestPatchArticle_MissingFields(t *testing.T) {
+	router := setupRouter(t, updateStubRepo(t, domain.Article{ID: 2, Title: "Hello", Version: 3}))
+
+	rec := performRequestWithHeaders(router, http.MethodPatch, "/article/2", []byte(`{}`), map[string]string{"If-Match": `"3"`})
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var resp articleResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Title != "Hello" {
+		t.Fatalf("expected title to be kept, got %q", resp.Title)
+	}
+}
+
+func TestPatchArticle_MissingIfMatch(t *testing.T) {
+	router := setupRouter(t, &stubRepo{})
+
+	rec := performRequest(router, http.MethodPatch, "/article/2", []byte(`{"title":"Hello"}`))
+
+	if rec.Code != http.StatusPreconditionRequired {
+		t.Fatalf("expected status %d, got %d", http.StatusPreconditionRequired, rec.Code)
+	}
+}
+
+func TestPatchArticle_StaleETag(t *testing.T) {
+	router := setupRouter(t, updateStubRepo(t, domain.Article{ID: 2, Title: "Hello", Version: 4}))
+
+	rec := performRequestWithHeaders(router, http.MethodPatch, "/article/2", []byte(`{"title":"Hi"}`), map[string]string{"If-Match": `"3"`})
+
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("expected status %d, got %d", http.StatusPreconditionFailed, rec.Code)
+	}
+	if !bytes.Contains(rec.Body.Bytes(), []byte(domain.ErrVersionConflict.Error())) {
+		t.Fatalf("expected conflict message, got %s", rec.Body.String())
+	}
+}
+
+func TestPatchArticle_MalformedETag(t *testing.T) {
+	router := setupRouter(t, &stubRepo{})
+
+	rec := performRequestWithHeaders(router, http.MethodPatch, "/article/2", []byte(`{"title":"Hi"}`), map[string]string{"If-Match": "3"})
+
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("expected status %d, got %d", http.StatusPreconditionFailed, rec.Code)
+	}
+}
+
+func TestPatchArticle_WeakETag(t *testing.T) {
+	router := setupRouter(t, updateStubRepo(t, domain.Article{ID: 2, Title: "Hello", Version: 3}))
+
+	rec := performRequestWithHeaders(router, http.MethodPatch, "/article/2", []byte(`{"title":"Hi"}`), map[string]string{"If-Match": `W/"3"`})
+
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("expected status %d, got %d", http.StatusPreconditionFailed, rec.Code)
+	}
+}
+
+func TestDeleteArticle_Success(t *testing.T) {
+	router := setupRouter(t, &stubRepo{
+		deleteFn: func(_ context.Context, id int64) error {
+			if id != 2 {
+				t.Fatalf("expected id 2, got %d", id)
+			}
+			return nil
+		},
+	})
+
+	rec := performRequest(router, http.MethodDelete, "/article/2", nil)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected status %d, got %d", http.StatusNoContent, rec.Code)
+	}
+}
+
+func TestDeleteArticle_NotFound(t *testing.T) {
+	router := setupRouter(t, &stubRepo{
+		deleteFn: func(_ context.Context, _ int64) error {
+			return domain.ErrArticleNotFound
+		},
+	})
+
+	rec := performRequest(router, http.MethodDelete, "/article/2", nil)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestRestoreArticle_Success(t *testing.T) {
+	router := setupRouter(t, &stubRepo{
+		restoreFn: func(_ context.Context, id int64) (domain.Article, error) {
+			return domain.Article{ID: id, Title: "Hello", Version: 2}, nil
+		},
+	})
+
+	rec := performRequest(router, http.MethodPost, "/article/2/restore", nil)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if got := rec.Header().Get("ETag"); got != `"2"` {
+		t.Fatalf("expected ETag %q, got %q", `"2"`, got)
+	}
+}
+
+func TestRestoreArticle_InvalidID(t *testing.T) {
+	router := setupRouter(t, &stubRepo{})
+
+	rec := performRequest(router, http.MethodPost, "/article/abc/restore", nil)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestSearchArticles_Success(t *testing.T) {
+	router := setupRouter(t, &stubRepo{
+		listFn: func(_ context.Context, _ domain.ListArticlesQuery) ([]domain.Article, error) {
+			return []domain.Article{
+				{ID: 2, Title: "Unrelated"},
+				{ID: 1, Title: "Hello", Body: "Hello world"},
+			}, nil
+		},
+	})
+
+	rec := performRequest(router, http.MethodGet, "/article/search?q=world", nil)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var resp searchArticlesResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Items) != 1 || resp.Items[0].ID != 1 {
+		t.Fatalf("unexpected items: %+v", resp.Items)
+	}
+	if resp.Items[0].Snippet != "Hello <b>world</b>" {
+		t.Fatalf("unexpected snippet: %q", resp.Items[0].Snippet)
+	}
+}
+
+func TestSearchArticles_MissingQuery(t *testing.T) {
+	router := setupRouter(t, &stubRepo{})
+
+	rec := performRequest(router, http.MethodGet, "/article/search", nil)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+	if !bytes.Contains(rec.Body.Bytes(), []byte(domain.ErrInvalidSearch.Error())) {
+		t.Fatalf("expected invalid search message, got %s", rec.Body.String())
+	}
+}
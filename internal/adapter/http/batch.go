@@ -0,0 +1,153 @@
+package httpadapter
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"articles/internal/domain"
+)
+
+// Per-item statuses of a batch create response.
+const (
+	batchItemCreated = "created"
+	batchItemFailed  = "failed"
+	batchItemAborted = "aborted"
+)
+
+type batchCreateRequest struct {
+	// Mode is "atomic" (the default) or "best_effort".
+	Mode  string                 `json:"mode"`
+	Items []createArticleRequest `json:"items"`
+}
+
+type batchItemError struct {
+	Code   string       `json:"code"`
+	Detail string       `json:"detail"`
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+type batchItemResponse struct {
+	Index   int              `json:"index"`
+	Status  string           `json:"status"`
+	Article *articleResponse `json:"article,omitempty"`
+	Error   *batchItemError  `json:"error,omitempty"`
+}
+
+type batchCreateResponse struct {
+	Mode    domain.BatchMode    `json:"mode"`
+	Created int                 `json:"created"`
+	Failed  int                 `json:"failed"`
+	Items   []batchItemResponse `json:"items"`
+}
+
+type getArticlesResponse struct {
+	Items   []articleResponse `json:"items"`
+	Missing []int64           `json:"missing"`
+}
+
+// BatchCreateArticles handles POST /articles:batchCreate. The response is
+// 201 when every item was created, 207 when only some were and 422 when
+// none were; each item reports its own outcome either way.
+func (h *ArticleHandler) BatchCreateArticles(c *gin.Context) {
+	var req batchCreateRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	mode, err := domain.ParseBatchMode(req.Mode)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	inputs := make([]domain.ArticleInput, 0, len(req.Items))
+	for _, item := range req.Items {
+		inputs = append(inputs, item.input())
+	}
+
+	result, err := h.service.BatchCreateArticles(c.Request.Context(), inputs, mode)
+	if err != nil {
+		logFailure(c, "batch create articles failed", err)
+		h.handleError(c, err)
+		return
+	}
+
+	resp := batchCreateResponse{
+		Mode:    result.Mode,
+		Created: result.Created,
+		Failed:  len(result.Items) - result.Created,
+		Items:   make([]batchItemResponse, 0, len(result.Items)),
+	}
+	for i, item := range result.Items {
+		entry := batchItemResponse{Index: i, Status: batchItemCreated}
+		if item.Err == nil {
+			article := toResponse(item.Article)
+			entry.Article = &article
+		} else {
+			entry.Status = batchItemFailed
+			if errors.Is(item.Err, domain.ErrBatchAborted) {
+				entry.Status = batchItemAborted
+			}
+			problem := problemFor(item.Err)
+			entry.Error = &batchItemError{Code: problem.Code, Detail: problem.Detail, Errors: problem.Errors}
+		}
+		resp.Items = append(resp.Items, entry)
+	}
+
+	status := http.StatusMultiStatus
+	switch resp.Created {
+	case len(resp.Items):
+		status = http.StatusCreated
+	case 0:
+		status = http.StatusUnprocessableEntity
+	}
+	c.JSON(status, resp)
+}
+
+// GetArticles handles GET /articles?ids=1,2,3.
+func (h *ArticleHandler) GetArticles(c *gin.Context) {
+	ids, err := parseIDs(c.Query("ids"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	articles, missing, err := h.service.GetArticles(c.Request.Context(), ids)
+	if err != nil {
+		logFailure(c, "get articles failed", err)
+		h.handleError(c, err)
+		return
+	}
+
+	resp := getArticlesResponse{Items: make([]articleResponse, 0, len(articles)), Missing: missing}
+	for _, article := range articles {
+		resp.Items = append(resp.Items, toResponse(article))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func parseIDs(raw string) ([]int64, error) {
+	if raw == "" {
+		return nil, domain.ErrInvalidIDs
+	}
+
+	parts := strings.Split(raw, ",")
+	if len(parts) > domain.MaxBatchSize {
+		return nil, domain.ErrInvalidIDs
+	}
+
+	ids := make([]int64, 0, len(parts))
+	for _, part := range parts {
+		id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			return nil, domain.ErrInvalidIDs
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,127 @@
+package httpadapter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"articles/internal/domain"
+)
+
+func saveManyWithIDs(_ context.Context, articles []domain.Article) ([]domain.Article, error) {
+	saved := make([]domain.Article, len(articles))
+	for i, article := range articles {
+		article.ID = int64(i + 1)
+		saved[i] = article
+	}
+	return saved, nil
+}
+
+func decodeBatch(t *testing.T, body []byte) batchCreateResponse {
+	t.Helper()
+
+	var resp batchCreateResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return resp
+}
+
+func TestBatchCreateArticles_AllCreated(t *testing.T) {
+	router := setupRouter(t, &stubRepo{saveManyFn: saveManyWithIDs})
+
+	rec := performRequest(router, http.MethodPost, "/articles:batchCreate", []byte(`{"items":[{"title":"One"},{"title":"Two"}]}`))
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, rec.Code)
+	}
+	resp := decodeBatch(t, rec.Body.Bytes())
+	if resp.Mode != domain.BatchAtomic || resp.Created != 2 || resp.Failed != 0 {
+		t.Fatalf("unexpected summary: %+v", resp)
+	}
+	if resp.Items[1].Status != batchItemCreated || resp.Items[1].Article == nil || resp.Items[1].Article.Title != "Two" {
+		t.Fatalf("unexpected item: %+v", resp.Items[1])
+	}
+}
+
+func TestBatchCreateArticles_AtomicFailure(t *testing.T) {
+	router := setupRouter(t, &stubRepo{})
+
+	rec := performRequest(router, http.MethodPost, "/articles:batchCreate", []byte(`{"items":[{"title":"One"},{"title":""}]}`))
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status %d, got %d", http.StatusUnprocessableEntity, rec.Code)
+	}
+	resp := decodeBatch(t, rec.Body.Bytes())
+	if resp.Items[0].Status != batchItemAborted || resp.Items[0].Error.Code != "batch.aborted" {
+		t.Fatalf("expected item 0 to be aborted, got %+v", resp.Items[0])
+	}
+	if resp.Items[1].Status != batchItemFailed || resp.Items[1].Error.Code != "article.title_required" {
+		t.Fatalf("expected item 1 to fail with article.title_required, got %+v", resp.Items[1])
+	}
+}
+
+func TestBatchCreateArticles_BestEffortPartial(t *testing.T) {
+	router := setupRouter(t, &stubRepo{saveManyFn: saveManyWithIDs})
+
+	rec := performRequest(router, http.MethodPost, "/articles:batchCreate",
+		[]byte(`{"mode":"best_effort","items":[{"title":"One"},{"title":"","author_id":"a b"}]}`))
+
+	if rec.Code != http.StatusMultiStatus {
+		t.Fatalf("expected status %d, got %d", http.StatusMultiStatus, rec.Code)
+	}
+	resp := decodeBatch(t, rec.Body.Bytes())
+	if resp.Created != 1 || resp.Failed != 1 {
+		t.Fatalf("unexpected summary: %+v", resp)
+	}
+	failed := resp.Items[1]
+	if failed.Error == nil || failed.Error.Code != CodeValidationFailed || len(failed.Error.Errors) != 2 {
+		t.Fatalf("expected a validation failure listing both fields, got %+v", failed.Error)
+	}
+}
+
+func TestBatchCreateArticles_InvalidMode(t *testing.T) {
+	router := setupRouter(t, &stubRepo{})
+
+	rec := performRequest(router, http.MethodPost, "/articles:batchCreate", []byte(`{"mode":"some","items":[{"title":"One"}]}`))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestGetArticles_Success(t *testing.T) {
+	router := setupRouter(t, &stubRepo{
+		getByIDsFn: func(_ context.Context, ids []int64) ([]domain.Article, error) {
+			return []domain.Article{{ID: 2, Title: "Two", Status: domain.StatusPublished}}, nil
+		},
+	})
+
+	rec := performRequest(router, http.MethodGet, "/articles?ids=2,%205", nil)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	var resp getArticlesResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Items) != 1 || resp.Items[0].ID != 2 {
+		t.Fatalf("expected article 2, got %+v", resp.Items)
+	}
+	if len(resp.Missing) != 1 || resp.Missing[0] != 5 {
+		t.Fatalf("expected 5 to be missing, got %v", resp.Missing)
+	}
+}
+
+func TestGetArticles_InvalidIDs(t *testing.T) {
+	router := setupRouter(t, &stubRepo{})
+
+	for _, query := range []string{"", "?ids=", "?ids=1,x", "?ids=1,-2"} {
+		rec := performRequest(router, http.MethodGet, "/articles"+query, nil)
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("expected status %d for %q, got %d", http.StatusBadRequest, query, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package httpadapter
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"articles/internal/domain"
+	"articles/internal/logging"
+)
+
+const (
+	problemContentType = "application/problem+json"
+
+	// ErrorFormatHeader set to "legacy" switches error bodies back to the
+	// original {"error": "..."} shape for clients that still parse it.
+	ErrorFormatHeader = "X-Error-Format"
+	legacyErrorFormat = "legacy"
+
+	problemTypePrefix = "/problems/"
+)
+
+// Stable codes that are not tied to a domain error.
+const (
+	CodeInternal         = "internal"
+	CodeInvalidBody      = "request.invalid_body"
+	CodeBodyTooLarge     = "request.body_too_large"
+	CodeIfMatchRequired  = "request.if_match_required"
+	CodeValidationFailed = "article.validation_failed"
+
+	CodeIdempotencyInvalidKey = "idempotency.invalid_key"
+	CodeIdempotencyKeyReused  = "idempotency.key_reused"
+	CodeIdempotencyInProgress = "idempotency.in_progress"
+
+	CodeUnauthenticated   = "auth.unauthenticated"
+	CodeInsufficientScope = "auth.insufficient_scope"
+
+	CodeRateLimited = "rate_limit.exceeded"
+)
+
+// Problem is an RFC 7807 problem details object. Code is the stable,
+// machine-readable identifier clients should branch on; Type is derived
+// from it.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError pins a validation failure to a request field.
+type FieldError struct {
+	Field  string `json:"field"`
+	Code   string `json:"code"`
+	Detail string `json:"detail"`
+}
+
+// NewProblem builds a problem for a failure without a domain error behind it.
+func NewProblem(status int, code, title, detail string) Problem {
+	return Problem{Status: status, Code: code, Title: title, Detail: detail}
+}
+
+// WriteProblem aborts the request with p, or with the legacy error body
+// when the client asked for it through ErrorFormatHeader.
+func WriteProblem(c *gin.Context, p Problem) {
+	writeProblem(c, p, func(p Problem) any { return p })
+}
+
+// writeProblem is WriteProblem for problems with extension members: extend
+// wraps the completed p in a body that adds them.
+func writeProblem(c *gin.Context, p Problem, extend func(Problem) any) {
+	if strings.EqualFold(c.GetHeader(ErrorFormatHeader), legacyErrorFormat) {
+		c.AbortWithStatusJSON(p.Status, gin.H{"error": p.Detail})
+		return
+	}
+
+	p.Type = problemTypePrefix + p.Code
+	p.Instance = c.Request.URL.Path
+	p.RequestID = logging.RequestID(c.Request.Context())
+
+	c.Header("Content-Type", problemContentType)
+	c.AbortWithStatusJSON(p.Status, extend(p))
+}
+
+type problemType struct {
+	err    error
+	status int
+	code   string
+	title  string
+	// field is the request field at fault for validation errors.
+	field string
+}
+
+// problemTypes maps every domain error to its stable code. Codes are part of
+// the API contract: add new ones freely, never rename existing ones.
+var problemTypes = []problemType{
+	{domain.ErrArticleNotFound, http.StatusNotFound, "article.not_found", "Article not found", ""},
+	{domain.ErrVersionConflict, http.StatusPreconditionFailed, "article.version_conflict", "Article was modified", ""},
+	{domain.ErrInvalidID, http.StatusBadRequest, "article.invalid_id", "Invalid article ID", "id"},
+	{domain.ErrInvalidTitle, http.StatusBadRequest, "article.title_required", "Title is required", "title"},
+	{domain.ErrTitleTooLong, http.StatusBadRequest, "article.title_too_long", "Title too long", "title"},
+	{domain.ErrBodyTooLong, http.StatusBadRequest, "article.body_too_long", "Body too long", "body"},
+	{domain.ErrSummaryTooLong, http.StatusBadRequest, "article.summary_too_long", "Summary too long", "summary"},
+	{domain.ErrInvalidAuthorID, http.StatusBadRequest, "article.invalid_author_id", "Invalid author ID", "author_id"},
+	{domain.ErrInvalidTag, http.StatusBadRequest, "article.invalid_tag", "Invalid tag", "tags"},
+	{domain.ErrTooManyTags, http.StatusBadRequest, "article.too_many_tags", "Too many tags", "tags"},
+	{domain.ErrInvalidTagFilter, http.StatusBadRequest, "tag.invalid_filter", "Invalid tag filter", "tag"},
+	{domain.ErrInvalidTagMatch, http.StatusBadRequest, "tag.invalid_match", "Invalid tag match", "tag_match"},
+	{domain.ErrInvalidCursor, http.StatusBadRequest, "pagination.invalid_cursor", "Invalid cursor", "cursor"},
+	{domain.ErrInvalidLimit, http.StatusBadRequest, "pagination.invalid_limit", "Invalid limit", "limit"},
+	{domain.ErrInvalidSearch, http.StatusBadRequest, "search.invalid_query", "Invalid search query", "q"},
+	{domain.ErrInvalidIDs, http.StatusBadRequest, "article.invalid_ids", "Invalid article IDs", "ids"},
+	{domain.ErrInvalidBatchSize, http.StatusBadRequest, "batch.invalid_size", "Invalid batch size", "items"},
+	{domain.ErrInvalidBatchMode, http.StatusBadRequest, "batch.invalid_mode", "Invalid batch mode", "mode"},
+	{domain.ErrBatchAborted, http.StatusUnprocessableEntity, "batch.aborted", "Batch aborted", ""},
+	{domain.ErrInvalidFormat, http.StatusBadRequest, "transfer.invalid_format", "Invalid format", "format"},
+	{domain.ErrInvalidRecord, http.StatusBadRequest, "import.invalid_record", "Invalid record", ""},
+	{domain.ErrInvalidCSVHeader, http.StatusBadRequest, "import.invalid_header", "Invalid CSV header", ""},
+	{domain.ErrRevisionNotFound, http.StatusNotFound, "revision.not_found", "Revision not found", ""},
+	{domain.ErrInvalidRevision, http.StatusBadRequest, "revision.invalid_number", "Invalid revision number", "rev"},
+	{domain.ErrInvalidDiffFormat, http.StatusBadRequest, "diff.invalid_format", "Invalid diff format", "format"},
+	{domain.ErrInvalidStatus, http.StatusBadRequest, "article.invalid_status", "Invalid status", "status"},
+	{domain.ErrStatusTransition, http.StatusConflict, "article.invalid_transition", "Invalid status transition", ""},
+	{domain.ErrInvalidPublishAt, http.StatusBadRequest, "article.invalid_publish_at", "Invalid publish_at", "publish_at"},
+	{domain.ErrForbidden, http.StatusForbidden, "auth.forbidden", "Forbidden", ""},
+}
+
+// problemFor translates err into a problem. Validation failures on several
+// fields at once (errors.Join) become one 400 listing each field.
+func problemFor(err error) Problem {
+	var (
+		matched []problemType
+		fields  []FieldError
+	)
+	for _, candidate := range problemTypes {
+		if !errors.Is(err, candidate.err) {
+			continue
+		}
+		matched = append(matched, candidate)
+		if candidate.field != "" {
+			fields = append(fields, FieldError{Field: candidate.field, Code: candidate.code, Detail: candidate.err.Error()})
+		}
+	}
+
+	switch {
+	case len(matched) == 0:
+		return NewProblem(http.StatusInternalServerError, CodeInternal, "Internal server error", "internal server error")
+	case len(fields) > 1:
+		details := make([]string, 0, len(fields))
+		for _, field := range fields {
+			details = append(details, field.Detail)
+		}
+		p := NewProblem(http.StatusBadRequest, CodeValidationFailed, "Validation failed", strings.Join(details, "; "))
+		p.Errors = fields
+		return p
+	default:
+		first := matched[0]
+		p := NewProblem(first.status, first.code, first.title, first.err.Error())
+		p.Errors = fields
+		return p
+	}
+}
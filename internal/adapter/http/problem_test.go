@@ -0,0 +1,187 @@
+package httpadapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"articles/internal/domain"
+)
+
+func decodeProblem(t *testing.T, body []byte) Problem {
+	t.Helper()
+
+	var problem Problem
+	if err := json.Unmarshal(body, &problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	return problem
+}
+
+func TestProblem_DomainErrorHasStableCode(t *testing.T) {
+	router := setupRouter(t, &stubRepo{
+		getByIDFn: func(context.Context, int64) (domain.Article, error) {
+			return domain.Article{}, domain.ErrArticleNotFound
+		},
+	})
+
+	rec := performRequest(router, http.MethodGet, "/article/42", nil)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/problem+json" {
+		t.Fatalf("expected problem+json content type, got %q", got)
+	}
+
+	problem := decodeProblem(t, rec.Body.Bytes())
+	want := Problem{
+		Type:     "/problems/article.not_found",
+		Title:    "Article not found",
+		Status:   http.StatusNotFound,
+		Detail:   domain.ErrArticleNotFound.Error(),
+		Instance: "/article/42",
+		Code:     "article.not_found",
+	}
+	if problem.Type != want.Type || problem.Title != want.Title || problem.Status != want.Status ||
+		problem.Detail != want.Detail || problem.Instance != want.Instance || problem.Code != want.Code {
+		t.Fatalf("expected %+v, got %+v", want, problem)
+	}
+}
+
+func TestProblem_FieldErrors(t *testing.T) {
+	router := setupRouter(t, &stubRepo{})
+
+	body := `{"title":"` + strings.Repeat("a", domain.MaxTitleLength+1) + `","author_id":"two words"}`
+	rec := performRequest(router, http.MethodPost, "/article", []byte(body))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+	problem := decodeProblem(t, rec.Body.Bytes())
+	if problem.Code != CodeValidationFailed {
+		t.Fatalf("expected code %q, got %q", CodeValidationFailed, problem.Code)
+	}
+	want := []FieldError{
+		{Field: "title", Code: "article.title_too_long", Detail: domain.ErrTitleTooLong.Error()},
+		{Field: "author_id", Code: "article.invalid_author_id", Detail: domain.ErrInvalidAuthorID.Error()},
+	}
+	if len(problem.Errors) != len(want) {
+		t.Fatalf("expected field errors %+v, got %+v", want, problem.Errors)
+	}
+	for i := range want {
+		if problem.Errors[i] != want[i] {
+			t.Fatalf("expected field error %+v, got %+v", want[i], problem.Errors[i])
+		}
+	}
+}
+
+func TestProblem_SingleFieldError(t *testing.T) {
+	router := setupRouter(t, &stubRepo{})
+
+	rec := performRequest(router, http.MethodGet, "/article?limit=500", nil)
+
+	problem := decodeProblem(t, rec.Body.Bytes())
+	if problem.Code != "pagination.invalid_limit" {
+		t.Fatalf("expected code pagination.invalid_limit, got %q", problem.Code)
+	}
+	if len(problem.Errors) != 1 || problem.Errors[0].Field != "limit" {
+		t.Fatalf("expected a single limit field error, got %+v", problem.Errors)
+	}
+}
+
+func TestProblem_InternalErrorHidesCause(t *testing.T) {
+	router := setupRouter(t, &stubRepo{
+		getByIDFn: func(context.Context, int64) (domain.Article, error) {
+			return domain.Article{}, errors.New("connection refused to 10.0.0.5")
+		},
+	})
+
+	rec := performRequest(router, http.MethodGet, "/article/1", nil)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status %d, got %d", http.StatusInternalServerError, rec.Code)
+	}
+	if strings.Contains(rec.Body.String(), "10.0.0.5") {
+		t.Fatalf("expected the cause to stay out of the response, got %s", rec.Body.String())
+	}
+	if problem := decodeProblem(t, rec.Body.Bytes()); problem.Code != CodeInternal {
+		t.Fatalf("expected code %q, got %q", CodeInternal, problem.Code)
+	}
+}
+
+func TestProblem_IfMatchRequired(t *testing.T) {
+	router := setupRouter(t, &stubRepo{})
+
+	rec := performRequest(router, http.MethodPatch, "/article/1", []byte(`{"title":"New"}`))
+
+	if rec.Code != http.StatusPreconditionRequired {
+		t.Fatalf("expected status %d, got %d", http.StatusPreconditionRequired, rec.Code)
+	}
+	if problem := decodeProblem(t, rec.Body.Bytes()); problem.Code != CodeIfMatchRequired {
+		t.Fatalf("expected code %q, got %q", CodeIfMatchRequired, problem.Code)
+	}
+}
+
+func TestProblem_BodyTooLarge(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, 16)
+		c.Next()
+	})
+	router.POST("/article", NewArticleHandler(nil).CreateArticle)
+
+	rec := performRequest(router, http.MethodPost, "/article", []byte(`{"title":"`+strings.Repeat("a", 64)+`"}`))
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected status %d, got %d", http.StatusRequestEntityTooLarge, rec.Code)
+	}
+	if problem := decodeProblem(t, rec.Body.Bytes()); problem.Code != CodeBodyTooLarge {
+		t.Fatalf("expected code %q, got %q", CodeBodyTooLarge, problem.Code)
+	}
+}
+
+func TestProblem_LegacyFormat(t *testing.T) {
+	router := setupRouter(t, &stubRepo{})
+
+	rec := performRequestWithHeaders(router, http.MethodPost, "/article", []byte(`{"title":"  "}`), map[string]string{
+		ErrorFormatHeader: "legacy",
+	})
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "application/json") {
+		t.Fatalf("expected application/json in legacy mode, got %q", got)
+	}
+	var body map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(body) != 1 || body["error"] != domain.ErrInvalidTitle.Error() {
+		t.Fatalf("expected legacy error body, got %v", body)
+	}
+}
+
+func TestProblem_ForbiddenIs403(t *testing.T) {
+	router := setupRouter(t, &stubRepo{
+		deleteFn: func(context.Context, int64) error {
+			return domain.ErrForbidden
+		},
+	})
+
+	rec := performRequest(router, http.MethodDelete, "/article/42", nil)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected status %d, got %d", http.StatusForbidden, rec.Code)
+	}
+	if problem := decodeProblem(t, rec.Body.Bytes()); problem.Code != "auth.forbidden" {
+		t.Fatalf("expected code auth.forbidden, got %q", problem.Code)
+	}
+}
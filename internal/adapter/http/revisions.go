@@ -0,0 +1,214 @@
+package httpadapter
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"articles/internal/domain"
+)
+
+// revisionSummaryResponse leaves out the body and summary, which
+// GET /article/:id/revisions/:rev returns.
+type revisionSummaryResponse struct {
+	Number    int64     `json:"number"`
+	Title     string    `json:"title"`
+	Slug      string    `json:"slug"`
+	Tags      []string  `json:"tags"`
+	ChangedBy string    `json:"changed_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type revisionResponse struct {
+	ArticleID int64     `json:"article_id"`
+	Number    int64     `json:"number"`
+	Title     string    `json:"title"`
+	Slug      string    `json:"slug"`
+	Body      string    `json:"body"`
+	Summary   string    `json:"summary"`
+	Tags      []string  `json:"tags"`
+	ChangedBy string    `json:"changed_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type listRevisionsResponse struct {
+	Items []revisionSummaryResponse `json:"items"`
+}
+
+type diffSpanResponse struct {
+	Op   domain.DiffOp `json:"op"`
+	Text string        `json:"text"`
+}
+
+type fieldDiffResponse struct {
+	Field   string             `json:"field"`
+	Unified string             `json:"unified,omitempty"`
+	Spans   []diffSpanResponse `json:"spans,omitempty"`
+}
+
+type revisionDiffResponse struct {
+	ArticleID int64               `json:"article_id"`
+	From      int64               `json:"from"`
+	To        int64               `json:"to"`
+	Format    domain.DiffFormat   `json:"format"`
+	Fields    []fieldDiffResponse `json:"fields"`
+}
+
+// ListRevisions handles GET /article/:id/revisions, oldest revision first.
+func (h *ArticleHandler) ListRevisions(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		h.handleError(c, domain.ErrInvalidID)
+		return
+	}
+
+	revisions, err := h.service.ListRevisions(c.Request.Context(), id)
+	if err != nil {
+		logFailure(c, "list revisions failed", err)
+		h.handleError(c, err)
+		return
+	}
+
+	resp := listRevisionsResponse{Items: make([]revisionSummaryResponse, 0, len(revisions))}
+	for _, revision := range revisions {
+		resp.Items = append(resp.Items, revisionSummaryResponse{
+			Number:    revision.Number,
+			Title:     revision.Title,
+			Slug:      revision.Slug,
+			Tags:      nonNilTags(revision.Tags),
+			ChangedBy: revision.ChangedBy,
+			CreatedAt: revision.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *ArticleHandler) GetRevision(c *gin.Context) {
+	id, number, ok := h.revisionParams(c)
+	if !ok {
+		return
+	}
+
+	revision, err := h.service.GetRevision(c.Request.Context(), id, number)
+	if err != nil {
+		logFailure(c, "get revision failed", err)
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, revisionResponse{
+		ArticleID: revision.ArticleID,
+		Number:    revision.Number,
+		Title:     revision.Title,
+		Slug:      revision.Slug,
+		Body:      revision.Body,
+		Summary:   revision.Summary,
+		Tags:      nonNilTags(revision.Tags),
+		ChangedBy: revision.ChangedBy,
+		CreatedAt: revision.CreatedAt,
+	})
+}
+
+// DiffRevisions handles GET /article/:id/revisions/diff. from and to default
+// to the revision before the current one and the current one; format is
+// unified (the default) or word.
+func (h *ArticleHandler) DiffRevisions(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		h.handleError(c, domain.ErrInvalidID)
+		return
+	}
+	from, err := queryRevision(c, "from")
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	to, err := queryRevision(c, "to")
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	diff, err := h.service.DiffRevisions(c.Request.Context(), id, from, to, domain.DiffFormat(c.Query("format")))
+	if err != nil {
+		logFailure(c, "diff revisions failed", err)
+		h.handleError(c, err)
+		return
+	}
+
+	resp := revisionDiffResponse{
+		ArticleID: diff.ArticleID,
+		From:      diff.From,
+		To:        diff.To,
+		Format:    diff.Format,
+		Fields:    make([]fieldDiffResponse, 0, len(diff.Fields)),
+	}
+	for _, field := range diff.Fields {
+		fieldResp := fieldDiffResponse{Field: field.Field, Unified: field.Unified}
+		for _, span := range field.Spans {
+			fieldResp.Spans = append(fieldResp.Spans, diffSpanResponse{Op: span.Op, Text: span.Text})
+		}
+		resp.Fields = append(resp.Fields, fieldResp)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RevertArticle handles POST /article/:id/revisions/:rev/revert: the content
+// of the revision becomes a new version of the article. Like PUT it needs
+// If-Match.
+func (h *ArticleHandler) RevertArticle(c *gin.Context) {
+	id, number, ok := h.revisionParams(c)
+	if !ok {
+		return
+	}
+	expectedVersion, ok := h.requireIfMatch(c)
+	if !ok {
+		return
+	}
+
+	article, err := h.service.RevertArticle(c.Request.Context(), id, number, expectedVersion)
+	if err != nil {
+		logFailure(c, "revert article failed", err)
+		h.handleError(c, err)
+		return
+	}
+
+	setETag(c, article)
+	c.JSON(http.StatusOK, toResponse(article))
+}
+
+// revisionParams parses the :id and :rev path parameters, answering the
+// request itself when either is not a number.
+func (h *ArticleHandler) revisionParams(c *gin.Context) (id, number int64, ok bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		h.handleError(c, domain.ErrInvalidID)
+		return 0, 0, false
+	}
+	number, err = strconv.ParseInt(c.Param("rev"), 10, 64)
+	if err != nil {
+		h.handleError(c, domain.ErrInvalidRevision)
+		return 0, 0, false
+	}
+	return id, number, true
+}
+
+// queryRevision parses an optional revision number query parameter; 0 lets
+// the service pick the default.
+func queryRevision(c *gin.Context, name string) (int64, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return 0, nil
+	}
+
+	number, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || number <= 0 {
+		return 0, domain.ErrInvalidRevision
+	}
+
+	return number, nil
+}
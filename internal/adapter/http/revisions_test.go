@@ -0,0 +1,180 @@
+package httpadapter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"articles/internal/domain"
+	"articles/internal/usecase"
+)
+
+type stubRevisionRepo struct {
+	revisions []domain.Revision
+}
+
+func (s *stubRevisionRepo) SaveRevisions(_ context.Context, revisions []domain.Revision) error {
+	s.revisions = append(s.revisions, revisions...)
+	return nil
+}
+
+func (s *stubRevisionRepo) ListRevisions(_ context.Context, _ int64) ([]domain.Revision, error) {
+	return s.revisions, nil
+}
+
+func (s *stubRevisionRepo) GetRevision(_ context.Context, _ int64, number int64) (domain.Revision, error) {
+	for _, revision := range s.revisions {
+		if revision.Number == number {
+			return revision, nil
+		}
+	}
+	return domain.Revision{}, domain.ErrRevisionNotFound
+}
+
+// revisionStore holds both revisions of the article revisionArticleRepo
+// serves at version 2.
+func revisionStore() *stubRevisionRepo {
+	return &stubRevisionRepo{revisions: []domain.Revision{
+		{ArticleID: 1, Number: 1, Title: "Hello", Body: "The quick fox"},
+		{ArticleID: 1, Number: 2, Title: "Hello", Body: "The slow fox", Tags: []string{"go"}},
+	}}
+}
+
+func revisionArticleRepo() *stubRepo {
+	current := domain.Article{ID: 1, Title: "Hello", Body: "The slow fox", Tags: []string{"go"}, Status: domain.StatusPublished, Version: 2}
+	return &stubRepo{
+		getByIDFn: func(_ context.Context, id int64) (domain.Article, error) {
+			if id != 1 {
+				return domain.Article{}, domain.ErrArticleNotFound
+			}
+			return current, nil
+		},
+		updateFn: func(_ context.Context, article domain.Article) (domain.Article, error) {
+			article.Version++
+			return article, nil
+		},
+	}
+}
+
+func TestListRevisions(t *testing.T) {
+	router := setupRouter(t, revisionArticleRepo(), usecase.WithRevisions(revisionStore()))
+
+	rec := performRequest(router, http.MethodGet, "/article/1/revisions", nil)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var resp listRevisionsResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Items) != 2 || resp.Items[0].Number != 1 || resp.Items[0].Tags == nil {
+		t.Fatalf("unexpected revisions: %+v", resp.Items)
+	}
+}
+
+func TestGetRevision(t *testing.T) {
+	router := setupRouter(t, revisionArticleRepo(), usecase.WithRevisions(revisionStore()))
+
+	rec := performRequest(router, http.MethodGet, "/article/1/revisions/1", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var resp revisionResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Number != 1 || resp.Body != "The quick fox" {
+		t.Fatalf("unexpected revision: %+v", resp)
+	}
+
+	for _, tc := range []struct {
+		path   string
+		status int
+		code   string
+	}{
+		{"/article/1/revisions/9", http.StatusNotFound, "revision.not_found"},
+		{"/article/1/revisions/first", http.StatusBadRequest, "revision.invalid_number"},
+		{"/article/2/revisions/1", http.StatusNotFound, "article.not_found"},
+	} {
+		rec := performRequest(router, http.MethodGet, tc.path, nil)
+		if rec.Code != tc.status {
+			t.Fatalf("%s: expected status %d, got %d", tc.path, tc.status, rec.Code)
+		}
+		if problem := decodeProblem(t, rec.Body.Bytes()); problem.Code != tc.code {
+			t.Fatalf("%s: expected code %q, got %q", tc.path, tc.code, problem.Code)
+		}
+	}
+}
+
+func TestDiffRevisions_Words(t *testing.T) {
+	router := setupRouter(t, revisionArticleRepo(), usecase.WithRevisions(revisionStore()))
+
+	rec := performRequest(router, http.MethodGet, "/article/1/revisions/diff?from=1&to=2&format=word", nil)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var resp revisionDiffResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Format != domain.DiffWords || len(resp.Fields) != 2 || resp.Fields[0].Field != "body" {
+		t.Fatalf("unexpected diff: %+v", resp)
+	}
+	spans := resp.Fields[0].Spans
+	if len(spans) != 4 || spans[1] != (diffSpanResponse{Op: domain.DiffDelete, Text: "quick"}) ||
+		spans[2] != (diffSpanResponse{Op: domain.DiffInsert, Text: "slow"}) {
+		t.Fatalf("unexpected body spans: %+v", spans)
+	}
+}
+
+func TestDiffRevisions_InvalidQuery(t *testing.T) {
+	router := setupRouter(t, revisionArticleRepo(), usecase.WithRevisions(revisionStore()))
+
+	for _, tc := range []struct {
+		query string
+		code  string
+	}{
+		{"?from=0", "revision.invalid_number"},
+		{"?to=two", "revision.invalid_number"},
+		{"?format=html", "diff.invalid_format"},
+	} {
+		rec := performRequest(router, http.MethodGet, "/article/1/revisions/diff"+tc.query, nil)
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected status %d, got %d", tc.query, http.StatusBadRequest, rec.Code)
+		}
+		if problem := decodeProblem(t, rec.Body.Bytes()); problem.Code != tc.code {
+			t.Fatalf("%s: expected code %q, got %q", tc.query, tc.code, problem.Code)
+		}
+	}
+}
+
+func TestRevertArticle(t *testing.T) {
+	revisions := revisionStore()
+	router := setupRouter(t, revisionArticleRepo(), usecase.WithRevisions(revisions))
+
+	rec := performRequest(router, http.MethodPost, "/article/1/revisions/1/revert", nil)
+	if rec.Code != http.StatusPreconditionRequired {
+		t.Fatalf("expected status %d without If-Match, got %d", http.StatusPreconditionRequired, rec.Code)
+	}
+
+	rec = performRequestWithHeaders(router, http.MethodPost, "/article/1/revisions/1/revert", nil, map[string]string{"If-Match": `"2"`})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	if etag := rec.Header().Get("ETag"); etag != `"3"` {
+		t.Fatalf("expected ETag %q, got %q", `"3"`, etag)
+	}
+	var resp articleResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Body != "The quick fox" || len(resp.Tags) != 0 {
+		t.Fatalf("expected the content of revision 1, got %+v", resp)
+	}
+	if last := revisions.revisions[len(revisions.revisions)-1]; last.Number != 3 || last.Body != "The quick fox" {
+		t.Fatalf("expected the revert to be recorded as revision 3, got %+v", last)
+	}
+}
@@ -0,0 +1,75 @@
+package httpadapter
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"articles/internal/domain"
+)
+
+func slugRepo() *stubRepo {
+	return &stubRepo{
+		getBySlugFn: func(_ context.Context, slug string) (domain.Article, error) {
+			if slug == "hello" || slug == "old-hello" {
+				return domain.Article{ID: 1, Title: "Hello", Slug: "hello", Status: domain.StatusPublished, Version: 2}, nil
+			}
+			if slug == "東京" || slug == "旧-東京" {
+				return domain.Article{ID: 2, Title: "東京", Slug: "東京", Status: domain.StatusPublished, Version: 1}, nil
+			}
+			return domain.Article{}, domain.ErrArticleNotFound
+		},
+	}
+}
+
+func TestGetArticleBySlug_Current(t *testing.T) {
+	router := setupRouter(t, slugRepo())
+
+	rec := performRequest(router, http.MethodGet, "/article/by-slug/hello", nil)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if etag := rec.Header().Get("ETag"); etag != `"2"` {
+		t.Fatalf("expected ETag %q, got %q", `"2"`, etag)
+	}
+}
+
+func TestGetArticleBySlug_RedirectsFormerSlug(t *testing.T) {
+	router := setupRouter(t, slugRepo())
+
+	rec := performRequest(router, http.MethodGet, "/article/by-slug/old-hello", nil)
+
+	if rec.Code != http.StatusMovedPermanently {
+		t.Fatalf("expected status %d, got %d", http.StatusMovedPermanently, rec.Code)
+	}
+	if location := rec.Header().Get("Location"); location != "/article/by-slug/hello" {
+		t.Fatalf("expected redirect to /article/by-slug/hello, got %q", location)
+	}
+}
+
+func TestGetArticleBySlug_PercentEncodedSlugs(t *testing.T) {
+	router := setupRouter(t, slugRepo())
+
+	if rec := performRequest(router, http.MethodGet, "/article/by-slug/%E6%9D%B1%E4%BA%AC", nil); rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	rec := performRequest(router, http.MethodGet, "/article/by-slug/%E6%97%A7-%E6%9D%B1%E4%BA%AC", nil)
+	if rec.Code != http.StatusMovedPermanently {
+		t.Fatalf("expected status %d, got %d", http.StatusMovedPermanently, rec.Code)
+	}
+	if location := rec.Header().Get("Location"); location != "/article/by-slug/%E6%9D%B1%E4%BA%AC" {
+		t.Fatalf("expected a percent-encoded redirect, got %q", location)
+	}
+}
+
+func TestGetArticleBySlug_NotFound(t *testing.T) {
+	router := setupRouter(t, slugRepo())
+
+	rec := performRequest(router, http.MethodGet, "/article/by-slug/missing", nil)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
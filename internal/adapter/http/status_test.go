@@ -0,0 +1,129 @@
+package httpadapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"articles/internal/domain"
+)
+
+func TestGetArticle_DraftIsHidden(t *testing.T) {
+	router := setupRouter(t, &stubRepo{
+		getByIDFn: func(_ context.Context, _ int64) (domain.Article, error) {
+			return domain.Article{ID: 2, Title: "Hello", Status: domain.StatusDraft, Version: 1}, nil
+		},
+	})
+
+	rec := performRequest(router, http.MethodGet, "/article/2", nil)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestCreateArticle_PublishedIsRejected(t *testing.T) {
+	router := setupRouter(t, &stubRepo{})
+
+	rec := performRequest(router, http.MethodPost, "/article", []byte(`{"title":"Hello","status":"published"}`))
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected status %d, got %d", http.StatusConflict, rec.Code)
+	}
+	if !bytes.Contains(rec.Body.Bytes(), []byte("article.invalid_transition")) {
+		t.Fatalf("expected invalid transition code, got %s", rec.Body.String())
+	}
+}
+
+func TestPatchArticle_Publish(t *testing.T) {
+	router := setupRouter(t, updateStubRepo(t, domain.Article{ID: 2, Title: "Hello", Status: domain.StatusInReview, Version: 1}))
+
+	rec := performRequestWithHeaders(router, http.MethodPatch, "/article/2", []byte(`{"status":"published"}`), map[string]string{"If-Match": `"1"`})
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var resp articleResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Status != "published" || resp.PublishAt == nil {
+		t.Fatalf("expected a published article with publish_at, got %+v", resp)
+	}
+}
+
+func TestPatchArticle_InvalidTransition(t *testing.T) {
+	router := setupRouter(t, updateStubRepo(t, domain.Article{ID: 2, Title: "Hello", Status: domain.StatusDraft, Version: 1}))
+
+	rec := performRequestWithHeaders(router, http.MethodPatch, "/article/2", []byte(`{"status":"published"}`), map[string]string{"If-Match": `"1"`})
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected status %d, got %d", http.StatusConflict, rec.Code)
+	}
+}
+
+func TestPatchArticle_InvalidStatus(t *testing.T) {
+	router := setupRouter(t, updateStubRepo(t, domain.Article{ID: 2, Title: "Hello", Status: domain.StatusDraft, Version: 1}))
+
+	rec := performRequestWithHeaders(router, http.MethodPatch, "/article/2", []byte(`{"status":"live"}`), map[string]string{"If-Match": `"1"`})
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+	if !bytes.Contains(rec.Body.Bytes(), []byte("article.invalid_status")) {
+		t.Fatalf("expected invalid status code, got %s", rec.Body.String())
+	}
+}
+
+func TestPatchArticle_PublishAt(t *testing.T) {
+	scheduled := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	current := domain.Article{ID: 2, Title: "Hello", Status: domain.StatusInReview, PublishAt: &scheduled, Version: 1}
+
+	for _, tc := range []struct {
+		name string
+		body string
+		want *time.Time
+	}{
+		{"missing keeps it", `{}`, &scheduled},
+		{"null clears it", `{"publish_at":null}`, nil},
+		{"time replaces it", `{"publish_at":"2031-01-01T00:00:00+02:00"}`, ptr(time.Date(2030, 12, 31, 22, 0, 0, 0, time.UTC))},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			router := setupRouter(t, updateStubRepo(t, current))
+
+			rec := performRequestWithHeaders(router, http.MethodPatch, "/article/2", []byte(tc.body), map[string]string{"If-Match": `"1"`})
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+			}
+			var resp articleResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if (resp.PublishAt == nil) != (tc.want == nil) || (tc.want != nil && !resp.PublishAt.Equal(*tc.want)) {
+				t.Fatalf("expected publish_at %v, got %v", tc.want, resp.PublishAt)
+			}
+		})
+	}
+}
+
+func TestPatchArticle_PublishAtOnPublished(t *testing.T) {
+	router := setupRouter(t, updateStubRepo(t, domain.Article{ID: 2, Title: "Hello", Status: domain.StatusPublished, Version: 1}))
+
+	rec := performRequestWithHeaders(router, http.MethodPatch, "/article/2", []byte(`{"publish_at":"2031-01-01T00:00:00Z"}`), map[string]string{"If-Match": `"1"`})
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+	if !bytes.Contains(rec.Body.Bytes(), []byte("article.invalid_publish_at")) {
+		t.Fatalf("expected invalid publish_at code, got %s", rec.Body.String())
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
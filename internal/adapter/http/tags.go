@@ -0,0 +1,34 @@
+package httpadapter
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type tagResponse struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+type listTagsResponse struct {
+	Items []tagResponse `json:"items"`
+}
+
+// ListTags handles GET /tags: every tag in use with the number of articles
+// carrying it, most used first.
+func (h *ArticleHandler) ListTags(c *gin.Context) {
+	tags, err := h.service.ListTags(c.Request.Context())
+	if err != nil {
+		logFailure(c, "list tags failed", err)
+		h.handleError(c, err)
+		return
+	}
+
+	resp := listTagsResponse{Items: make([]tagResponse, 0, len(tags))}
+	for _, tag := range tags {
+		resp.Items = append(resp.Items, tagResponse{Name: tag.Name, Count: tag.Count})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
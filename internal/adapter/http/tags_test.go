@@ -0,0 +1,99 @@
+package httpadapter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+
+	"articles/internal/domain"
+)
+
+func TestCreateArticle_NormalizesTags(t *testing.T) {
+	router := setupRouter(t, &stubRepo{
+		saveFn: func(_ context.Context, article domain.Article) (domain.Article, error) {
+			article.ID = 1
+			return article, nil
+		},
+	})
+
+	rec := performRequest(router, http.MethodPost, "/article", []byte(`{"title":"Hello","tags":["Go","db"," go "]}`))
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, rec.Code)
+	}
+	var resp articleResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if !slices.Equal(resp.Tags, []string{"db", "go"}) {
+		t.Fatalf("expected tags [db go], got %v", resp.Tags)
+	}
+}
+
+func TestCreateArticle_InvalidTag(t *testing.T) {
+	router := setupRouter(t, &stubRepo{})
+
+	rec := performRequest(router, http.MethodPost, "/article", []byte(`{"title":"Hello","tags":["not valid"]}`))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+	if problem := decodeProblem(t, rec.Body.Bytes()); problem.Code != "article.invalid_tag" || problem.Errors[0].Field != "tags" {
+		t.Fatalf("expected article.invalid_tag on tags, got %+v", problem)
+	}
+}
+
+func TestListArticles_TagFilter(t *testing.T) {
+	var got domain.TagFilter
+	router := setupRouter(t, &stubRepo{
+		listFn: func(_ context.Context, query domain.ListArticlesQuery) ([]domain.Article, error) {
+			got = query.Tags
+			return []domain.Article{}, nil
+		},
+	})
+
+	rec := performRequest(router, http.MethodGet, "/article?tag=go&tag=DB&tag_match=any", nil)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if !slices.Equal(got.Tags, []string{"db", "go"}) || got.Match != domain.TagMatchAny {
+		t.Fatalf("unexpected tag filter: %+v", got)
+	}
+}
+
+func TestListArticles_InvalidTagMatch(t *testing.T) {
+	router := setupRouter(t, &stubRepo{})
+
+	rec := performRequest(router, http.MethodGet, "/article?tag=go&tag_match=some", nil)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+	if problem := decodeProblem(t, rec.Body.Bytes()); problem.Code != "tag.invalid_match" {
+		t.Fatalf("expected tag.invalid_match, got %+v", problem)
+	}
+}
+
+func TestListTags(t *testing.T) {
+	router := setupRouter(t, &stubRepo{
+		listTagsFn: func(context.Context, domain.ArticleStatus) ([]domain.TagCount, error) {
+			return []domain.TagCount{{Name: "go", Count: 3}, {Name: "db", Count: 1}}, nil
+		},
+	})
+
+	rec := performRequest(router, http.MethodGet, "/tags", nil)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	var resp listTagsResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Items) != 2 || resp.Items[0] != (tagResponse{Name: "go", Count: 3}) {
+		t.Fatalf("unexpected tags: %+v", resp.Items)
+	}
+}
@@ -0,0 +1,131 @@
+package httpadapter
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"articles/internal/domain"
+	"articles/internal/transfer"
+)
+
+type importLineErrorResponse struct {
+	Line   int          `json:"line"`
+	Code   string       `json:"code"`
+	Detail string       `json:"detail"`
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+type importResponse struct {
+	Imported int                       `json:"imported"`
+	Failed   int                       `json:"failed"`
+	Errors   []importLineErrorResponse `json:"errors"`
+}
+
+// importProblem reports an import that stopped part way. The records
+// imported before it stopped stay, so the problem carries what the import
+// response would have said so far.
+type importProblem struct {
+	Problem
+	Import importResponse `json:"import"`
+}
+
+func newImportResponse(result domain.ImportResult) importResponse {
+	resp := importResponse{
+		Imported: result.Imported,
+		Failed:   result.Failed,
+		Errors:   make([]importLineErrorResponse, 0, len(result.Errors)),
+	}
+	for _, lineErr := range result.Errors {
+		problem := problemFor(lineErr.Err)
+		resp.Errors = append(resp.Errors, importLineErrorResponse{
+			Line:   lineErr.Line,
+			Code:   problem.Code,
+			Detail: problem.Detail,
+			Errors: problem.Errors,
+		})
+	}
+	return resp
+}
+
+// ExportArticles handles GET /article/export?format=ndjson|csv. Articles are
+// written a page at a time as they are read.
+func (h *ArticleHandler) ExportArticles(c *gin.Context) {
+	format, err := transfer.ParseFormat(c.Query("format"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", `attachment; filename="articles.`+string(format)+`"`)
+	c.Status(http.StatusOK)
+
+	encoder := transfer.NewEncoder(c.Writer, format)
+	_, err = h.service.ExportArticles(c.Request.Context(), func(page []domain.Article) error {
+		for _, article := range page {
+			if err := encoder.Encode(article); err != nil {
+				return err
+			}
+		}
+		if err := encoder.Flush(); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	if err == nil {
+		err = encoder.Flush()
+	}
+	if err != nil {
+		logFailure(c, "export articles failed", err)
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Disposition")
+			h.handleError(c, err)
+			return
+		}
+		// The status line is gone; dropping the connection is the only way
+		// left to tell the client the file is incomplete.
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// ImportArticles handles POST /article/import?format=ndjson|csv. Every
+// valid record is created; the others are listed by line. The response is
+// 200 when nothing failed, 207 when some records failed and 422 when none
+// was imported. An import that stops part way answers with a problem that
+// still carries the counts and line errors.
+func (h *ArticleHandler) ImportArticles(c *gin.Context) {
+	format, err := transfer.ParseFormat(c.Query("format"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	result, err := h.service.ImportArticles(c.Request.Context(), transfer.NewDecoder(c.Request.Body, format))
+	resp := newImportResponse(result)
+	if err != nil {
+		logFailure(c, "import articles failed", err)
+
+		var problem Problem
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			problem = NewProblem(http.StatusRequestEntityTooLarge, CodeBodyTooLarge, "Request body too large", "request body too large")
+		} else {
+			problem = problemFor(err)
+			h.observeErrors(problem.Code)
+		}
+		writeProblem(c, problem, func(p Problem) any { return importProblem{Problem: p, Import: resp} })
+		return
+	}
+
+	status := http.StatusOK
+	switch {
+	case result.Failed > 0 && result.Imported > 0:
+		status = http.StatusMultiStatus
+	case result.Failed > 0:
+		status = http.StatusUnprocessableEntity
+	}
+	c.JSON(status, resp)
+}
@@ -0,0 +1,162 @@
+package httpadapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"articles/internal/domain"
+)
+
+func TestExportArticles_NDJSON(t *testing.T) {
+	router := setupRouter(t, &stubRepo{
+		listFn: func(_ context.Context, query domain.ListArticlesQuery) ([]domain.Article, error) {
+			if query.After != nil {
+				t.Fatalf("expected a single page, got query %+v", query)
+			}
+			return []domain.Article{{ID: 2, Title: "Two"}, {ID: 1, Title: "One"}}, nil
+		},
+	})
+
+	rec := performRequest(router, http.MethodGet, "/article/export", nil)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/x-ndjson" {
+		t.Fatalf("expected NDJSON content type, got %q", got)
+	}
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"title":"Two"`) {
+		t.Fatalf("expected one line per article, got %q", rec.Body.String())
+	}
+}
+
+func TestExportArticles_CSV(t *testing.T) {
+	router := setupRouter(t, &stubRepo{
+		listFn: func(context.Context, domain.ListArticlesQuery) ([]domain.Article, error) {
+			return []domain.Article{{ID: 1, Title: "One, with comma"}}, nil
+		},
+	})
+
+	rec := performRequest(router, http.MethodGet, "/article/export?format=csv", nil)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/csv") {
+		t.Fatalf("expected CSV content type, got %q", rec.Header().Get("Content-Type"))
+	}
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "id,title,") || !strings.Contains(lines[1], `"One, with comma"`) {
+		t.Fatalf("expected a header and one row, got %q", rec.Body.String())
+	}
+}
+
+func TestExportArticles_ErrorBeforeFirstRow(t *testing.T) {
+	router := setupRouter(t, &stubRepo{
+		listFn: func(context.Context, domain.ListArticlesQuery) ([]domain.Article, error) {
+			return nil, errors.New("db down")
+		},
+	})
+
+	rec := performRequest(router, http.MethodGet, "/article/export", nil)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status %d, got %d", http.StatusInternalServerError, rec.Code)
+	}
+	if rec.Header().Get("Content-Disposition") != "" {
+		t.Fatalf("did not expect an attachment for an error, got %q", rec.Header().Get("Content-Disposition"))
+	}
+}
+
+func TestExportArticles_InvalidFormat(t *testing.T) {
+	router := setupRouter(t, &stubRepo{})
+
+	rec := performRequest(router, http.MethodGet, "/article/export?format=xml", nil)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestImportArticles_PartialFailure(t *testing.T) {
+	router := setupRouter(t, &stubRepo{
+		saveManyFn: func(_ context.Context, articles []domain.Article) ([]domain.Article, error) {
+			return articles, nil
+		},
+	})
+
+	body := "{\"title\":\"One\"}\n{\"title\":\"\"}\n{broken\n"
+	rec := performRequest(router, http.MethodPost, "/article/import", []byte(body))
+
+	if rec.Code != http.StatusMultiStatus {
+		t.Fatalf("expected status %d, got %d", http.StatusMultiStatus, rec.Code)
+	}
+	var resp importResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Imported != 1 || resp.Failed != 2 {
+		t.Fatalf("expected 1 imported and 2 failed, got %+v", resp)
+	}
+	if resp.Errors[0].Line != 2 || resp.Errors[0].Code != "article.title_required" {
+		t.Fatalf("unexpected first error: %+v", resp.Errors[0])
+	}
+	if resp.Errors[1].Line != 3 || resp.Errors[1].Code != "import.invalid_record" {
+		t.Fatalf("unexpected second error: %+v", resp.Errors[1])
+	}
+}
+
+func TestImportArticles_FailurePartWayReportsProgress(t *testing.T) {
+	calls := 0
+	router := setupRouter(t, &stubRepo{
+		saveManyFn: func(_ context.Context, articles []domain.Article) ([]domain.Article, error) {
+			calls++
+			if calls > 1 {
+				return nil, errors.New("db down")
+			}
+			return articles, nil
+		},
+	})
+
+	var body strings.Builder
+	body.WriteString("{\"title\":\"\"}\n")
+	for range domain.MaxBatchSize + 1 {
+		body.WriteString("{\"title\":\"One\"}\n")
+	}
+	rec := performRequest(router, http.MethodPost, "/article/import", []byte(body.String()))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status %d, got %d", http.StatusInternalServerError, rec.Code)
+	}
+	var resp struct {
+		Code   string         `json:"code"`
+		Import importResponse `json:"import"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Code != CodeInternal || resp.Import.Imported != domain.MaxBatchSize || resp.Import.Failed != 1 {
+		t.Fatalf("expected the first batch and the invalid line to be reported, got %+v", resp)
+	}
+	if len(resp.Import.Errors) != 1 || resp.Import.Errors[0].Line != 1 {
+		t.Fatalf("unexpected line errors: %+v", resp.Import.Errors)
+	}
+}
+
+func TestImportArticles_CSVWithoutTitleColumn(t *testing.T) {
+	router := setupRouter(t, &stubRepo{})
+
+	rec := performRequest(router, http.MethodPost, "/article/import?format=csv", []byte("name\nx\n"))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "import.invalid_header") {
+		t.Fatalf("expected import.invalid_header, got %s", rec.Body.String())
+	}
+}
@@ -0,0 +1,129 @@
+package gormstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"articles/internal/auth"
+)
+
+type APIKeyStore struct {
+	db           *gorm.DB
+	dialect      Dialect
+	queryTimeout time.Duration
+}
+
+func NewAPIKeyStore(db *gorm.DB, dialect Dialect, queryTimeout time.Duration) *APIKeyStore {
+	return &APIKeyStore{db: db, dialect: dialect, queryTimeout: queryTimeout}
+}
+
+func (s *APIKeyStore) CreateKey(ctx context.Context, key auth.APIKey) (auth.APIKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	model := newAPIKeyModel(key, s.dialect)
+	if err := s.db.WithContext(ctx).Create(&model).Error; err != nil {
+		return auth.APIKey{}, fmt.Errorf("create api key: %w", err)
+	}
+	return model.toAuth(), nil
+}
+
+func (s *APIKeyStore) GetKeyByHash(ctx context.Context, hash string) (auth.APIKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	var model apiKeyModel
+	err := s.db.WithContext(ctx).First(&model, "key_hash = ?", hash).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return auth.APIKey{}, auth.ErrKeyNotFound
+	case err != nil:
+		return auth.APIKey{}, fmt.Errorf("get api key: %w", err)
+	}
+	return model.toAuth(), nil
+}
+
+func (s *APIKeyStore) ListKeys(ctx context.Context) ([]auth.APIKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	var models []apiKeyModel
+	if err := s.db.WithContext(ctx).Order("id").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("list api keys: %w", err)
+	}
+
+	keys := make([]auth.APIKey, 0, len(models))
+	for _, model := range models {
+		keys = append(keys, model.toAuth())
+	}
+	return keys, nil
+}
+
+func (s *APIKeyStore) RevokeKey(ctx context.Context, id int64, at time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	db := s.db.WithContext(ctx)
+	result := db.Model(&apiKeyModel{}).Where("id = ? AND revoked_at IS NULL", id).Update("revoked_at", s.dialect.time(at))
+	if result.Error != nil {
+		return fmt.Errorf("revoke api key %d: %w", id, result.Error)
+	}
+	if result.RowsAffected == 1 {
+		return nil
+	}
+
+	// Nothing changed: either the key is already revoked or there is none.
+	var count int64
+	if err := db.Model(&apiKeyModel{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return fmt.Errorf("get api key %d: %w", id, err)
+	}
+	if count == 0 {
+		return auth.ErrKeyNotFound
+	}
+	return nil
+}
+
+type apiKeyModel struct {
+	ID     int64  `gorm:"column:id;primaryKey"`
+	Name   string `gorm:"column:name"`
+	Prefix string `gorm:"column:prefix"`
+	Hash   string `gorm:"column:key_hash"`
+	// Scopes is space separated.
+	Scopes    string     `gorm:"column:scopes"`
+	CreatedAt time.Time  `gorm:"column:created_at"`
+	RevokedAt *time.Time `gorm:"column:revoked_at"`
+}
+
+func (apiKeyModel) TableName() string { return "api_keys" }
+
+func newAPIKeyModel(key auth.APIKey, dialect Dialect) apiKeyModel {
+	return apiKeyModel{
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Hash:      key.Hash,
+		Scopes:    auth.FormatScopes(key.Scopes),
+		CreatedAt: dialect.time(key.CreatedAt),
+		RevokedAt: dialect.timeOrNil(key.RevokedAt),
+	}
+}
+
+func (m apiKeyModel) toAuth() auth.APIKey {
+	var scopes []auth.Scope
+	for _, scope := range strings.Fields(m.Scopes) {
+		scopes = append(scopes, auth.Scope(scope))
+	}
+	return auth.APIKey{
+		ID:        m.ID,
+		Name:      m.Name,
+		Prefix:    m.Prefix,
+		Hash:      m.Hash,
+		Scopes:    scopes,
+		CreatedAt: m.CreatedAt,
+		RevokedAt: m.RevokedAt,
+	}
+}
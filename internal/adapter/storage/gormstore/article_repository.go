@@ -0,0 +1,395 @@
+package gormstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"articles/internal/domain"
+)
+
+// ArticleRepository stores articles, their tags and slugs. Updates and
+// restores read the row back in their transaction rather than through
+// RETURNING, so both databases take the same path.
+type ArticleRepository struct {
+	db           *gorm.DB
+	dialect      Dialect
+	queryTimeout time.Duration
+}
+
+func NewArticleRepository(db *gorm.DB, dialect Dialect, queryTimeout time.Duration) *ArticleRepository {
+	return &ArticleRepository{db: db, dialect: dialect, queryTimeout: queryTimeout}
+}
+
+// DB returns the database handle, for adapters adding queries of their own.
+func (r *ArticleRepository) DB() *gorm.DB {
+	return r.db
+}
+
+func (r *ArticleRepository) Save(ctx context.Context, article domain.Article) (_ domain.Article, err error) {
+	ctx, end := r.Begin(ctx, "Save", "INSERT")
+	defer end(&err)
+
+	model := newArticleModel(article, r.dialect)
+
+	err = conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// The slug is set once it has been claimed, which needs the ID.
+		if err := tx.Omit("slug").Create(&model).Error; err != nil {
+			return fmt.Errorf("create article: %w", err)
+		}
+		slug, err := AssignSlug(tx, model.ID, article.BaseSlug())
+		if err != nil {
+			return err
+		}
+		model.Slug = slug
+		return ReplaceTags(tx, map[int64][]string{model.ID: article.Tags})
+	})
+	if err != nil {
+		return domain.Article{}, err
+	}
+
+	saved := model.ToDomain()
+	saved.Tags = article.Tags
+	return saved, nil
+}
+
+func (r *ArticleRepository) SaveMany(ctx context.Context, articles []domain.Article) (_ []domain.Article, err error) {
+	ctx, end := r.Begin(ctx, "SaveMany", "INSERT")
+	defer end(&err)
+
+	if len(articles) == 0 {
+		return []domain.Article{}, nil
+	}
+
+	models := make([]ArticleModel, 0, len(articles))
+	for _, article := range articles {
+		models = append(models, newArticleModel(article, r.dialect))
+	}
+
+	err = conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("slug").CreateInBatches(&models, InsertBatchSize).Error; err != nil {
+			return fmt.Errorf("create %d articles: %w", len(articles), err)
+		}
+		tags := make(map[int64][]string, len(models))
+		for i := range models {
+			slug, err := AssignSlug(tx, models[i].ID, articles[i].BaseSlug())
+			if err != nil {
+				return err
+			}
+			models[i].Slug = slug
+			tags[models[i].ID] = articles[i].Tags
+		}
+		return ReplaceTags(tx, tags)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	saved := make([]domain.Article, 0, len(models))
+	for i, model := range models {
+		article := model.ToDomain()
+		article.Tags = articles[i].Tags
+		saved = append(saved, article)
+	}
+
+	return saved, nil
+}
+
+func (r *ArticleRepository) GetByID(ctx context.Context, id int64) (_ domain.Article, err error) {
+	ctx, end := r.Begin(ctx, "GetByID", "SELECT")
+	defer end(&err)
+
+	return getByID(conn(ctx, r.db), id)
+}
+
+func (r *ArticleRepository) GetByIDs(ctx context.Context, ids []int64) (_ []domain.Article, err error) {
+	ctx, end := r.Begin(ctx, "GetByIDs", "SELECT")
+	defer end(&err)
+
+	if len(ids) == 0 {
+		return []domain.Article{}, nil
+	}
+
+	var models []ArticleModel
+	if err := conn(ctx, r.db).Where("id IN ?", ids).Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("get articles by id: %w", err)
+	}
+
+	return withTags(conn(ctx, r.db), models)
+}
+
+func (r *ArticleRepository) GetBySlug(ctx context.Context, slug string) (_ domain.Article, err error) {
+	ctx, end := r.Begin(ctx, "GetBySlug", "SELECT")
+	defer end(&err)
+
+	var model ArticleModel
+	err = conn(ctx, r.db).Scopes(BySlug(slug)).First(&model).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return domain.Article{}, domain.ErrArticleNotFound
+	case err != nil:
+		return domain.Article{}, fmt.Errorf("get article by slug %q: %w", slug, err)
+	}
+
+	articles, err := withTags(conn(ctx, r.db), []ArticleModel{model})
+	if err != nil {
+		return domain.Article{}, err
+	}
+	return articles[0], nil
+}
+
+func (r *ArticleRepository) List(ctx context.Context, query domain.ListArticlesQuery) (_ []domain.Article, err error) {
+	ctx, end := r.Begin(ctx, "List", "SELECT")
+	defer end(&err)
+
+	tx := conn(ctx, r.db).Order("created_at DESC, id DESC").Limit(query.Limit)
+	if query.After != nil {
+		tx = tx.Where("(created_at, id) < (?, ?)", r.dialect.time(query.After.CreatedAt), query.After.ID)
+	}
+	if len(query.Tags.Tags) > 0 {
+		tx = tx.Where("id IN (?)", TaggedArticles(r.db, query.Tags))
+	}
+	switch {
+	case query.Status != "" && query.Owner != "":
+		tx = tx.Where("(status = ? OR author_id = ?)", query.Status, query.Owner)
+	case query.Status != "":
+		tx = tx.Where("status = ?", query.Status)
+	}
+
+	var models []ArticleModel
+	if err := tx.Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("list articles: %w", err)
+	}
+
+	return withTags(conn(ctx, r.db), models)
+}
+
+func (r *ArticleRepository) ListScheduled(ctx context.Context, due time.Time, limit int) (_ []domain.Article, err error) {
+	ctx, end := r.Begin(ctx, "ListScheduled", "SELECT")
+	defer end(&err)
+
+	var models []ArticleModel
+	err = conn(ctx, r.db).
+		Where("status = ? AND publish_at IS NOT NULL AND publish_at <= ?", domain.StatusInReview, r.dialect.time(due)).
+		Order("publish_at, id").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, fmt.Errorf("list scheduled articles: %w", err)
+	}
+
+	return withTags(conn(ctx, r.db), models)
+}
+
+func (r *ArticleRepository) Update(ctx context.Context, article domain.Article) (_ domain.Article, err error) {
+	ctx, end := r.Begin(ctx, "Update", "UPDATE")
+	defer end(&err)
+
+	var updated domain.Article
+	err = conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&ArticleModel{}).
+			Where("id = ? AND version = ?", article.ID, article.Version).
+			Updates(map[string]any{
+				"title":      article.Title,
+				"body":       article.Body,
+				"summary":    article.Summary,
+				"status":     statusOrDraft(article.Status),
+				"publish_at": r.dialect.timeOrNil(article.PublishAt),
+				"version":    gorm.Expr("version + 1"),
+				"updated_at": r.dialect.time(time.Now()),
+			})
+		if result.Error != nil {
+			return fmt.Errorf("update article %d: %w", article.ID, result.Error)
+		}
+		if result.RowsAffected > 0 {
+			if _, err := AssignSlug(tx, article.ID, article.BaseSlug()); err != nil {
+				return err
+			}
+			if err := ReplaceTags(tx, map[int64][]string{article.ID: article.Tags}); err != nil {
+				return err
+			}
+		}
+
+		// Either the row is gone or someone bumped the version first.
+		current, err := getByID(tx, article.ID)
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrVersionConflict
+		}
+
+		updated = current
+		return nil
+	})
+	if err != nil {
+		return domain.Article{}, err
+	}
+
+	return updated, nil
+}
+
+func (r *ArticleRepository) Delete(ctx context.Context, id int64) (err error) {
+	ctx, end := r.Begin(ctx, "Delete", "UPDATE")
+	defer end(&err)
+
+	result := conn(ctx, r.db).Delete(&ArticleModel{}, "id = ?", id)
+	if result.Error != nil {
+		return fmt.Errorf("delete article %d: %w", id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrArticleNotFound
+	}
+
+	return nil
+}
+
+func (r *ArticleRepository) Restore(ctx context.Context, id int64) (_ domain.Article, err error) {
+	ctx, end := r.Begin(ctx, "Restore", "UPDATE")
+	defer end(&err)
+
+	var restored domain.Article
+	err = conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().
+			Model(&ArticleModel{}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Updates(map[string]any{
+				"deleted_at": nil,
+				"version":    gorm.Expr("version + 1"),
+				"updated_at": r.dialect.time(time.Now()),
+			})
+		if result.Error != nil {
+			return fmt.Errorf("restore article %d: %w", id, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrArticleNotFound
+		}
+
+		article, err := getByID(tx, id)
+		if err != nil {
+			return err
+		}
+
+		restored = article
+		return nil
+	})
+	if err != nil {
+		return domain.Article{}, err
+	}
+
+	return restored, nil
+}
+
+func (r *ArticleRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (_ int64, err error) {
+	ctx, end := r.Begin(ctx, "PurgeDeleted", "DELETE")
+	defer end(&err)
+
+	result := conn(ctx, r.db).
+		Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", r.dialect.time(deletedBefore)).
+		Delete(&ArticleModel{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("purge deleted articles: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (r *ArticleRepository) ListTags(ctx context.Context, status domain.ArticleStatus) (_ []domain.TagCount, err error) {
+	ctx, end := r.Begin(ctx, "ListTags", "SELECT")
+	defer end(&err)
+
+	return ListTags(conn(ctx, r.db), status)
+}
+
+func getByID(db *gorm.DB, id int64) (domain.Article, error) {
+	var model ArticleModel
+	err := db.First(&model, "id = ?", id).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return domain.Article{}, domain.ErrArticleNotFound
+	case err != nil:
+		return domain.Article{}, fmt.Errorf("get article by id %d: %w", id, err)
+	}
+
+	articles, err := withTags(db, []ArticleModel{model})
+	if err != nil {
+		return domain.Article{}, err
+	}
+	return articles[0], nil
+}
+
+// withTags converts models and loads their tags.
+func withTags(db *gorm.DB, models []ArticleModel) ([]domain.Article, error) {
+	articles := make([]domain.Article, 0, len(models))
+	for _, model := range models {
+		articles = append(articles, model.ToDomain())
+	}
+	if err := LoadTags(db, articles); err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
+// ArticleModel is a row of the articles table.
+type ArticleModel struct {
+	ID        int64      `gorm:"column:id;primaryKey"`
+	Title     string     `gorm:"column:title"`
+	Slug      string     `gorm:"column:slug"`
+	Body      string     `gorm:"column:body"`
+	Summary   string     `gorm:"column:summary"`
+	AuthorID  string     `gorm:"column:author_id"`
+	Status    string     `gorm:"column:status"`
+	PublishAt *time.Time `gorm:"column:publish_at"`
+	Version   int64      `gorm:"column:version"`
+	CreatedAt time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time  `gorm:"column:updated_at;autoUpdateTime"`
+	// DeletedAt makes GORM soft delete rows and skip them in every query
+	// that is not explicitly Unscoped.
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at"`
+}
+
+func (ArticleModel) TableName() string { return "articles" }
+
+func newArticleModel(article domain.Article, dialect Dialect) ArticleModel {
+	return ArticleModel{
+		Title:     article.Title,
+		Body:      article.Body,
+		Summary:   article.Summary,
+		AuthorID:  article.AuthorID,
+		Status:    string(statusOrDraft(article.Status)),
+		PublishAt: dialect.timeOrNil(article.PublishAt),
+		Version:   1,
+		// Left zero, autoCreateTime stamps the current time.
+		CreatedAt: dialect.time(article.CreatedAt),
+	}
+}
+
+// ToDomain converts the row, leaving Tags to LoadTags.
+func (m ArticleModel) ToDomain() domain.Article {
+	return domain.Article{
+		ID:        m.ID,
+		Title:     m.Title,
+		Slug:      m.Slug,
+		Body:      m.Body,
+		Summary:   m.Summary,
+		AuthorID:  m.AuthorID,
+		Status:    domain.ArticleStatus(m.Status),
+		PublishAt: m.PublishAt,
+		Version:   m.Version,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+
+// statusOrDraft stores articles saved without a status as drafts, as the
+// column default does.
+func statusOrDraft(status domain.ArticleStatus) domain.ArticleStatus {
+	if status == "" {
+		return domain.StatusDraft
+	}
+	return status
+}
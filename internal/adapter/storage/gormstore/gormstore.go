@@ -0,0 +1,41 @@
+// Package gormstore holds the GORM code the postgres and sqlite adapters
+// share: articles, tags, slugs, revisions, idempotency keys and API keys.
+// The adapters keep what differs between the databases, full-text search,
+// and pass the rest in as a Dialect.
+package gormstore
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// InsertBatchSize is the number of rows per multi-row INSERT statement.
+const InsertBatchSize = 100
+
+// Dialect describes the database beneath the stores.
+type Dialect struct {
+	// Name prefixes the span names of the article repository, as in
+	// "postgres.ArticleRepository.Save".
+	Name string
+	// System is the db.system.name attribute of those spans.
+	System attribute.KeyValue
+	// Time converts a timestamp before it is stored or compared. Nil keeps
+	// it as it is.
+	Time func(time.Time) time.Time
+}
+
+func (d Dialect) time(t time.Time) time.Time {
+	if d.Time == nil {
+		return t
+	}
+	return d.Time(t)
+}
+
+func (d Dialect) timeOrNil(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	converted := d.time(*t)
+	return &converted
+}
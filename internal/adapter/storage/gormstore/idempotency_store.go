@@ -0,0 +1,145 @@
+package gormstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"articles/internal/idempotency"
+)
+
+// reserveAttempts bounds the insert/read loop in Reserve: the record it
+// collided with can be released before it is read back.
+const reserveAttempts = 3
+
+type IdempotencyStore struct {
+	db           *gorm.DB
+	dialect      Dialect
+	queryTimeout time.Duration
+}
+
+func NewIdempotencyStore(db *gorm.DB, dialect Dialect, queryTimeout time.Duration) *IdempotencyStore {
+	return &IdempotencyStore{db: db, dialect: dialect, queryTimeout: queryTimeout}
+}
+
+func (s *IdempotencyStore) Reserve(ctx context.Context, record idempotency.Record, now time.Time) (idempotency.Record, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	db := s.db.WithContext(ctx)
+
+	// An expired holder of the key is dropped first so the insert can take it.
+	if err := db.Where("idempotency_key = ? AND expires_at <= ?", record.Key, s.dialect.time(now)).Delete(&idempotencyModel{}).Error; err != nil {
+		return idempotency.Record{}, false, fmt.Errorf("expire idempotency key: %w", err)
+	}
+
+	for range reserveAttempts {
+		model := idempotencyModel{
+			Key:         record.Key,
+			Fingerprint: record.Fingerprint,
+			Header:      "{}",
+			CreatedAt:   s.dialect.time(record.CreatedAt),
+			ExpiresAt:   s.dialect.time(record.ExpiresAt),
+		}
+		result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&model)
+		if result.Error != nil {
+			return idempotency.Record{}, false, fmt.Errorf("reserve idempotency key: %w", result.Error)
+		}
+		if result.RowsAffected == 1 {
+			return idempotency.Record{}, true, nil
+		}
+
+		var existing idempotencyModel
+		err := db.First(&existing, "idempotency_key = ?", record.Key).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			continue
+		case err != nil:
+			return idempotency.Record{}, false, fmt.Errorf("get idempotency key: %w", err)
+		}
+
+		stored, err := existing.toRecord()
+		if err != nil {
+			return idempotency.Record{}, false, err
+		}
+		return stored, false, nil
+	}
+
+	return idempotency.Record{}, false, fmt.Errorf("reserve idempotency key: gave up after %d attempts", reserveAttempts)
+}
+
+func (s *IdempotencyStore) Complete(ctx context.Context, key string, statusCode int, header http.Header, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	encoded, err := json.Marshal(header)
+	if err != nil {
+		return fmt.Errorf("encode idempotency headers: %w", err)
+	}
+
+	err = s.db.WithContext(ctx).
+		Model(&idempotencyModel{}).
+		Where("idempotency_key = ?", key).
+		Updates(map[string]any{"status_code": statusCode, "header": string(encoded), "body": body}).
+		Error
+	if err != nil {
+		return fmt.Errorf("complete idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	ctx, cancel := context.WithTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	if err := s.db.WithContext(ctx).Delete(&idempotencyModel{}, "idempotency_key = ?", key).Error; err != nil {
+		return fmt.Errorf("release idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (s *IdempotencyStore) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	result := s.db.WithContext(ctx).Delete(&idempotencyModel{}, "expires_at <= ?", s.dialect.time(now))
+	if result.Error != nil {
+		return 0, fmt.Errorf("delete expired idempotency keys: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+type idempotencyModel struct {
+	Key         string    `gorm:"column:idempotency_key;primaryKey"`
+	Fingerprint string    `gorm:"column:fingerprint"`
+	StatusCode  int       `gorm:"column:status_code"`
+	Header      string    `gorm:"column:header"`
+	Body        []byte    `gorm:"column:body"`
+	CreatedAt   time.Time `gorm:"column:created_at"`
+	ExpiresAt   time.Time `gorm:"column:expires_at"`
+}
+
+func (idempotencyModel) TableName() string { return "idempotency_keys" }
+
+func (m idempotencyModel) toRecord() (idempotency.Record, error) {
+	var header http.Header
+	if err := json.Unmarshal([]byte(m.Header), &header); err != nil {
+		return idempotency.Record{}, fmt.Errorf("decode idempotency headers: %w", err)
+	}
+
+	return idempotency.Record{
+		Key:         m.Key,
+		Fingerprint: m.Fingerprint,
+		StatusCode:  m.StatusCode,
+		Header:      header,
+		Body:        m.Body,
+		CreatedAt:   m.CreatedAt,
+		ExpiresAt:   m.ExpiresAt,
+	}, nil
+}
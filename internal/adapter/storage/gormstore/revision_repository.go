@@ -0,0 +1,148 @@
+package gormstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"articles/internal/domain"
+)
+
+type RevisionRepository struct {
+	db           *gorm.DB
+	dialect      Dialect
+	queryTimeout time.Duration
+}
+
+func NewRevisionRepository(db *gorm.DB, dialect Dialect, queryTimeout time.Duration) *RevisionRepository {
+	return &RevisionRepository{db: db, dialect: dialect, queryTimeout: queryTimeout}
+}
+
+func (r *RevisionRepository) SaveRevisions(ctx context.Context, revisions []domain.Revision) error {
+	ctx, cancel := context.WithTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	if len(revisions) == 0 {
+		return nil
+	}
+
+	models := make([]revisionModel, 0, len(revisions))
+	for _, revision := range revisions {
+		model, err := newRevisionModel(revision, r.dialect)
+		if err != nil {
+			return err
+		}
+		models = append(models, model)
+	}
+
+	err := conn(ctx, r.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(&models, InsertBatchSize).Error
+	if err != nil {
+		return fmt.Errorf("save %d revisions: %w", len(revisions), err)
+	}
+
+	return nil
+}
+
+func (r *RevisionRepository) ListRevisions(ctx context.Context, articleID int64) ([]domain.Revision, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var models []revisionModel
+	if err := conn(ctx, r.db).Where("article_id = ?", articleID).Order("number").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("list revisions of article %d: %w", articleID, err)
+	}
+
+	revisions := make([]domain.Revision, 0, len(models))
+	for _, model := range models {
+		revision, err := model.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, nil
+}
+
+func (r *RevisionRepository) GetRevision(ctx context.Context, articleID, number int64) (domain.Revision, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var model revisionModel
+	err := conn(ctx, r.db).First(&model, "article_id = ? AND number = ?", articleID, number).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return domain.Revision{}, domain.ErrRevisionNotFound
+	case err != nil:
+		return domain.Revision{}, fmt.Errorf("get revision %d of article %d: %w", number, articleID, err)
+	}
+
+	return model.toDomain()
+}
+
+type revisionModel struct {
+	ArticleID int64  `gorm:"column:article_id;primaryKey"`
+	Number    int64  `gorm:"column:number;primaryKey"`
+	Title     string `gorm:"column:title"`
+	Slug      string `gorm:"column:slug"`
+	Body      string `gorm:"column:body"`
+	Summary   string `gorm:"column:summary"`
+	// Tags is a JSON array of tag names.
+	Tags      string    `gorm:"column:tags"`
+	ChangedBy string    `gorm:"column:changed_by"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+func (revisionModel) TableName() string { return "article_revisions" }
+
+func newRevisionModel(revision domain.Revision, dialect Dialect) (revisionModel, error) {
+	tags := revision.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	encoded, err := json.Marshal(tags)
+	if err != nil {
+		return revisionModel{}, fmt.Errorf("encode revision tags: %w", err)
+	}
+
+	return revisionModel{
+		ArticleID: revision.ArticleID,
+		Number:    revision.Number,
+		Title:     revision.Title,
+		Slug:      revision.Slug,
+		Body:      revision.Body,
+		Summary:   revision.Summary,
+		Tags:      string(encoded),
+		ChangedBy: revision.ChangedBy,
+		CreatedAt: dialect.time(revision.CreatedAt),
+	}, nil
+}
+
+func (m revisionModel) toDomain() (domain.Revision, error) {
+	var tags []string
+	if err := json.Unmarshal([]byte(m.Tags), &tags); err != nil {
+		return domain.Revision{}, fmt.Errorf("decode revision tags: %w", err)
+	}
+	if len(tags) == 0 {
+		tags = nil
+	}
+
+	return domain.Revision{
+		ArticleID: m.ArticleID,
+		Number:    m.Number,
+		Title:     m.Title,
+		Slug:      m.Slug,
+		Body:      m.Body,
+		Summary:   m.Summary,
+		Tags:      tags,
+		ChangedBy: m.ChangedBy,
+		CreatedAt: m.CreatedAt,
+	}, nil
+}
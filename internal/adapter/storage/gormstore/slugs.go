@@ -0,0 +1,115 @@
+package gormstore
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"articles/internal/domain"
+)
+
+// claimSlugAttempts bounds the read/insert loop in claimSlug: a concurrent
+// writer can take the free slug between the two.
+const claimSlugAttempts = 5
+
+// slugModel is a row of article_slugs, which holds every slug an article
+// has had. Those other than articles.slug redirect to the article.
+type slugModel struct {
+	Slug      string `gorm:"column:slug;primaryKey"`
+	ArticleID int64  `gorm:"column:article_id"`
+}
+
+func (slugModel) TableName() string { return "article_slugs" }
+
+// BySlug narrows a query on articles to the article that has, or once had,
+// slug.
+func BySlug(slug string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.
+			Joins("JOIN article_slugs ON article_slugs.article_id = articles.id").
+			Where("article_slugs.slug = ?", slug)
+	}
+}
+
+// BackfillSlugs gives the articles still carrying the placeholder slug
+// "article-{id}" one derived from their title, and returns how many it
+// changed. Migration 0009 sets the placeholders since SQL cannot run
+// domain.Slugify; they stay in article_slugs, so old links redirect.
+func BackfillSlugs(db *gorm.DB) (int, error) {
+	var rows []struct {
+		ID    int64
+		Title string
+	}
+	if err := db.Table("articles").Select("id, title").Where("slug = 'article-' || id").Order("id").Scan(&rows).Error; err != nil {
+		return 0, fmt.Errorf("get placeholder slugs: %w", err)
+	}
+
+	changed := 0
+	for _, row := range rows {
+		base := domain.Slugify(row.Title)
+		if base == fmt.Sprintf("article-%d", row.ID) {
+			continue
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			_, err := AssignSlug(tx, row.ID, base)
+			return err
+		})
+		if err != nil {
+			return changed, err
+		}
+		changed++
+	}
+
+	return changed, nil
+}
+
+// AssignSlug claims the first free form of base for the article and makes
+// it the article's current slug.
+func AssignSlug(tx *gorm.DB, articleID int64, base string) (string, error) {
+	slug, err := claimSlug(tx, articleID, base)
+	if err != nil {
+		return "", err
+	}
+
+	err = tx.Table("articles").Where("id = ?", articleID).UpdateColumn("slug", slug).Error
+	if err != nil {
+		return "", fmt.Errorf("set slug of article %d: %w", articleID, err)
+	}
+
+	return slug, nil
+}
+
+// claimSlug records the first free form of base in article_slugs. Slugs the
+// article already owns count as free, so a title changed back gets its old
+// slug again.
+func claimSlug(tx *gorm.DB, articleID int64, base string) (string, error) {
+	for range claimSlugAttempts {
+		var claimed []slugModel
+		if err := tx.Where("slug = ? OR slug LIKE ?", base, base+"-%").Find(&claimed).Error; err != nil {
+			return "", fmt.Errorf("get slugs like %q: %w", base, err)
+		}
+		owners := make(map[string]int64, len(claimed))
+		for _, model := range claimed {
+			owners[model.Slug] = model.ArticleID
+		}
+
+		slug := domain.UniqueSlug(base, func(slug string) bool {
+			owner, ok := owners[slug]
+			return ok && owner != articleID
+		})
+		if owners[slug] == articleID {
+			return slug, nil
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&slugModel{Slug: slug, ArticleID: articleID})
+		if result.Error != nil {
+			return "", fmt.Errorf("claim slug %q: %w", slug, result.Error)
+		}
+		if result.RowsAffected == 1 {
+			return slug, nil
+		}
+	}
+
+	return "", fmt.Errorf("claim slug %q: gave up after %d attempts", base, claimSlugAttempts)
+}
@@ -0,0 +1,161 @@
+package gormstore
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"articles/internal/domain"
+)
+
+type tagModel struct {
+	ID   int64  `gorm:"column:id;primaryKey"`
+	Name string `gorm:"column:name"`
+}
+
+func (tagModel) TableName() string { return "tags" }
+
+type articleTagModel struct {
+	ArticleID int64 `gorm:"column:article_id;primaryKey"`
+	TagID     int64 `gorm:"column:tag_id;primaryKey"`
+}
+
+func (articleTagModel) TableName() string { return "article_tags" }
+
+// ListTags counts the live articles carrying each tag, most used first,
+// only those in status unless it is empty.
+func ListTags(db *gorm.DB, status domain.ArticleStatus) ([]domain.TagCount, error) {
+	tx := db.
+		Table("tags").
+		Select("tags.name AS name, COUNT(*) AS count").
+		Joins("JOIN article_tags ON article_tags.tag_id = tags.id").
+		Joins("JOIN articles ON articles.id = article_tags.article_id AND articles.deleted_at IS NULL")
+	if status != "" {
+		tx = tx.Where("articles.status = ?", status)
+	}
+
+	var rows []struct {
+		Name  string
+		Count int64
+	}
+	err := tx.Group("tags.name").Order("count DESC, tags.name").Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("list tags: %w", err)
+	}
+
+	tags := make([]domain.TagCount, 0, len(rows))
+	for _, row := range rows {
+		tags = append(tags, domain.TagCount{Name: row.Name, Count: row.Count})
+	}
+
+	return tags, nil
+}
+
+// ReplaceTags makes tags the complete set of tags of each article, creating
+// tags that do not exist yet.
+func ReplaceTags(tx *gorm.DB, tags map[int64][]string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	articleIDs := make([]int64, 0, len(tags))
+	seen := make(map[string]bool)
+	var names []string
+	for id, articleTags := range tags {
+		articleIDs = append(articleIDs, id)
+		for _, name := range articleTags {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+
+	if err := tx.Where("article_id IN ?", articleIDs).Delete(&articleTagModel{}).Error; err != nil {
+		return fmt.Errorf("clear article tags: %w", err)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	models := make([]tagModel, 0, len(names))
+	for _, name := range names {
+		models = append(models, tagModel{Name: name})
+	}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models).Error; err != nil {
+		return fmt.Errorf("create tags: %w", err)
+	}
+
+	// Tags that already existed come back without an ID from the insert.
+	models = nil
+	if err := tx.Where("name IN ?", names).Find(&models).Error; err != nil {
+		return fmt.Errorf("get tags: %w", err)
+	}
+	tagIDs := make(map[string]int64, len(models))
+	for _, model := range models {
+		tagIDs[model.Name] = model.ID
+	}
+
+	var links []articleTagModel
+	for id, articleTags := range tags {
+		for _, name := range articleTags {
+			links = append(links, articleTagModel{ArticleID: id, TagID: tagIDs[name]})
+		}
+	}
+	if err := tx.CreateInBatches(&links, InsertBatchSize).Error; err != nil {
+		return fmt.Errorf("link article tags: %w", err)
+	}
+
+	return nil
+}
+
+// LoadTags fills in the tags of articles.
+func LoadTags(db *gorm.DB, articles []domain.Article) error {
+	if len(articles) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(articles))
+	for _, article := range articles {
+		ids = append(ids, article.ID)
+	}
+
+	var rows []struct {
+		ArticleID int64
+		Name      string
+	}
+	err := db.Table("article_tags").
+		Select("article_tags.article_id AS article_id, tags.name AS name").
+		Joins("JOIN tags ON tags.id = article_tags.tag_id").
+		Where("article_tags.article_id IN ?", ids).
+		Order("tags.name").
+		Scan(&rows).Error
+	if err != nil {
+		return fmt.Errorf("load article tags: %w", err)
+	}
+
+	tags := make(map[int64][]string, len(articles))
+	for _, row := range rows {
+		tags[row.ArticleID] = append(tags[row.ArticleID], row.Name)
+	}
+	for i := range articles {
+		articles[i].Tags = tags[articles[i].ID]
+	}
+
+	return nil
+}
+
+// TaggedArticles selects the IDs of the articles matching filter, for use
+// as an "id IN (?)" subquery.
+func TaggedArticles(db *gorm.DB, filter domain.TagFilter) *gorm.DB {
+	sub := db.Session(&gorm.Session{NewDB: true}).
+		Table("article_tags").
+		Select("article_tags.article_id").
+		Joins("JOIN tags ON tags.id = article_tags.tag_id").
+		Where("tags.name IN ?", filter.Tags)
+	if filter.Match != domain.TagMatchAny {
+		sub = sub.Group("article_tags.article_id").Having("COUNT(*) = ?", len(filter.Tags))
+	}
+	return sub
+}
@@ -0,0 +1,41 @@
+package gormstore
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"articles/internal/domain"
+)
+
+const instrumentationName = "articles/internal/adapter/storage/gormstore"
+
+// Begin bounds ctx by the query timeout and starts a client span named after
+// the repository method, tagged with the SQL operation it issues. The
+// returned func is deferred with a pointer to the method's error result and
+// ends both.
+func (r *ArticleRepository) Begin(ctx context.Context, method, operation string) (context.Context, func(*error)) {
+	ctx, span := otel.Tracer(instrumentationName).Start(ctx, r.dialect.Name+".ArticleRepository."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			r.dialect.System,
+			semconv.DBOperationName(operation),
+			semconv.DBCollectionName("articles"),
+		),
+	)
+	ctx, cancel := context.WithTimeout(ctx, r.queryTimeout)
+
+	return ctx, func(err *error) {
+		cancel()
+		// Missing rows and stale versions are answers, not database failures.
+		if *err != nil && !errors.Is(*err, domain.ErrArticleNotFound) && !errors.Is(*err, domain.ErrVersionConflict) {
+			span.RecordError(*err)
+			span.SetStatus(codes.Error, (*err).Error())
+		}
+		span.End()
+	}
+}
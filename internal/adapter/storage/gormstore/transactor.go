@@ -0,0 +1,43 @@
+package gormstore
+
+import (
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+// Transactor runs units of work on db. The stores of this package opened on
+// the same db join the transaction through the ctx handed to fn.
+type Transactor struct {
+	db *gorm.DB
+}
+
+func NewTransactor(db *gorm.DB) *Transactor {
+	return &Transactor{db: db}
+}
+
+func (t *Transactor) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return conn(ctx, t.db).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// conn returns the transaction ctx carries, or db outside of one, bound to
+// ctx.
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
+
+// OnConn returns db bound to ctx and running its statements on conn, such as
+// the connection a migrate.Migrator holds the schema lock on.
+func OnConn(ctx context.Context, db *gorm.DB, conn *sql.Conn) *gorm.DB {
+	onConn := db.Session(&gorm.Session{NewDB: true, Context: ctx})
+	onConn.Statement.ConnPool = conn
+	return onConn
+}
@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"articles/internal/auth"
+)
+
+// APIKeyStore keeps API keys in process memory.
+type APIKeyStore struct {
+	mu     sync.Mutex
+	nextID int64
+	keys   []auth.APIKey
+}
+
+func NewAPIKeyStore() *APIKeyStore {
+	return &APIKeyStore{nextID: 1}
+}
+
+func (s *APIKeyStore) CreateKey(_ context.Context, key auth.APIKey) (auth.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key.ID = s.nextID
+	s.nextID++
+	key.Scopes = slices.Clone(key.Scopes)
+	s.keys = append(s.keys, key)
+	return cloneKey(key), nil
+}
+
+func (s *APIKeyStore) GetKeyByHash(_ context.Context, hash string) (auth.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range s.keys {
+		if key.Hash == hash {
+			return cloneKey(key), nil
+		}
+	}
+	return auth.APIKey{}, auth.ErrKeyNotFound
+}
+
+func (s *APIKeyStore) ListKeys(_ context.Context) ([]auth.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]auth.APIKey, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, cloneKey(key))
+	}
+	return keys, nil
+}
+
+func (s *APIKeyStore) RevokeKey(_ context.Context, id int64, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, key := range s.keys {
+		if key.ID != id {
+			continue
+		}
+		if key.RevokedAt == nil {
+			revokedAt := at.UTC()
+			s.keys[i].RevokedAt = &revokedAt
+		}
+		return nil
+	}
+	return auth.ErrKeyNotFound
+}
+
+// cloneKey keeps callers from mutating stored scopes and revocation times.
+func cloneKey(key auth.APIKey) auth.APIKey {
+	key.Scopes = slices.Clone(key.Scopes)
+	if key.RevokedAt != nil {
+		revokedAt := *key.RevokedAt
+		key.RevokedAt = &revokedAt
+	}
+	return key
+}
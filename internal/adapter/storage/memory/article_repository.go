@@ -0,0 +1,340 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"articles/internal/domain"
+)
+
+// ArticleRepository keeps articles in process memory. It is safe for
+// concurrent use and loses all data when the process exits.
+type ArticleRepository struct {
+	mu       sync.RWMutex
+	now      func() time.Time
+	lastID   int64
+	articles map[int64]*storedArticle
+	// slugs maps every slug ever assigned to its article.
+	slugs map[string]int64
+}
+
+type storedArticle struct {
+	article   domain.Article
+	deletedAt *time.Time
+}
+
+type Option func(*ArticleRepository)
+
+// WithClock replaces time.Now as the source of CreatedAt, UpdatedAt and
+// deletion timestamps.
+func WithClock(now func() time.Time) Option {
+	return func(r *ArticleRepository) {
+		r.now = now
+	}
+}
+
+func NewArticleRepository(opts ...Option) *ArticleRepository {
+	r := &ArticleRepository{
+		now:      time.Now,
+		articles: make(map[int64]*storedArticle),
+		slugs:    make(map[string]int64),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+func (r *ArticleRepository) Save(_ context.Context, article domain.Article) (domain.Article, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastID++
+	now := r.now()
+	article.ID = r.lastID
+	article.Slug = r.claimSlug(article.BaseSlug(), article.ID)
+	article.Tags = slices.Clone(article.Tags)
+	article.Status = statusOrDraft(article.Status)
+	article.Version = 1
+	if article.CreatedAt.IsZero() {
+		article.CreatedAt = now
+	}
+	article.UpdatedAt = now
+	r.articles[article.ID] = &storedArticle{article: article}
+
+	return article, nil
+}
+
+func (r *ArticleRepository) SaveMany(_ context.Context, articles []domain.Article) ([]domain.Article, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	saved := make([]domain.Article, 0, len(articles))
+	for _, article := range articles {
+		r.lastID++
+		article.ID = r.lastID
+		article.Slug = r.claimSlug(article.BaseSlug(), article.ID)
+		article.Tags = slices.Clone(article.Tags)
+		article.Status = statusOrDraft(article.Status)
+		article.Version = 1
+		if article.CreatedAt.IsZero() {
+			article.CreatedAt = now
+		}
+		article.UpdatedAt = now
+		r.articles[article.ID] = &storedArticle{article: article}
+		saved = append(saved, article)
+	}
+
+	return saved, nil
+}
+
+func (r *ArticleRepository) GetByID(_ context.Context, id int64) (domain.Article, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored, ok := r.articles[id]
+	if !ok || stored.deletedAt != nil {
+		return domain.Article{}, domain.ErrArticleNotFound
+	}
+
+	return stored.article, nil
+}
+
+func (r *ArticleRepository) GetByIDs(_ context.Context, ids []int64) ([]domain.Article, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	articles := make([]domain.Article, 0, len(ids))
+	for _, id := range ids {
+		if stored, ok := r.articles[id]; ok && stored.deletedAt == nil {
+			articles = append(articles, stored.article)
+		}
+	}
+
+	return articles, nil
+}
+
+func (r *ArticleRepository) GetBySlug(_ context.Context, slug string) (domain.Article, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored, ok := r.articles[r.slugs[slug]]
+	if !ok || stored.deletedAt != nil {
+		return domain.Article{}, domain.ErrArticleNotFound
+	}
+
+	return stored.article, nil
+}
+
+func (r *ArticleRepository) List(_ context.Context, query domain.ListArticlesQuery) ([]domain.Article, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	articles := make([]domain.Article, 0, len(r.articles))
+	for _, stored := range r.articles {
+		if stored.deletedAt != nil {
+			continue
+		}
+		if query.After != nil && !before(stored.article, *query.After) {
+			continue
+		}
+		if !matchesTags(stored.article, query.Tags) {
+			continue
+		}
+		if query.Status != "" && stored.article.Status != query.Status &&
+			(query.Owner == "" || stored.article.AuthorID != query.Owner) {
+			continue
+		}
+		articles = append(articles, stored.article)
+	}
+
+	sort.Slice(articles, func(i, j int) bool {
+		return before(articles[j], domain.ArticleCursor{CreatedAt: articles[i].CreatedAt, ID: articles[i].ID})
+	})
+	if len(articles) > query.Limit {
+		articles = articles[:query.Limit]
+	}
+
+	return articles, nil
+}
+
+func (r *ArticleRepository) ListScheduled(_ context.Context, due time.Time, limit int) ([]domain.Article, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var articles []domain.Article
+	for _, stored := range r.articles {
+		article := stored.article
+		if stored.deletedAt != nil || article.Status != domain.StatusInReview || article.PublishAt == nil || article.PublishAt.After(due) {
+			continue
+		}
+		articles = append(articles, article)
+	}
+
+	sort.Slice(articles, func(i, j int) bool {
+		if !articles[i].PublishAt.Equal(*articles[j].PublishAt) {
+			return articles[i].PublishAt.Before(*articles[j].PublishAt)
+		}
+		return articles[i].ID < articles[j].ID
+	})
+	if len(articles) > limit {
+		articles = articles[:limit]
+	}
+
+	return articles, nil
+}
+
+func (r *ArticleRepository) Update(_ context.Context, article domain.Article) (domain.Article, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.articles[article.ID]
+	if !ok || stored.deletedAt != nil {
+		return domain.Article{}, domain.ErrArticleNotFound
+	}
+	if stored.article.Version != article.Version {
+		return domain.Article{}, domain.ErrVersionConflict
+	}
+
+	stored.article.Title = article.Title
+	stored.article.Slug = r.claimSlug(article.BaseSlug(), article.ID)
+	stored.article.Body = article.Body
+	stored.article.Summary = article.Summary
+	stored.article.Tags = slices.Clone(article.Tags)
+	stored.article.Status = statusOrDraft(article.Status)
+	stored.article.PublishAt = article.PublishAt
+	stored.article.Version++
+	stored.article.UpdatedAt = r.now()
+
+	return stored.article, nil
+}
+
+func (r *ArticleRepository) Delete(_ context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.articles[id]
+	if !ok || stored.deletedAt != nil {
+		return domain.ErrArticleNotFound
+	}
+
+	now := r.now()
+	stored.deletedAt = &now
+
+	return nil
+}
+
+func (r *ArticleRepository) Restore(_ context.Context, id int64) (domain.Article, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.articles[id]
+	if !ok || stored.deletedAt == nil {
+		return domain.Article{}, domain.ErrArticleNotFound
+	}
+
+	stored.deletedAt = nil
+	stored.article.Version++
+	stored.article.UpdatedAt = r.now()
+
+	return stored.article, nil
+}
+
+func (r *ArticleRepository) PurgeDeleted(_ context.Context, deletedBefore time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var purged int64
+	for id, stored := range r.articles {
+		if stored.deletedAt != nil && stored.deletedAt.Before(deletedBefore) {
+			delete(r.articles, id)
+			purged++
+		}
+	}
+	for slug, id := range r.slugs {
+		if _, ok := r.articles[id]; !ok {
+			delete(r.slugs, slug)
+		}
+	}
+
+	return purged, nil
+}
+
+func (r *ArticleRepository) ListTags(_ context.Context, status domain.ArticleStatus) ([]domain.TagCount, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := make(map[string]int64)
+	for _, stored := range r.articles {
+		if stored.deletedAt != nil || (status != "" && stored.article.Status != status) {
+			continue
+		}
+		for _, tag := range stored.article.Tags {
+			counts[tag]++
+		}
+	}
+
+	tags := make([]domain.TagCount, 0, len(counts))
+	for name, count := range counts {
+		tags = append(tags, domain.TagCount{Name: name, Count: count})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Count != tags[j].Count {
+			return tags[i].Count > tags[j].Count
+		}
+		return tags[i].Name < tags[j].Name
+	})
+
+	return tags, nil
+}
+
+// claimSlug records the first free form of base for article id and returns
+// it. Slugs id already owns count as free. The caller must hold r.mu.
+func (r *ArticleRepository) claimSlug(base string, id int64) string {
+	slug := domain.UniqueSlug(base, func(slug string) bool {
+		owner, ok := r.slugs[slug]
+		return ok && owner != id
+	})
+	r.slugs[slug] = id
+	return slug
+}
+
+// statusOrDraft stores articles saved without a status as drafts, as the
+// database default does.
+func statusOrDraft(status domain.ArticleStatus) domain.ArticleStatus {
+	if status == "" {
+		return domain.StatusDraft
+	}
+	return status
+}
+
+// matchesTags reports whether article passes filter.
+func matchesTags(article domain.Article, filter domain.TagFilter) bool {
+	if len(filter.Tags) == 0 {
+		return true
+	}
+
+	matched := 0
+	for _, tag := range filter.Tags {
+		if slices.Contains(article.Tags, tag) {
+			matched++
+		}
+	}
+	if filter.Match == domain.TagMatchAny {
+		return matched > 0
+	}
+	return matched == len(filter.Tags)
+}
+
+// before reports whether article sorts after cursor in newest-first order,
+// i.e. whether (created_at, id) < (cursor.CreatedAt, cursor.ID).
+func before(article domain.Article, cursor domain.ArticleCursor) bool {
+	if !article.CreatedAt.Equal(cursor.CreatedAt) {
+		return article.CreatedAt.Before(cursor.CreatedAt)
+	}
+	return article.ID < cursor.ID
+}
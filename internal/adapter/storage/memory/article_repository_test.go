@@ -0,0 +1,171 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"articles/internal/domain"
+	"articles/internal/domain/repotest"
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestRepo() (*ArticleRepository, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	return NewArticleRepository(WithClock(clock.Now)), clock
+}
+
+func TestArticleRepository_Contract(t *testing.T) {
+	repotest.Run(t, func(*testing.T) domain.ArticleRepository {
+		return NewArticleRepository()
+	})
+}
+
+func TestArticleRepository_SaveAndGetByID(t *testing.T) {
+	repo, clock := newTestRepo()
+
+	saved, err := repo.Save(context.Background(), domain.Article{Title: "Hello", AuthorID: "user-1"})
+	if err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	if saved.ID != 1 || saved.Version != 1 {
+		t.Fatalf("unexpected saved article: %+v", saved)
+	}
+	if !saved.CreatedAt.Equal(clock.Now()) || !saved.UpdatedAt.Equal(clock.Now()) {
+		t.Fatalf("expected timestamps from the injected clock, got %+v", saved)
+	}
+
+	got, err := repo.GetByID(context.Background(), saved.ID)
+	if err != nil {
+		t.Fatalf("GetByID returned error: %v", err)
+	}
+	if !reflect.DeepEqual(got, saved) {
+		t.Fatalf("expected %+v, got %+v", saved, got)
+	}
+}
+
+func TestArticleRepository_List(t *testing.T) {
+	repo, clock := newTestRepo()
+
+	var saved []domain.Article
+	for _, title := range []string{"First", "Second", "Third"} {
+		article, err := repo.Save(context.Background(), domain.Article{Title: title})
+		if err != nil {
+			t.Fatalf("seed Save returned error: %v", err)
+		}
+		saved = append(saved, article)
+	}
+	// Two articles sharing a timestamp must still page deterministically.
+	clock.Advance(time.Second)
+	saved = append(saved, mustSave(t, repo, "Fourth"), mustSave(t, repo, "Fifth"))
+
+	var ids []int64
+	query := domain.ListArticlesQuery{Limit: 2}
+	for {
+		page, err := repo.List(context.Background(), query)
+		if err != nil {
+			t.Fatalf("List returned error: %v", err)
+		}
+		for _, article := range page {
+			ids = append(ids, article.ID)
+		}
+		if len(page) < query.Limit {
+			break
+		}
+		last := page[len(page)-1]
+		query.After = &domain.ArticleCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	want := []int64{saved[4].ID, saved[3].ID, saved[2].ID, saved[1].ID, saved[0].ID}
+	if len(ids) != len(want) {
+		t.Fatalf("expected ids %v, got %v", want, ids)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("expected ids %v, got %v", want, ids)
+		}
+	}
+}
+
+func TestArticleRepository_Update(t *testing.T) {
+	repo, clock := newTestRepo()
+	saved := mustSave(t, repo, "Helo")
+
+	clock.Advance(time.Minute)
+	saved.Title = "Hello"
+	updated, err := repo.Update(context.Background(), saved)
+	if err != nil {
+		t.Fatalf("Update returned error: %v", err)
+	}
+	if updated.Title != "Hello" || updated.Version != 2 || !updated.UpdatedAt.Equal(clock.Now()) {
+		t.Fatalf("unexpected updated article: %+v", updated)
+	}
+
+	if _, err := repo.Update(context.Background(), saved); !errors.Is(err, domain.ErrVersionConflict) {
+		t.Fatalf("expected ErrVersionConflict, got %v", err)
+	}
+}
+
+func TestArticleRepository_DeleteRestoreAndPurge(t *testing.T) {
+	repo, clock := newTestRepo()
+	saved := mustSave(t, repo, "Hello")
+
+	if err := repo.Delete(context.Background(), saved.ID); err != nil {
+		t.Fatalf("Delete returned error: %v", err)
+	}
+	if _, err := repo.GetByID(context.Background(), saved.ID); !errors.Is(err, domain.ErrArticleNotFound) {
+		t.Fatalf("expected ErrArticleNotFound for deleted article, got %v", err)
+	}
+
+	restored, err := repo.Restore(context.Background(), saved.ID)
+	if err != nil {
+		t.Fatalf("Restore returned error: %v", err)
+	}
+	if restored.Version != 2 {
+		t.Fatalf("expected restore to bump version, got %d", restored.Version)
+	}
+
+	if err := repo.Delete(context.Background(), saved.ID); err != nil {
+		t.Fatalf("Delete returned error: %v", err)
+	}
+	purged, err := repo.PurgeDeleted(context.Background(), clock.Now())
+	if err != nil || purged != 0 {
+		t.Fatalf("expected nothing purged at the deletion instant, got %d, %v", purged, err)
+	}
+	clock.Advance(time.Hour)
+	purged, err = repo.PurgeDeleted(context.Background(), clock.Now())
+	if err != nil || purged != 1 {
+		t.Fatalf("expected 1 purged article, got %d, %v", purged, err)
+	}
+	if _, err := repo.Restore(context.Background(), saved.ID); !errors.Is(err, domain.ErrArticleNotFound) {
+		t.Fatalf("expected purged article to be gone, got %v", err)
+	}
+}
+
+func mustSave(t *testing.T, repo *ArticleRepository, title string) domain.Article {
+	t.Helper()
+	article, err := repo.Save(context.Background(), domain.Article{Title: title})
+	if err != nil {
+		t.Fatalf("seed Save returned error: %v", err)
+	}
+	return article
+}
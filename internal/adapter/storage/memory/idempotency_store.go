@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"articles/internal/idempotency"
+)
+
+// IdempotencyStore keeps idempotency records in process memory. Expired
+// records are swept on every Reserve, so memory stays bounded by the
+// number of keys seen within one TTL.
+type IdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]idempotency.Record
+}
+
+func NewIdempotencyStore() *IdempotencyStore {
+	return &IdempotencyStore{records: make(map[string]idempotency.Record)}
+}
+
+func (s *IdempotencyStore) Reserve(_ context.Context, record idempotency.Record, now time.Time) (idempotency.Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deleteExpired(now)
+	if existing, ok := s.records[record.Key]; ok {
+		return cloneRecord(existing), false, nil
+	}
+
+	record.StatusCode, record.Header, record.Body = 0, nil, nil
+	s.records[record.Key] = record
+	return idempotency.Record{}, true, nil
+}
+
+func (s *IdempotencyStore) Complete(_ context.Context, key string, statusCode int, header http.Header, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[key]
+	if !ok {
+		return nil
+	}
+	record.StatusCode = statusCode
+	record.Header = header.Clone()
+	record.Body = bytes.Clone(body)
+	s.records[key] = record
+	return nil
+}
+
+func (s *IdempotencyStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+	return nil
+}
+
+func (s *IdempotencyStore) DeleteExpired(_ context.Context, now time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.deleteExpired(now), nil
+}
+
+func (s *IdempotencyStore) deleteExpired(now time.Time) int64 {
+	var deleted int64
+	for key, record := range s.records {
+		if !record.ExpiresAt.After(now) {
+			delete(s.records, key)
+			deleted++
+		}
+	}
+	return deleted
+}
+
+// cloneRecord keeps callers from mutating stored headers and bodies.
+func cloneRecord(record idempotency.Record) idempotency.Record {
+	record.Header = record.Header.Clone()
+	record.Body = bytes.Clone(record.Body)
+	return record
+}
@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"articles/internal/ratelimit"
+)
+
+// rateLimitSweepInterval is how often RateLimitStore forgets buckets that
+// have refilled.
+const rateLimitSweepInterval = time.Minute
+
+type rateLimitEntry struct {
+	bucket ratelimit.Bucket
+	limit  ratelimit.Limit
+}
+
+// RateLimitStore keeps token buckets in process memory, so each replica
+// enforces its own budgets. Buckets that have refilled are forgotten, which
+// bounds memory by the number of clients seen within one period.
+type RateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]rateLimitEntry
+	lastSweep time.Time
+}
+
+func NewRateLimitStore() *RateLimitStore {
+	return &RateLimitStore{buckets: make(map[string]rateLimitEntry)}
+}
+
+func (s *RateLimitStore) Take(_ context.Context, key string, limit ratelimit.Limit, now time.Time) (ratelimit.Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) >= rateLimitSweepInterval {
+		s.sweep(now)
+	}
+
+	bucket, result := s.buckets[key].bucket.Take(limit, now)
+	s.buckets[key] = rateLimitEntry{bucket: bucket, limit: limit}
+	return result, nil
+}
+
+func (s *RateLimitStore) sweep(now time.Time) {
+	for key, entry := range s.buckets {
+		if entry.bucket.Full(entry.limit, now) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
@@ -0,0 +1,115 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"articles/internal/ratelimit"
+)
+
+var (
+	rateLimitStart = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	rateLimit      = ratelimit.Limit{Burst: 3, Period: 3 * time.Second}
+)
+
+func takeToken(t *testing.T, store *RateLimitStore, key string, now time.Time) ratelimit.Result {
+	t.Helper()
+
+	result, err := store.Take(context.Background(), key, rateLimit, now)
+	if err != nil {
+		t.Fatalf("Take returned error: %v", err)
+	}
+	return result
+}
+
+func TestRateLimitStore_AllowsBurstThenRefuses(t *testing.T) {
+	store := NewRateLimitStore()
+
+	for i := range rateLimit.Burst {
+		if result := takeToken(t, store, "client", rateLimitStart); !result.Allowed || result.Remaining != rateLimit.Burst-1-i {
+			t.Fatalf("take %d: expected allowed with %d remaining, got %+v", i, rateLimit.Burst-1-i, result)
+		}
+	}
+
+	result := takeToken(t, store, "client", rateLimitStart)
+	if result.Allowed || result.RetryAfter <= 0 {
+		t.Fatalf("expected a refusal with a retry delay, got %+v", result)
+	}
+}
+
+func TestRateLimitStore_Refills(t *testing.T) {
+	store := NewRateLimitStore()
+	for range rateLimit.Burst + 1 {
+		takeToken(t, store, "client", rateLimitStart)
+	}
+
+	if result := takeToken(t, store, "client", rateLimitStart.Add(time.Second)); !result.Allowed {
+		t.Fatalf("expected a token to be refilled after 1s, got %+v", result)
+	}
+	if result := takeToken(t, store, "client", rateLimitStart.Add(time.Hour)); !result.Allowed || result.Remaining != rateLimit.Burst-1 {
+		t.Fatalf("expected a full bucket after an hour, got %+v", result)
+	}
+}
+
+func TestRateLimitStore_KeysAreIndependent(t *testing.T) {
+	store := NewRateLimitStore()
+	for range rateLimit.Burst {
+		takeToken(t, store, "first", rateLimitStart)
+	}
+
+	if result := takeToken(t, store, "second", rateLimitStart); !result.Allowed || result.Remaining != rateLimit.Burst-1 {
+		t.Fatalf("expected another key to start full, got %+v", result)
+	}
+}
+
+func TestRateLimitStore_ConcurrentTakesSpendEachTokenOnce(t *testing.T) {
+	const workers = 10
+	store := NewRateLimitStore()
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		allowed int
+	)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := store.Take(context.Background(), "client", rateLimit, rateLimitStart)
+			if err != nil {
+				t.Errorf("Take returned error: %v", err)
+				return
+			}
+			if result.Allowed {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if allowed != rateLimit.Burst {
+		t.Fatalf("expected %d of %d concurrent takes to be allowed, got %d", rateLimit.Burst, workers, allowed)
+	}
+}
+
+func TestRateLimitStore_ForgetsRefilledBuckets(t *testing.T) {
+	store := NewRateLimitStore()
+	limit := ratelimit.Limit{Burst: 2, Period: time.Second}
+
+	for _, key := range []string{"first", "second"} {
+		if _, err := store.Take(context.Background(), key, limit, rateLimitStart); err != nil {
+			t.Fatalf("Take returned error: %v", err)
+		}
+	}
+	if _, err := store.Take(context.Background(), "third", limit, rateLimitStart.Add(rateLimitSweepInterval)); err != nil {
+		t.Fatalf("Take returned error: %v", err)
+	}
+
+	if len(store.buckets) != 1 {
+		t.Fatalf("expected only the new bucket to be kept, got %d", len(store.buckets))
+	}
+}
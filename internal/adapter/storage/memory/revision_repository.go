@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+
+	"articles/internal/domain"
+)
+
+// RevisionRepository keeps revisions in process memory. It is safe for
+// concurrent use and loses all data when the process exits.
+type RevisionRepository struct {
+	mu        sync.RWMutex
+	revisions map[int64][]domain.Revision
+}
+
+func NewRevisionRepository() *RevisionRepository {
+	return &RevisionRepository{revisions: make(map[int64][]domain.Revision)}
+}
+
+func (r *RevisionRepository) SaveRevisions(_ context.Context, revisions []domain.Revision) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, revision := range revisions {
+		revision.Tags = slices.Clone(revision.Tags)
+		stored := r.revisions[revision.ArticleID]
+		// Revisions of one article may be recorded out of order by
+		// concurrent updates; keep them sorted by number.
+		i, found := slices.BinarySearchFunc(stored, revision.Number, func(rev domain.Revision, number int64) int {
+			return cmp.Compare(rev.Number, number)
+		})
+		if found {
+			continue
+		}
+		r.revisions[revision.ArticleID] = slices.Insert(stored, i, revision)
+	}
+
+	return nil
+}
+
+func (r *RevisionRepository) ListRevisions(_ context.Context, articleID int64) ([]domain.Revision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return slices.Clone(r.revisions[articleID]), nil
+}
+
+func (r *RevisionRepository) GetRevision(_ context.Context, articleID, number int64) (domain.Revision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, revision := range r.revisions[articleID] {
+		if revision.Number == number {
+			return revision, nil
+		}
+	}
+
+	return domain.Revision{}, domain.ErrRevisionNotFound
+}
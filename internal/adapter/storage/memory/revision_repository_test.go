@@ -0,0 +1,14 @@
+package memory
+
+import (
+	"testing"
+
+	"articles/internal/domain"
+	"articles/internal/domain/repotest"
+)
+
+func TestRevisionRepository_Contract(t *testing.T) {
+	repotest.RunRevisions(t, func(*testing.T) (domain.ArticleRepository, domain.RevisionRepository) {
+		return NewArticleRepository(), NewRevisionRepository()
+	})
+}
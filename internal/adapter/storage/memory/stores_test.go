@@ -0,0 +1,16 @@
+package memory
+
+import (
+	"testing"
+
+	"articles/internal/storetest"
+)
+
+func TestStores_Contract(t *testing.T) {
+	storetest.Run(t, func(*testing.T) storetest.Stores {
+		return storetest.Stores{
+			Keys:        NewAPIKeyStore(),
+			Idempotency: NewIdempotencyStore(),
+		}
+	})
+}
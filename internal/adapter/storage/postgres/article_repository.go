@@ -1,67 +1,48 @@
 package postgres
 
 import (
-	"context"
-	"errors"
-	"fmt"
 	"time"
 
 	"gorm.io/gorm"
 
-	"articles/internal/domain"
+	"articles/internal/adapter/storage/gormstore"
 )
 
+// ArticleRepository is the shared GORM repository with PostgreSQL full-text
+// search added.
 type ArticleRepository struct {
-	db *gorm.DB
+	*gormstore.ArticleRepository
+	searchLanguage string
+	queryTimeout   time.Duration
 }
 
-const queryTimeout = 3 * time.Second
+const defaultQueryTimeout = 3 * time.Second
 
-func NewArticleRepository(db *gorm.DB) *ArticleRepository {
-	return &ArticleRepository{db: db}
-}
-
-func (r *ArticleRepository) Save(ctx context.Context, article domain.Article) (domain.Article, error) {
-	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
-	defer cancel()
-
-	model := articleModel{Title: article.Title}
+type Option func(*ArticleRepository)
 
-	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
-		return domain.Article{}, fmt.Errorf("create article: %w", err)
+// WithQueryTimeout bounds every statement issued by the repository.
+func WithQueryTimeout(timeout time.Duration) Option {
+	return func(r *ArticleRepository) {
+		r.queryTimeout = timeout
 	}
-
-	return domain.Article{
-		ID:        model.ID,
-		Title:     model.Title,
-		CreatedAt: model.CreatedAt,
-	}, nil
 }
 
-func (r *ArticleRepository) GetByID(ctx context.Context, id int64) (domain.Article, error) {
-	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
-	defer cancel()
-
-	var model articleModel
-	err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error
-	switch {
-	case errors.Is(err, gorm.ErrRecordNotFound):
-		return domain.Article{}, domain.ErrArticleNotFound
-	case err != nil:
-		return domain.Article{}, fmt.Errorf("get article by id %d: %w", id, err)
+// WithSearchLanguage sets the text search configuration (for example
+// "english" or "simple") used to parse queries and build snippets.
+func WithSearchLanguage(language string) Option {
+	return func(r *ArticleRepository) {
+		r.searchLanguage = language
 	}
-
-	return domain.Article{
-		ID:        model.ID,
-		Title:     model.Title,
-		CreatedAt: model.CreatedAt,
-	}, nil
 }
 
-type articleModel struct {
-	ID        int64     `gorm:"column:id;primaryKey"`
-	Title     string    `gorm:"column:title"`
-	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+func NewArticleRepository(db *gorm.DB, opts ...Option) *ArticleRepository {
+	r := &ArticleRepository{
+		searchLanguage: indexedSearchLanguage,
+		queryTimeout:   defaultQueryTimeout,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	r.ArticleRepository = gormstore.NewArticleRepository(db, dialect, r.queryTimeout)
+	return r
 }
-
-func (articleModel) TableName() string { return "articles" }
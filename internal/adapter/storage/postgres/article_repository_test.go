@@ -16,7 +16,10 @@ import (
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 
+	dbmigrations "articles/db"
 	"articles/internal/domain"
+	"articles/internal/domain/repotest"
+	"articles/internal/migrate"
 )
 
 func setupTestDB(t *testing.T) (*gorm.DB, func()) {
@@ -68,9 +71,20 @@ func setupTestDB(t *testing.T) (*gorm.DB, func()) {
 		t.Fatalf("open gorm: %v", err)
 	}
 
-	if err := db.AutoMigrate(&articleModel{}); err != nil {
+	// Tests run against the schema the service migrates to, not a model of it.
+	sqlDB, err := db.DB()
+	if err != nil {
+		container.Terminate(ctx)
+		t.Fatalf("get sql.DB: %v", err)
+	}
+	migrator, err := migrate.New(sqlDB, migrate.Postgres, dbmigrations.Migrations)
+	if err != nil {
+		container.Terminate(ctx)
+		t.Fatalf("load migrations: %v", err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
 		container.Terminate(ctx)
-		t.Fatalf("auto-migrate: %v", err)
+		t.Fatalf("apply migrations: %v", err)
 	}
 
 	cleanup := func() {
@@ -94,6 +108,19 @@ func ensureDocker(t *testing.T) {
 	testcontainers.Logger = log.New(io.Discard, "", 0)
 }
 
+func TestArticleRepository_Contract(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// One container serves every subtest; each starts from an empty table.
+	repotest.Run(t, func(t *testing.T) domain.ArticleRepository {
+		if err := db.Exec("TRUNCATE articles, tags, article_tags, article_slugs, article_revisions RESTART IDENTITY").Error; err != nil {
+			t.Fatalf("truncate articles: %v", err)
+		}
+		return NewArticleRepository(db)
+	})
+}
+
 func TestArticleRepository_Save(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -0,0 +1,21 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"articles/internal/domain"
+	"articles/internal/domain/repotest"
+)
+
+func TestRevisionRepository_Contract(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repotest.RunRevisions(t, func(t *testing.T) (domain.ArticleRepository, domain.RevisionRepository) {
+		if err := db.Exec("TRUNCATE articles, tags, article_tags, article_slugs, article_revisions RESTART IDENTITY").Error; err != nil {
+			t.Fatalf("truncate articles: %v", err)
+		}
+		return NewArticleRepository(db), NewRevisionRepository(db, time.Second)
+	})
+}
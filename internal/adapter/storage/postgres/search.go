@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+
+	"articles/internal/adapter/storage/gormstore"
+	"articles/internal/domain"
+)
+
+// indexedSearchLanguage must match the configuration used by the generated
+// search_vector column in db/migrations.
+const indexedSearchLanguage = "english"
+
+// ts_headline marks matches with control characters, which are first
+// stripped from the text, so the snippet can be HTML-escaped before the
+// marks become tags. The in-process search produces the same format.
+const (
+	highlightStart  = "\x02"
+	highlightStop   = "\x03"
+	headlineOptions = `StartSel="` + highlightStart + `", StopSel="` + highlightStop + `", MaxWords=35, MinWords=15, MaxFragments=2`
+)
+
+var highlightTags = strings.NewReplacer(highlightStart, "<b>", highlightStop, "</b>")
+
+type searchRow struct {
+	gormstore.ArticleModel `gorm:"embedded"`
+	Rank                   float64 `gorm:"column:rank"`
+	Snippet                string  `gorm:"column:snippet"`
+}
+
+func (r *ArticleRepository) Search(ctx context.Context, query domain.SearchQuery) (_ []domain.SearchResult, err error) {
+	ctx, end := r.Begin(ctx, "Search", "SELECT")
+	defer end(&err)
+
+	// The stored column only helps when the configured language is the one it
+	// was generated with; any other language is vectorised on the fly.
+	vector := "search_vector"
+	if r.searchLanguage != indexedSearchLanguage {
+		vector = `(setweight(to_tsvector(CAST(@lang AS regconfig), title), 'A') ||
+			setweight(to_tsvector(CAST(@lang AS regconfig), summary), 'B') ||
+			setweight(to_tsvector(CAST(@lang AS regconfig), body), 'C'))`
+	}
+
+	sql := fmt.Sprintf(`
+		SELECT articles.*,
+			ts_rank(%[1]s, q) AS rank,
+			ts_headline(CAST(@lang AS regconfig), translate(concat_ws(' ', title, summary, body), @marks, ''), q, @headline) AS snippet
+		FROM articles, websearch_to_tsquery(CAST(@lang AS regconfig), @text) AS q
+		WHERE articles.deleted_at IS NULL AND %[1]s @@ q
+			AND (@status = '' OR articles.status = @status OR (@owner <> '' AND articles.author_id = @owner))
+		ORDER BY rank DESC, id DESC
+		LIMIT @limit`, vector)
+
+	var rows []searchRow
+	err = r.DB().WithContext(ctx).Raw(sql, map[string]any{
+		"lang":     r.searchLanguage,
+		"text":     query.Text,
+		"headline": headlineOptions,
+		"marks":    highlightStart + highlightStop,
+		"limit":    query.Limit,
+		"status":   string(query.Status),
+		"owner":    query.Owner,
+	}).Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("search articles: %w", err)
+	}
+
+	articles := make([]domain.Article, 0, len(rows))
+	for _, row := range rows {
+		articles = append(articles, row.ToDomain())
+	}
+	if err := gormstore.LoadTags(r.DB().WithContext(ctx), articles); err != nil {
+		return nil, err
+	}
+
+	results := make([]domain.SearchResult, 0, len(rows))
+	for i, row := range rows {
+		results = append(results, domain.SearchResult{
+			Article: articles[i],
+			Rank:    row.Rank,
+			Snippet: highlight(row.Snippet),
+		})
+	}
+
+	return results, nil
+}
+
+// highlight escapes a ts_headline snippet and turns its marks into <b> tags.
+func highlight(snippet string) string {
+	return highlightTags.Replace(html.EscapeString(snippet))
+}
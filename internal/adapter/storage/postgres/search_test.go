@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"articles/internal/domain"
+)
+
+func TestArticleRepository_Search_OtherLanguage(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo := NewArticleRepository(db, WithSearchLanguage("simple"))
+
+	if _, err := repo.Save(context.Background(), domain.Article{Title: "Running fast"}); err != nil {
+		t.Fatalf("seed Save returned error: %v", err)
+	}
+
+	// "simple" does not stem, so "run" must not match "running".
+	results, err := repo.Search(context.Background(), domain.SearchQuery{Text: "run", Limit: 10})
+	if err != nil {
+		t.Fatalf("Search returned error: %v", err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("expected no results, got %+v", results)
+	}
+}
+
+func TestArticleRepository_Search_EscapesSnippet(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	repo := NewArticleRepository(db)
+
+	article := domain.Article{Title: "Tips", Body: "Postgres <i>tricks</i> & \x02more\x03"}
+	if _, err := repo.Save(context.Background(), article); err != nil {
+		t.Fatalf("seed Save returned error: %v", err)
+	}
+
+	results, err := repo.Search(context.Background(), domain.SearchQuery{Text: "postgres", Limit: 10})
+	if err != nil {
+		t.Fatalf("Search returned error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %+v", results)
+	}
+	want := "<b>Postgres</b> &lt;i&gt;tricks&lt;/i&gt; &amp; more"
+	if !strings.Contains(results[0].Snippet, want) {
+		t.Fatalf("expected snippet to contain %q, got %q", want, results[0].Snippet)
+	}
+}
@@ -0,0 +1,25 @@
+package postgres
+
+import (
+	"time"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"gorm.io/gorm"
+
+	"articles/internal/adapter/storage/gormstore"
+)
+
+// dialect stores timestamps as they are: the columns are timestamptz.
+var dialect = gormstore.Dialect{Name: "postgres", System: semconv.DBSystemNamePostgreSQL}
+
+func NewRevisionRepository(db *gorm.DB, queryTimeout time.Duration) *gormstore.RevisionRepository {
+	return gormstore.NewRevisionRepository(db, dialect, queryTimeout)
+}
+
+func NewIdempotencyStore(db *gorm.DB, queryTimeout time.Duration) *gormstore.IdempotencyStore {
+	return gormstore.NewIdempotencyStore(db, dialect, queryTimeout)
+}
+
+func NewAPIKeyStore(db *gorm.DB, queryTimeout time.Duration) *gormstore.APIKeyStore {
+	return gormstore.NewAPIKeyStore(db, dialect, queryTimeout)
+}
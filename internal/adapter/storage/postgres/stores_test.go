@@ -0,0 +1,23 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"articles/internal/storetest"
+)
+
+func TestStores_Contract(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	storetest.Run(t, func(t *testing.T) storetest.Stores {
+		if err := db.Exec("TRUNCATE api_keys, idempotency_keys RESTART IDENTITY").Error; err != nil {
+			t.Fatalf("truncate stores: %v", err)
+		}
+		return storetest.Stores{
+			Keys:        NewAPIKeyStore(db, time.Second),
+			Idempotency: NewIdempotencyStore(db, time.Second),
+		}
+	})
+}
@@ -0,0 +1,34 @@
+package sqlite
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"articles/internal/adapter/storage/gormstore"
+)
+
+const defaultQueryTimeout = 3 * time.Second
+
+type options struct {
+	queryTimeout time.Duration
+}
+
+type Option func(*options)
+
+// WithQueryTimeout bounds every statement issued by the repository.
+func WithQueryTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.queryTimeout = timeout
+	}
+}
+
+// NewArticleRepository returns the shared GORM repository; SQLite has no
+// full-text search, so listings stand in for it.
+func NewArticleRepository(db *gorm.DB, opts ...Option) *gormstore.ArticleRepository {
+	o := options{queryTimeout: defaultQueryTimeout}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return gormstore.NewArticleRepository(db, dialect, o.queryTimeout)
+}
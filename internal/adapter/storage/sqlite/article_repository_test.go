@@ -0,0 +1,53 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"articles/internal/domain"
+	"articles/internal/domain/repotest"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := Open(context.Background(), filepath.Join(t.TempDir(), "articles.db"))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := Migrate(context.Background(), db); err != nil {
+		t.Fatalf("migrate sqlite: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+
+	return db
+}
+
+func TestArticleRepository_Contract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) domain.ArticleRepository {
+		return NewArticleRepository(setupTestDB(t))
+	})
+}
+
+func TestOpen_MigrateIsIdempotent(t *testing.T) {
+	db := setupTestDB(t)
+
+	if err := Migrate(context.Background(), db); err != nil {
+		t.Fatalf("second Migrate returned error: %v", err)
+	}
+
+	var version int64
+	if err := db.Raw("SELECT version FROM schema_migrations").Scan(&version).Error; err != nil {
+		t.Fatalf("read schema version: %v", err)
+	}
+	if version != 12 {
+		t.Fatalf("expected schema version 12, got %d", version)
+	}
+}
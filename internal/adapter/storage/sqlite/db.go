@@ -0,0 +1,68 @@
+package sqlite
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"time"
+
+	gormsqlite "gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"articles/internal/migrate"
+)
+
+//go:embed migrations/*.sql
+var embedded embed.FS
+
+// Migrations mirrors db/migrations for SQLite; keep the two in sync. Version
+// numbers match, so 0006 (the PostgreSQL search vector) has no counterpart.
+var Migrations, _ = fs.Sub(embedded, "migrations")
+
+// Open connects to the SQLite database at path (":memory:" for a private
+// in-memory database). The schema is left as is; see Migrate.
+func Open(ctx context.Context, path string) (*gorm.DB, error) {
+	db, err := gorm.Open(gormsqlite.Open(path), &gorm.Config{
+		// Timestamps are stored as text, so they must share one zone for
+		// keyset comparisons to order correctly.
+		NowFunc: func() time.Time { return time.Now().UTC() },
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	// SQLite serialises writers anyway, and ":memory:" databases are private
+	// to a connection, so a single connection avoids both "database is
+	// locked" errors and split in-memory databases.
+	sqlDB.SetMaxOpenConns(1)
+
+	for _, pragma := range []string{"PRAGMA foreign_keys = ON", "PRAGMA busy_timeout = 5000"} {
+		if err := db.WithContext(ctx).Exec(pragma).Error; err != nil {
+			_ = sqlDB.Close()
+			return nil, fmt.Errorf("%s: %w", pragma, err)
+		}
+	}
+
+	return db, nil
+}
+
+// Migrate applies every pending migration from Migrations.
+func Migrate(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+
+	migrator, err := migrate.New(sqlDB, migrate.SQLite, Migrations)
+	if err != nil {
+		return err
+	}
+
+	_, err = migrator.Up(ctx)
+	return err
+}
@@ -0,0 +1,2 @@
+ALTER TABLE articles DROP COLUMN updated_at;
+ALTER TABLE articles DROP COLUMN version;
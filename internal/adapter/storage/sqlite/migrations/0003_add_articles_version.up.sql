@@ -0,0 +1,6 @@
+ALTER TABLE articles ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+-- SQLite cannot add a NOT NULL column without a constant default, so the
+-- repository always writes updated_at itself.
+ALTER TABLE articles ADD COLUMN updated_at DATETIME;
+
+UPDATE articles SET updated_at = created_at WHERE updated_at IS NULL;
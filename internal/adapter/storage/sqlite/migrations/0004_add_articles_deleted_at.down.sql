@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS articles_deleted_at_idx;
+DROP INDEX IF EXISTS articles_live_created_at_id_idx;
+CREATE INDEX IF NOT EXISTS articles_created_at_id_idx ON articles (created_at DESC, id DESC);
+
+ALTER TABLE articles DROP COLUMN deleted_at;
@@ -0,0 +1,5 @@
+ALTER TABLE articles ADD COLUMN deleted_at DATETIME;
+
+DROP INDEX IF EXISTS articles_created_at_id_idx;
+CREATE INDEX IF NOT EXISTS articles_live_created_at_id_idx ON articles (created_at DESC, id DESC) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS articles_deleted_at_idx ON articles (deleted_at) WHERE deleted_at IS NOT NULL;
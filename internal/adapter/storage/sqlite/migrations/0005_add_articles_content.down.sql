@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS articles_author_id_idx;
+
+ALTER TABLE articles DROP COLUMN author_id;
+ALTER TABLE articles DROP COLUMN summary;
+ALTER TABLE articles DROP COLUMN body;
@@ -0,0 +1,5 @@
+ALTER TABLE articles ADD COLUMN body TEXT NOT NULL DEFAULT '';
+ALTER TABLE articles ADD COLUMN summary TEXT NOT NULL DEFAULT '';
+ALTER TABLE articles ADD COLUMN author_id TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS articles_author_id_idx ON articles (author_id) WHERE author_id <> '';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS article_slugs;
+DROP INDEX IF EXISTS articles_slug_key;
+ALTER TABLE articles DROP COLUMN slug;
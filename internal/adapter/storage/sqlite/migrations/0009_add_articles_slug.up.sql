@@ -0,0 +1,19 @@
+-- Existing articles get a placeholder slug, replaced with one derived from
+-- the title right after migrating; see db/migrations.
+ALTER TABLE articles ADD COLUMN slug TEXT;
+
+UPDATE articles SET slug = 'article-' || id WHERE slug IS NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS articles_slug_key ON articles (slug);
+
+CREATE TABLE IF NOT EXISTS article_slugs (
+    slug       TEXT PRIMARY KEY,
+    article_id INTEGER NOT NULL REFERENCES articles (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS article_slugs_article_id_idx ON article_slugs (article_id);
+
+INSERT INTO article_slugs (slug, article_id)
+SELECT slug, id FROM articles
+WHERE true -- lets SQLite tell ON CONFLICT from a join constraint
+ON CONFLICT (slug) DO NOTHING;
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS article_revisions (
+    article_id INTEGER NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    number     INTEGER NOT NULL,
+    title      TEXT NOT NULL,
+    slug       TEXT NOT NULL DEFAULT '',
+    body       TEXT NOT NULL DEFAULT '',
+    summary    TEXT NOT NULL DEFAULT '',
+    tags       TEXT NOT NULL DEFAULT '[]',
+    changed_by TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (article_id, number)
+);
+
+INSERT INTO article_revisions (article_id, number, title, slug, body, summary, tags, created_at)
+SELECT a.id, a.version, a.title, a.slug, a.body, a.summary,
+       COALESCE((SELECT json_group_array(name)
+                 FROM (SELECT t.name AS name
+                       FROM article_tags at JOIN tags t ON t.id = at.tag_id
+                       WHERE at.article_id = a.id
+                       ORDER BY t.name)), '[]'),
+       a.updated_at
+FROM articles a
+WHERE true -- lets SQLite tell ON CONFLICT from a join constraint
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS articles_scheduled_idx;
+ALTER TABLE articles DROP COLUMN publish_at;
+ALTER TABLE articles DROP COLUMN status;
@@ -0,0 +1,8 @@
+ALTER TABLE articles ADD COLUMN status TEXT NOT NULL DEFAULT 'draft'
+    CHECK (status IN ('draft', 'in_review', 'published', 'archived'));
+ALTER TABLE articles ADD COLUMN publish_at DATETIME;
+
+UPDATE articles SET status = 'published', publish_at = created_at;
+
+CREATE INDEX IF NOT EXISTS articles_scheduled_idx ON articles (publish_at)
+    WHERE status = 'in_review' AND publish_at IS NOT NULL AND deleted_at IS NULL;
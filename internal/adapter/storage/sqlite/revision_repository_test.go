@@ -0,0 +1,60 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"articles/internal/adapter/storage/gormstore"
+	"articles/internal/domain"
+	"articles/internal/domain/repotest"
+)
+
+func TestRevisionRepository_Contract(t *testing.T) {
+	repotest.RunRevisions(t, func(t *testing.T) (domain.ArticleRepository, domain.RevisionRepository) {
+		db := setupTestDB(t)
+		return NewArticleRepository(db), NewRevisionRepository(db, time.Second)
+	})
+}
+
+func TestTransactor_KeepsArticleAndRevisionTogether(t *testing.T) {
+	db := setupTestDB(t)
+	articles, revisions := NewArticleRepository(db), NewRevisionRepository(db, time.Second)
+	tx := gormstore.NewTransactor(db)
+	ctx := context.Background()
+
+	var saved domain.Article
+	err := tx.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		if saved, err = articles.Save(ctx, domain.Article{Title: "Kept"}); err != nil {
+			return err
+		}
+		return revisions.SaveRevisions(ctx, []domain.Revision{{ArticleID: saved.ID, Number: 1, Title: "Kept", CreatedAt: saved.CreatedAt}})
+	})
+	if err != nil {
+		t.Fatalf("WithTx returned error: %v", err)
+	}
+	if _, err := revisions.GetRevision(ctx, saved.ID, 1); err != nil {
+		t.Fatalf("expected the committed revision, got %v", err)
+	}
+
+	revisionErr := errors.New("revision failed")
+	var dropped domain.Article
+	err = tx.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		if dropped, err = articles.Save(ctx, domain.Article{Title: "Dropped"}); err != nil {
+			return err
+		}
+		return revisionErr
+	})
+	if !errors.Is(err, revisionErr) {
+		t.Fatalf("expected the revision error, got %v", err)
+	}
+	if _, err := articles.GetByID(ctx, dropped.ID); !errors.Is(err, domain.ErrArticleNotFound) {
+		t.Fatalf("expected the article to be rolled back, got %v", err)
+	}
+	if _, err := articles.GetBySlug(ctx, dropped.Slug); !errors.Is(err, domain.ErrArticleNotFound) {
+		t.Fatalf("expected its slug to be rolled back, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"articles/internal/adapter/storage/gormstore"
+	"articles/internal/domain"
+	"articles/internal/migrate"
+)
+
+// savePlaceholders saves an article per title and leaves them as migration
+// 0009 leaves those it finds.
+func savePlaceholders(t *testing.T, db *gorm.DB, titles ...string) []int64 {
+	t.Helper()
+
+	repo := NewArticleRepository(db)
+	var ids []int64
+	for _, title := range titles {
+		saved, err := repo.Save(context.Background(), domain.Article{Title: title})
+		if err != nil {
+			t.Fatalf("Save returned error: %v", err)
+		}
+		ids = append(ids, saved.ID)
+	}
+	if err := db.Exec("DELETE FROM article_slugs").Error; err != nil {
+		t.Fatalf("clear slugs: %v", err)
+	}
+	if err := db.Exec("UPDATE articles SET slug = 'article-' || id").Error; err != nil {
+		t.Fatalf("set placeholders: %v", err)
+	}
+	if err := db.Exec("INSERT INTO article_slugs (slug, article_id) SELECT slug, id FROM articles").Error; err != nil {
+		t.Fatalf("record placeholders: %v", err)
+	}
+	return ids
+}
+
+func TestBackfillSlugs_ReplacesPlaceholders(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewArticleRepository(db)
+	ctx := context.Background()
+	ids := savePlaceholders(t, db, "Hello World", "Hello, world!", "Article 3")
+
+	changed, err := gormstore.BackfillSlugs(db)
+	if err != nil {
+		t.Fatalf("BackfillSlugs returned error: %v", err)
+	}
+	if changed != 2 {
+		t.Fatalf("expected 2 slugs to change, got %d", changed)
+	}
+
+	for i, want := range []string{"hello-world", "hello-world-2", "article-3"} {
+		got, err := repo.GetByID(ctx, ids[i])
+		if err != nil {
+			t.Fatalf("GetByID returned error: %v", err)
+		}
+		if got.Slug != want {
+			t.Fatalf("expected article %d to get slug %q, got %q", ids[i], want, got.Slug)
+		}
+	}
+
+	redirected, err := repo.GetBySlug(ctx, "article-1")
+	if err != nil || redirected.ID != ids[0] {
+		t.Fatalf("expected the placeholder to still lead to article %d, got %+v, %v", ids[0], redirected, err)
+	}
+
+	if changed, err := gormstore.BackfillSlugs(db); err != nil || changed != 0 {
+		t.Fatalf("expected a second run to change nothing, got %d, %v", changed, err)
+	}
+}
+
+func TestBackfillSlugs_UnderTheSchemaLock(t *testing.T) {
+	db := setupTestDB(t)
+	ids := savePlaceholders(t, db, "Hello World")
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("DB returned error: %v", err)
+	}
+	migrator, err := migrate.New(sqlDB, migrate.SQLite, Migrations)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	// The lock holds the only connection; anything not running on it would
+	// wait for the timeout.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var changed int
+	err = migrator.WithLock(ctx, func(conn *sql.Conn) error {
+		changed, err = gormstore.BackfillSlugs(gormstore.OnConn(ctx, db, conn))
+		return err
+	})
+	if err != nil || changed != 1 {
+		t.Fatalf("expected 1 slug to change, got %d, %v", changed, err)
+	}
+	if got, err := NewArticleRepository(db).GetByID(ctx, ids[0]); err != nil || got.Slug != "hello-world" {
+		t.Fatalf("expected slug %q, got %+v, %v", "hello-world", got, err)
+	}
+}
@@ -0,0 +1,26 @@
+package sqlite
+
+import (
+	"time"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"gorm.io/gorm"
+
+	"articles/internal/adapter/storage/gormstore"
+)
+
+// dialect converts every timestamp to UTC: they are stored as text, which
+// only compares in time order when all of them share a zone.
+var dialect = gormstore.Dialect{Name: "sqlite", System: semconv.DBSystemNameSQLite, Time: time.Time.UTC}
+
+func NewRevisionRepository(db *gorm.DB, queryTimeout time.Duration) *gormstore.RevisionRepository {
+	return gormstore.NewRevisionRepository(db, dialect, queryTimeout)
+}
+
+func NewIdempotencyStore(db *gorm.DB, queryTimeout time.Duration) *gormstore.IdempotencyStore {
+	return gormstore.NewIdempotencyStore(db, dialect, queryTimeout)
+}
+
+func NewAPIKeyStore(db *gorm.DB, queryTimeout time.Duration) *gormstore.APIKeyStore {
+	return gormstore.NewAPIKeyStore(db, dialect, queryTimeout)
+}
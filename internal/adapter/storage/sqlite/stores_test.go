@@ -0,0 +1,18 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"articles/internal/storetest"
+)
+
+func TestStores_Contract(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) storetest.Stores {
+		db := setupTestDB(t)
+		return storetest.Stores{
+			Keys:        NewAPIKeyStore(db, time.Second),
+			Idempotency: NewIdempotencyStore(db, time.Second),
+		}
+	})
+}
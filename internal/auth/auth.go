@@ -0,0 +1,223 @@
+// Package auth identifies API callers. API keys are minted by operators,
+// stored only as hashes and resolved to a Principal carrying the scopes the
+// key was granted. Bearer tokens issued by other services are verified as
+// JWTs and resolved to a Principal carrying the roles their claims map to.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Scope string
+
+const (
+	ScopeArticlesRead  Scope = "articles:read"
+	ScopeArticlesWrite Scope = "articles:write"
+)
+
+// Scopes lists every scope a key can be granted.
+var Scopes = []Scope{ScopeArticlesRead, ScopeArticlesWrite}
+
+const (
+	// keyPrefix marks API keys so they are recognisable in configuration
+	// files and secret scanners.
+	keyPrefix = "ak_"
+	// keySecretBytes is the amount of randomness in a key.
+	keySecretBytes = 24
+	// displayPrefixLength is how much of a key is kept in clear to tell keys
+	// apart in listings.
+	displayPrefixLength = len(keyPrefix) + 8
+
+	MaxKeyNameLength = 100
+)
+
+var (
+	ErrKeyNotFound    = errors.New("api key not found")
+	ErrInvalidScope   = errors.New("scopes must be one or more of articles:read, articles:write")
+	ErrInvalidKeyName = errors.New("key name must be 1 to 100 characters")
+	ErrInvalidRole    = errors.New("role must be reader, author, editor or admin")
+)
+
+// ParseScopes parses a comma or space separated list of scopes, dropping
+// duplicates.
+func ParseScopes(raw string) ([]Scope, error) {
+	fields := strings.FieldsFunc(raw, func(r rune) bool { return r == ',' || r == ' ' })
+	if len(fields) == 0 {
+		return nil, ErrInvalidScope
+	}
+
+	scopes := make([]Scope, 0, len(fields))
+	for _, field := range fields {
+		scope := Scope(field)
+		if !slices.Contains(Scopes, scope) {
+			return nil, ErrInvalidScope
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, nil
+}
+
+// Role is what a signed-in user may do, as granted by the token issuer.
+type Role string
+
+const (
+	RoleReader Role = "reader"
+	RoleAuthor Role = "author"
+	RoleEditor Role = "editor"
+	RoleAdmin  Role = "admin"
+)
+
+// roleScopes lists the scopes each role grants.
+var roleScopes = map[Role][]Scope{
+	RoleReader: {ScopeArticlesRead},
+	RoleAuthor: {ScopeArticlesRead, ScopeArticlesWrite},
+	RoleEditor: {ScopeArticlesRead, ScopeArticlesWrite},
+	RoleAdmin:  {ScopeArticlesRead, ScopeArticlesWrite},
+}
+
+// ParseRole maps a role name from configuration to a Role.
+func ParseRole(raw string) (Role, error) {
+	role := Role(raw)
+	if _, ok := roleScopes[role]; !ok {
+		return "", ErrInvalidRole
+	}
+	return role, nil
+}
+
+// ScopesFor returns the scopes roles grant together, without duplicates.
+func ScopesFor(roles []Role) []Scope {
+	var scopes []Scope
+	for _, role := range roles {
+		for _, scope := range roleScopes[role] {
+			if !slices.Contains(scopes, scope) {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes
+}
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	// Subject identifies the caller in logs and revision history, for
+	// example "apikey:3" or "jwt:<iss>|<sub>" for a token.
+	Subject string
+	// AuthorID is the author articles the principal creates are attributed
+	// to; empty leaves it to the request.
+	AuthorID string
+	Roles    []Role
+	Scopes   []Scope
+}
+
+func (p Principal) HasScope(scope Scope) bool {
+	return slices.Contains(p.Scopes, scope)
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying p.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFrom returns the principal stored in ctx, if any.
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
+
+// APIKey is a stored key. The key itself is only shown once, when it is
+// minted; afterwards it can only be matched by its hash.
+type APIKey struct {
+	ID   int64
+	Name string
+	// Prefix is the start of the key, enough to tell keys apart.
+	Prefix string
+	// Hash is the hex SHA-256 of the key.
+	Hash      string
+	Scopes    []Scope
+	CreatedAt time.Time
+	// RevokedAt is set once the key has been revoked; revoked keys are kept
+	// so listings show them.
+	RevokedAt *time.Time
+}
+
+func (k APIKey) Revoked() bool {
+	return k.RevokedAt != nil
+}
+
+// Principal is the caller a request made with k is attributed to. Keys are
+// minted by operators for integrations, so a key that may write acts as an
+// editor and one that may only read as a reader.
+func (k APIKey) Principal() Principal {
+	role := RoleReader
+	if slices.Contains(k.Scopes, ScopeArticlesWrite) {
+		role = RoleEditor
+	}
+	return Principal{Subject: "apikey:" + strconv.FormatInt(k.ID, 10), Roles: []Role{role}, Scopes: k.Scopes}
+}
+
+// KeyStore persists API keys.
+type KeyStore interface {
+	// CreateKey stores key and returns it with its ID assigned.
+	CreateKey(ctx context.Context, key APIKey) (APIKey, error)
+	// GetKeyByHash returns the key, revoked or not, whose Hash is hash.
+	GetKeyByHash(ctx context.Context, hash string) (APIKey, error)
+	// ListKeys returns every key, oldest first.
+	ListKeys(ctx context.Context) ([]APIKey, error)
+	// RevokeKey marks a key revoked at the given time. Revoking a revoked key
+	// keeps the original time.
+	RevokeKey(ctx context.Context, id int64, at time.Time) error
+}
+
+// NewAPIKey mints a key. The returned secret is what clients send; only its
+// hash is kept in the returned APIKey.
+func NewAPIKey(name string, scopes []Scope, now time.Time) (secret string, key APIKey, err error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > MaxKeyNameLength {
+		return "", APIKey{}, ErrInvalidKeyName
+	}
+	if len(scopes) == 0 {
+		return "", APIKey{}, ErrInvalidScope
+	}
+
+	var b [keySecretBytes]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", APIKey{}, err
+	}
+	secret = keyPrefix + hex.EncodeToString(b[:])
+
+	return secret, APIKey{
+		Name:      name,
+		Prefix:    secret[:displayPrefixLength],
+		Hash:      HashKey(secret),
+		Scopes:    scopes,
+		CreatedAt: now.UTC(),
+	}, nil
+}
+
+// HashKey returns the hash a key is stored under. Keys carry enough
+// randomness that a fast hash is safe.
+func HashKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// FormatScopes joins scopes the way stores and listings show them.
+func FormatScopes(scopes []Scope) string {
+	parts := make([]string, len(scopes))
+	for i, scope := range scopes {
+		parts[i] = string(scope)
+	}
+	return strings.Join(parts, " ")
+}
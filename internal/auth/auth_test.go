@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseScopes(t *testing.T) {
+	got, err := ParseScopes("articles:read, articles:write articles:read")
+	if err != nil {
+		t.Fatalf("ParseScopes returned error: %v", err)
+	}
+	if want := []Scope{ScopeArticlesRead, ScopeArticlesWrite}; !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	for _, raw := range []string{"", " , ", "articles:admin"} {
+		if _, err := ParseScopes(raw); !errors.Is(err, ErrInvalidScope) {
+			t.Fatalf("ParseScopes(%q): expected ErrInvalidScope, got %v", raw, err)
+		}
+	}
+}
+
+func TestNewAPIKey(t *testing.T) {
+	secret, key, err := NewAPIKey("  ci  ", []Scope{ScopeArticlesRead}, time.Now())
+	if err != nil {
+		t.Fatalf("NewAPIKey returned error: %v", err)
+	}
+	if !strings.HasPrefix(secret, keyPrefix) || !strings.HasPrefix(secret, key.Prefix) {
+		t.Fatalf("expected %q to start with %q and %q", secret, keyPrefix, key.Prefix)
+	}
+	if key.Name != "ci" || key.Hash != HashKey(secret) || strings.Contains(key.Hash, secret) {
+		t.Fatalf("unexpected key %+v", key)
+	}
+
+	other, _, err := NewAPIKey("ci", []Scope{ScopeArticlesRead}, time.Now())
+	if err != nil || other == secret {
+		t.Fatalf("expected a fresh key, got %q, %v", other, err)
+	}
+}
+
+func TestNewAPIKey_Invalid(t *testing.T) {
+	if _, _, err := NewAPIKey(" ", []Scope{ScopeArticlesRead}, time.Now()); !errors.Is(err, ErrInvalidKeyName) {
+		t.Fatalf("expected ErrInvalidKeyName, got %v", err)
+	}
+	if _, _, err := NewAPIKey("ci", nil, time.Now()); !errors.Is(err, ErrInvalidScope) {
+		t.Fatalf("expected ErrInvalidScope, got %v", err)
+	}
+}
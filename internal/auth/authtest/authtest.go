@@ -0,0 +1,173 @@
+// Package authtest issues signed tokens for tests, together with the JWKS
+// file a verifier loads their keys from. It does not import auth, so the auth
+// package's own tests can use it.
+package authtest
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const (
+	// Issuer and Audience are what tokens from Claims carry.
+	Issuer   = "https://issuer.test"
+	Audience = "articles"
+)
+
+// Signer holds a private key and signs tokens with it.
+type Signer struct {
+	KeyID string
+	Alg   string
+	key   crypto.Signer
+}
+
+// NewSigner generates a key for alg, one of ES256, RS256 or EdDSA.
+func NewSigner(t *testing.T, kid, alg string) *Signer {
+	t.Helper()
+
+	var (
+		key crypto.Signer
+		err error
+	)
+	switch alg {
+	case "ES256":
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case "RS256":
+		key, err = rsa.GenerateKey(rand.Reader, 2048)
+	case "EdDSA":
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		t.Fatalf("authtest: unsupported algorithm %q", alg)
+	}
+	if err != nil {
+		t.Fatalf("generate %s key: %v", alg, err)
+	}
+	return &Signer{KeyID: kid, Alg: alg, key: key}
+}
+
+// PublicKey returns the key tokens from s are verified with.
+func (s *Signer) PublicKey() crypto.PublicKey {
+	return s.key.Public()
+}
+
+// Claims returns valid claims for subject, expiring in an hour.
+func Claims(subject string, roles ...string) map[string]any {
+	return map[string]any{
+		"iss":   Issuer,
+		"aud":   Audience,
+		"sub":   subject,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"roles": roles,
+	}
+}
+
+// Sign returns a compact JWS of claims.
+func (s *Signer) Sign(t *testing.T, claims map[string]any) string {
+	t.Helper()
+
+	header := map[string]string{"alg": s.Alg, "typ": "JWT"}
+	if s.KeyID != "" {
+		header["kid"] = s.KeyID
+	}
+	signed := encodeJSON(t, header) + "." + encodeJSON(t, claims)
+
+	var (
+		signature []byte
+		err       error
+	)
+	switch key := s.key.(type) {
+	case *ecdsa.PrivateKey:
+		digest := sha256.Sum256([]byte(signed))
+		var r, s *big.Int
+		r, s, err = ecdsa.Sign(rand.Reader, key, digest[:])
+		if err == nil {
+			signature = make([]byte, 64)
+			r.FillBytes(signature[:32])
+			s.FillBytes(signature[32:])
+		}
+	case *rsa.PrivateKey:
+		digest := sha256.Sum256([]byte(signed))
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(key, []byte(signed))
+	}
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// JWKS returns the JSON key set publishing the public keys of signers.
+func JWKS(t *testing.T, signers ...*Signer) []byte {
+	t.Helper()
+
+	keys := make([]map[string]string, 0, len(signers))
+	for _, s := range signers {
+		key := map[string]string{"kid": s.KeyID, "alg": s.Alg, "use": "sig"}
+		switch pub := s.PublicKey().(type) {
+		case *ecdsa.PublicKey:
+			point, err := pub.Bytes()
+			if err != nil {
+				t.Fatalf("encode EC key: %v", err)
+			}
+			key["kty"], key["crv"] = "EC", "P-256"
+			key["x"] = base64.RawURLEncoding.EncodeToString(point[1:33])
+			key["y"] = base64.RawURLEncoding.EncodeToString(point[33:])
+		case *rsa.PublicKey:
+			key["kty"] = "RSA"
+			key["n"] = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			key["e"] = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			key["kty"], key["crv"] = "OKP", "Ed25519"
+			key["x"] = base64.RawURLEncoding.EncodeToString(pub)
+		}
+		keys = append(keys, key)
+	}
+
+	data, err := json.Marshal(map[string]any{"keys": keys})
+	if err != nil {
+		t.Fatalf("encode JWKS: %v", err)
+	}
+	return data
+}
+
+// WriteJWKS writes the key set of signers to a file in a temporary directory
+// and returns its path.
+func WriteJWKS(t *testing.T, signers ...*Signer) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	RewriteJWKS(t, path, signers...)
+	return path
+}
+
+// RewriteJWKS replaces the key set at path, as an issuer rotating keys would.
+func RewriteJWKS(t *testing.T, path string, signers ...*Signer) {
+	t.Helper()
+
+	if err := os.WriteFile(path, JWKS(t, signers...), 0o600); err != nil {
+		t.Fatalf("write JWKS: %v", err)
+	}
+}
+
+func encodeJSON(t *testing.T, v any) string {
+	t.Helper()
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("encode token segment: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
@@ -0,0 +1,246 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/MicahParks/jwkset"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// jwksMinRefetch spaces out the fetches an unknown kid triggers, so
+	// tokens with made-up key IDs cannot make the service hammer the issuer.
+	jwksMinRefetch = 30 * time.Second
+	// maxJWKSBytes bounds the size of a fetched key set.
+	maxJWKSBytes = 1 << 20
+)
+
+// JWK is a key tokens may be verified with: an *rsa.PublicKey,
+// *ecdsa.PublicKey, ed25519.PublicKey or, for HMAC, a []byte secret. Alg,
+// when set, is the only algorithm the key may be used with.
+type JWK struct {
+	KeyID string
+	Alg   string
+	Key   any
+}
+
+// KeySource finds the key a token was signed with from its kid header.
+type KeySource interface {
+	Key(ctx context.Context, kid string) (JWK, error)
+}
+
+type staticKey struct {
+	key JWK
+}
+
+// StaticKey verifies every token with key, whatever its kid.
+func StaticKey(key any) KeySource {
+	return staticKey{key: JWK{Key: key}}
+}
+
+func (s staticKey) Key(context.Context, string) (JWK, error) {
+	return s.key, nil
+}
+
+// ParsePublicKeyPEM reads a PEM encoded public key or certificate.
+func ParsePublicKeyPEM(data []byte) (any, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	switch block.Type {
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return cert.PublicKey, nil
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+}
+
+// JWKS is a key set fetched from a URL or file and kept for refresh. A token
+// signed with a key the cached set lacks triggers an early fetch, so keys the
+// issuer rotates in are picked up without waiting for the refresh; keys it
+// rotates out stop working at the next fetch. Fetches run without holding
+// the cache, so tokens with known keys are verified meanwhile, and callers
+// needing one at the same time share it.
+type JWKS struct {
+	fetch   func(ctx context.Context) ([]byte, error)
+	refresh time.Duration
+	now     func() time.Time
+	loads   singleflight.Group
+
+	mu        sync.Mutex
+	keys      []JWK
+	fetchedAt time.Time
+}
+
+// NewJWKS returns a key set read through fetch at most every refresh,
+// starting on first use.
+func NewJWKS(fetch func(ctx context.Context) ([]byte, error), refresh time.Duration) *JWKS {
+	return &JWKS{fetch: fetch, refresh: refresh, now: time.Now}
+}
+
+// JWKSFromURL fetches the key set with client.
+func JWKSFromURL(client *http.Client, url string, refresh time.Duration) *JWKS {
+	return NewJWKS(func(ctx context.Context) ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("fetch %s: %s", url, resp.Status)
+		}
+		return io.ReadAll(io.LimitReader(resp.Body, maxJWKSBytes))
+	}, refresh)
+}
+
+// JWKSFromFile reads the key set from path, again at every refresh, so keys
+// can be rotated by replacing the file.
+func JWKSFromFile(path string, refresh time.Duration) *JWKS {
+	return NewJWKS(func(context.Context) ([]byte, error) {
+		return os.ReadFile(path)
+	}, refresh)
+}
+
+func (s *JWKS) Key(ctx context.Context, kid string) (JWK, error) {
+	now := s.now()
+	keys, fetchedAt := s.cached()
+	if keys == nil || now.Sub(fetchedAt) >= s.refresh {
+		var err error
+		if keys, err = s.load(ctx); err != nil {
+			return JWK{}, err
+		}
+		fetchedAt = now
+	}
+	if key, ok := find(keys, kid); ok {
+		return key, nil
+	}
+
+	if now.Sub(fetchedAt) >= jwksMinRefetch {
+		keys, err := s.load(ctx)
+		if err != nil {
+			return JWK{}, err
+		}
+		if key, ok := find(keys, kid); ok {
+			return key, nil
+		}
+	}
+	return JWK{}, fmt.Errorf("%w: unknown key %q", ErrInvalidToken, kid)
+}
+
+func (s *JWKS) cached() ([]JWK, time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.keys, s.fetchedAt
+}
+
+// load fetches the keys and swaps them in, once for all callers asking at the
+// same time. When the fetch fails the old keys are kept until the next
+// refresh; the error is only returned if there are none.
+func (s *JWKS) load(ctx context.Context) ([]JWK, error) {
+	keys, err, _ := s.loads.Do("", func() (any, error) {
+		s.mu.Lock()
+		s.fetchedAt = s.now()
+		s.mu.Unlock()
+
+		// The fetch is shared, so one caller giving up must not fail it for
+		// the others.
+		keys, err := s.read(context.WithoutCancel(ctx))
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if err != nil {
+			if s.keys != nil {
+				slog.WarnContext(ctx, "refresh JWKS failed, keeping cached keys", "error", err)
+				return s.keys, nil
+			}
+			return nil, fmt.Errorf("load JWKS: %w", err)
+		}
+		s.keys = keys
+		return keys, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys.([]JWK), nil
+}
+
+func (s *JWKS) read(ctx context.Context) ([]JWK, error) {
+	data, err := s.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ParseJWKS(data)
+}
+
+// find looks a key up by ID. A token without kid is matched to the only key
+// of a one-key set.
+func find(keys []JWK, kid string) (JWK, bool) {
+	if kid == "" && len(keys) == 1 {
+		return keys[0], true
+	}
+	for _, key := range keys {
+		if key.KeyID == kid {
+			return key, true
+		}
+	}
+	return JWK{}, false
+}
+
+// ParseJWKS reads the signature keys of a JWK set. Encryption keys and key
+// types other than RSA, EC and Ed25519 are skipped.
+func ParseJWKS(data []byte) ([]JWK, error) {
+	var set jwkset.JWKSMarshal
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("decode JWKS: %w", err)
+	}
+
+	keys := make([]JWK, 0, len(set.Keys))
+	for i, raw := range set.Keys {
+		if raw.USE != "" && raw.USE != jwkset.UseSig {
+			continue
+		}
+		if raw.KTY != jwkset.KtyRSA && raw.KTY != jwkset.KtyEC && raw.KTY != jwkset.KtyOKP {
+			continue
+		}
+		parsed, err := jwkset.NewJWKFromMarshal(raw, jwkset.JWKMarshalOptions{}, jwkset.JWKValidateOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("JWKS key %d (%q): %w", i, raw.KID, err)
+		}
+		switch parsed.Key().(type) {
+		case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey:
+		default:
+			return nil, fmt.Errorf("JWKS key %d (%q): unsupported %s key", i, raw.KID, raw.KTY)
+		}
+		keys = append(keys, JWK{KeyID: raw.KID, Alg: string(raw.ALG), Key: parsed.Key()})
+	}
+	return keys, nil
+}
@@ -0,0 +1,223 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrInvalidToken is returned for tokens that are malformed, badly signed,
+// expired or not meant for this service.
+var ErrInvalidToken = errors.New("invalid token")
+
+const defaultRoleClaim = "roles"
+
+// JWTVerifier checks bearer tokens and resolves them to principals.
+type JWTVerifier struct {
+	keys      KeySource
+	issuer    string
+	audience  string
+	roleClaim []string
+	roleMap   map[string]Role
+	leeway    time.Duration
+	now       func() time.Time
+}
+
+type JWTOption func(*JWTVerifier)
+
+// WithIssuer requires the iss claim to equal issuer.
+func WithIssuer(issuer string) JWTOption {
+	return func(v *JWTVerifier) {
+		v.issuer = issuer
+	}
+}
+
+// WithAudience requires audience to be among the aud claim.
+func WithAudience(audience string) JWTOption {
+	return func(v *JWTVerifier) {
+		v.audience = audience
+	}
+}
+
+// WithRoleClaim sets the claim roles are read from. Dots reach into nested
+// objects, so "realm_access.roles" reads {"realm_access":{"roles":[...]}}.
+func WithRoleClaim(claim string) JWTOption {
+	return func(v *JWTVerifier) {
+		v.roleClaim = strings.Split(claim, ".")
+	}
+}
+
+// WithRoleMap translates the issuer's role names. Without a map, role names
+// are taken as they are; with one, names it lacks are ignored.
+func WithRoleMap(roles map[string]Role) JWTOption {
+	return func(v *JWTVerifier) {
+		v.roleMap = roles
+	}
+}
+
+// WithLeeway tolerates clock skew between the issuer and this service when
+// checking exp and nbf.
+func WithLeeway(leeway time.Duration) JWTOption {
+	return func(v *JWTVerifier) {
+		v.leeway = leeway
+	}
+}
+
+func NewJWTVerifier(keys KeySource, opts ...JWTOption) *JWTVerifier {
+	v := &JWTVerifier{
+		keys:      keys,
+		roleClaim: []string{defaultRoleClaim},
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// signingMethods are the algorithms tokens may be signed with. The key a
+// token names must also be of the algorithm's type, so a public key can
+// never be used as an HMAC secret.
+var signingMethods = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+	"EdDSA",
+	"HS256", "HS384", "HS512",
+}
+
+// Verify checks token's signature and claims. Errors wrapping
+// ErrInvalidToken mean the token is at fault; others mean the keys could not
+// be loaded.
+func (v *JWTVerifier) Verify(ctx context.Context, token string) (Principal, error) {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(v.leeway),
+		jwt.WithTimeFunc(v.now),
+	}
+	if v.issuer != "" {
+		opts = append(opts, jwt.WithIssuer(v.issuer))
+	}
+	if v.audience != "" {
+		opts = append(opts, jwt.WithAudience(v.audience))
+	}
+
+	// keyErr keeps a failure to load the keys apart from a bad token.
+	var keyErr error
+	claims := jwt.MapClaims{}
+	_, err := jwt.NewParser(opts...).ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		// No header extension is understood, so none may be required.
+		if _, ok := t.Header["crit"]; ok {
+			return nil, errors.New("crit header is not supported")
+		}
+		kid, _ := t.Header["kid"].(string)
+		key, err := v.keys.Key(ctx, kid)
+		if err != nil {
+			keyErr = err
+			return nil, err
+		}
+		if alg := t.Method.Alg(); key.Alg != "" && key.Alg != alg {
+			return nil, fmt.Errorf("key %q is for %s, not %s", key.KeyID, key.Alg, alg)
+		}
+		return key.Key, nil
+	})
+	if keyErr != nil && !errors.Is(keyErr, ErrInvalidToken) {
+		return Principal{}, keyErr
+	}
+	if err != nil {
+		return Principal{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if err := v.checkClaims(claims); err != nil {
+		return Principal{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	subject, _ := claims["sub"].(string)
+	issuer, _ := claims["iss"].(string)
+	roles := v.roles(claims)
+	scopes := ScopesFor(roles)
+	if raw, ok := claims["scope"].(string); ok {
+		for _, field := range strings.Fields(raw) {
+			scope := Scope(field)
+			if slices.Contains(Scopes, scope) && !slices.Contains(scopes, scope) {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return Principal{Subject: tokenSubject(issuer, subject), AuthorID: subject, Roles: roles, Scopes: scopes}, nil
+}
+
+// tokenSubject namespaces a token's sub by its issuer, so that no sub can
+// pass for an API key's subject, or for a user of another issuer, in rate
+// limits, idempotency keys or revision history.
+func tokenSubject(issuer, sub string) string {
+	return "jwt:" + issuer + "|" + sub
+}
+
+// checkClaims checks what the parser leaves alone: every token must name
+// its subject.
+func (v *JWTVerifier) checkClaims(claims jwt.MapClaims) error {
+	if sub, _ := claims["sub"].(string); sub == "" {
+		return errors.New("sub claim is required")
+	}
+	return nil
+}
+
+// roles reads the role claim, which may be an array or a space separated
+// string, and drops names that do not map to a Role.
+func (v *JWTVerifier) roles(claims map[string]any) []Role {
+	var value any = claims
+	for _, name := range v.roleClaim {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = object[name]
+	}
+
+	var roles []Role
+	for _, name := range stringList(value) {
+		var role Role
+		if v.roleMap != nil {
+			mapped, ok := v.roleMap[name]
+			if !ok {
+				continue
+			}
+			role = mapped
+		} else {
+			parsed, err := ParseRole(name)
+			if err != nil {
+				continue
+			}
+			role = parsed
+		}
+		if !slices.Contains(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// stringList reads a claim that may be a single string or an array of
+// strings; strings are split on spaces.
+func stringList(value any) []string {
+	switch value := value.(type) {
+	case string:
+		return strings.Fields(value)
+	case []any:
+		list := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,362 @@
+package auth
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"articles/internal/auth/authtest"
+)
+
+func newTestVerifier(keys KeySource, opts ...JWTOption) *JWTVerifier {
+	opts = append([]JWTOption{WithIssuer(authtest.Issuer), WithAudience(authtest.Audience)}, opts...)
+	return NewJWTVerifier(keys, opts...)
+}
+
+func TestJWTVerifier_AlgorithmsFromJWKSFile(t *testing.T) {
+	signers := []*authtest.Signer{
+		authtest.NewSigner(t, "ec", "ES256"),
+		authtest.NewSigner(t, "rsa", "RS256"),
+		authtest.NewSigner(t, "ed", "EdDSA"),
+	}
+	verifier := newTestVerifier(JWKSFromFile(authtest.WriteJWKS(t, signers...), time.Hour))
+
+	for _, signer := range signers {
+		token := signer.Sign(t, authtest.Claims("user-1", "editor", "unknown"))
+
+		principal, err := verifier.Verify(context.Background(), token)
+		if err != nil {
+			t.Fatalf("%s: Verify returned error: %v", signer.Alg, err)
+		}
+		if principal.Subject != "jwt:"+authtest.Issuer+"|user-1" || principal.AuthorID != "user-1" {
+			t.Fatalf("%s: expected subject of user-1 at the issuer and author user-1, got %+v", signer.Alg, principal)
+		}
+		if !slices.Equal(principal.Roles, []Role{RoleEditor}) {
+			t.Fatalf("%s: expected roles [editor], got %v", signer.Alg, principal.Roles)
+		}
+		if !principal.HasScope(ScopeArticlesWrite) {
+			t.Fatalf("%s: expected editors to get %s, got %v", signer.Alg, ScopeArticlesWrite, principal.Scopes)
+		}
+	}
+}
+
+func TestJWTVerifier_RejectsInvalidTokens(t *testing.T) {
+	signer := authtest.NewSigner(t, "ec", "ES256")
+	other := authtest.NewSigner(t, "ec", "ES256")
+	verifier := newTestVerifier(StaticKey(signer.PublicKey()))
+
+	claims := func(set map[string]any) map[string]any {
+		c := authtest.Claims("user-1", "reader")
+		for k, v := range set {
+			if v == nil {
+				delete(c, k)
+				continue
+			}
+			c[k] = v
+		}
+		return c
+	}
+	past := time.Now().Add(-time.Hour).Unix()
+	payload := strings.Split(signer.Sign(t, claims(nil)), ".")[1]
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"malformed", "not-a-token"},
+		{"wrong key", other.Sign(t, claims(nil))},
+		{"expired", signer.Sign(t, claims(map[string]any{"exp": past}))},
+		{"no exp", signer.Sign(t, claims(map[string]any{"exp": nil}))},
+		{"not yet valid", signer.Sign(t, claims(map[string]any{"nbf": time.Now().Add(time.Hour).Unix()}))},
+		{"wrong issuer", signer.Sign(t, claims(map[string]any{"iss": "https://other.test"}))},
+		{"wrong audience", signer.Sign(t, claims(map[string]any{"aud": []string{"billing"}}))},
+		{"no subject", signer.Sign(t, claims(map[string]any{"sub": nil}))},
+		{"alg none", "eyJhbGciOiJub25lIn0." + payload + "."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := verifier.Verify(context.Background(), tt.token); !errors.Is(err, ErrInvalidToken) {
+				t.Fatalf("expected ErrInvalidToken, got %v", err)
+			}
+		})
+	}
+}
+
+func TestJWTVerifier_SubjectIsNamespaced(t *testing.T) {
+	signer := authtest.NewSigner(t, "", "ES256")
+	verifier := newTestVerifier(StaticKey(signer.PublicKey()))
+
+	principal, err := verifier.Verify(context.Background(), signer.Sign(t, authtest.Claims("apikey:3", "editor")))
+	if err != nil {
+		t.Fatalf("Verify returned error: %v", err)
+	}
+	key := APIKey{ID: 3, Scopes: []Scope{ScopeArticlesWrite}}
+	if principal.Subject == key.Principal().Subject {
+		t.Fatalf("expected a token with sub apikey:3 not to pass for API key 3, got subject %q", principal.Subject)
+	}
+	if principal.AuthorID != "apikey:3" {
+		t.Fatalf("expected the raw sub as author, got %q", principal.AuthorID)
+	}
+}
+
+// hmacToken signs claims with secret under alg, adding header to the JOSE
+// header.
+func hmacToken(t *testing.T, alg string, header, claims map[string]any, secret []byte) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(alg), jwt.MapClaims(claims))
+	for k, v := range header {
+		token.Header[k] = v
+	}
+	signed, err := token.SignedString(secret)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signed
+}
+
+func TestJWTVerifier_RejectsAlgorithmConfusion(t *testing.T) {
+	signer := authtest.NewSigner(t, "rsa", "RS256")
+	der, err := x509.MarshalPKIXPublicKey(signer.PublicKey())
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	verifiers := map[string]*JWTVerifier{
+		"static key": newTestVerifier(StaticKey(signer.PublicKey())),
+		"jwks":       newTestVerifier(JWKSFromFile(authtest.WriteJWKS(t, signer), time.Hour)),
+	}
+	for name, verifier := range verifiers {
+		for _, secret := range [][]byte{der, pemKey} {
+			token := hmacToken(t, "HS256", map[string]any{"kid": "rsa"}, authtest.Claims("user-1", "admin"), secret)
+			if _, err := verifier.Verify(context.Background(), token); !errors.Is(err, ErrInvalidToken) {
+				t.Fatalf("%s: expected ErrInvalidToken for HS256 signed with the RSA public key, got %v", name, err)
+			}
+		}
+	}
+}
+
+func TestJWTVerifier_RejectsUnsignedTokens(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	verifier := newTestVerifier(StaticKey(secret))
+
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims(authtest.Claims("user-1", "admin"))).
+		SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	signed := strings.Split(hmacToken(t, "HS256", nil, authtest.Claims("user-1", "admin"), secret), ".")
+	stripped := signed[0] + "." + signed[1] + "."
+	for _, token := range []string{unsigned, stripped} {
+		if _, err := verifier.Verify(context.Background(), token); !errors.Is(err, ErrInvalidToken) {
+			t.Fatalf("expected ErrInvalidToken for %q, got %v", token, err)
+		}
+	}
+}
+
+func TestJWTVerifier_RejectsCriticalHeaders(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	verifier := newTestVerifier(StaticKey(secret))
+	claims := authtest.Claims("user-1")
+
+	if _, err := verifier.Verify(context.Background(), hmacToken(t, "HS256", nil, claims, secret)); err != nil {
+		t.Fatalf("Verify returned error: %v", err)
+	}
+	token := hmacToken(t, "HS256", map[string]any{"crit": []string{"exp"}, "exp": 1}, claims, secret)
+	if _, err := verifier.Verify(context.Background(), token); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected ErrInvalidToken for a crit header, got %v", err)
+	}
+}
+
+func TestJWKS_KidlessTokenNeedsASingleKey(t *testing.T) {
+	first := authtest.NewSigner(t, "first", "ES256")
+	second := authtest.NewSigner(t, "second", "ES256")
+	kidless := *first
+	kidless.KeyID = ""
+	token := kidless.Sign(t, authtest.Claims("user-1"))
+
+	verifier := newTestVerifier(JWKSFromFile(authtest.WriteJWKS(t, first, second), time.Hour))
+	if _, err := verifier.Verify(context.Background(), token); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected ErrInvalidToken for a token without kid among several keys, got %v", err)
+	}
+
+	verifier = newTestVerifier(JWKSFromFile(authtest.WriteJWKS(t, first), time.Hour))
+	if _, err := verifier.Verify(context.Background(), token); err != nil {
+		t.Fatalf("expected a token without kid to use the only key, got %v", err)
+	}
+}
+
+func TestJWTVerifier_LeewayAndAudienceList(t *testing.T) {
+	signer := authtest.NewSigner(t, "", "ES256")
+	verifier := newTestVerifier(StaticKey(signer.PublicKey()), WithLeeway(time.Minute))
+
+	claims := authtest.Claims("user-1")
+	claims["exp"] = time.Now().Add(-30 * time.Second).Unix()
+	claims["aud"] = []string{"billing", authtest.Audience}
+
+	if _, err := verifier.Verify(context.Background(), signer.Sign(t, claims)); err != nil {
+		t.Fatalf("expected a token expired within the leeway to pass, got %v", err)
+	}
+}
+
+func TestJWTVerifier_RoleClaimAndMap(t *testing.T) {
+	signer := authtest.NewSigner(t, "", "ES256")
+	verifier := newTestVerifier(StaticKey(signer.PublicKey()),
+		WithRoleClaim("realm_access.roles"),
+		WithRoleMap(map[string]Role{"cms-admin": RoleAdmin, "cms-author": RoleAuthor}),
+	)
+
+	claims := authtest.Claims("user-1")
+	delete(claims, "roles")
+	claims["realm_access"] = map[string]any{"roles": []string{"cms-author", "cms-admin", "editor"}}
+	claims["scope"] = "openid articles:read"
+
+	principal, err := verifier.Verify(context.Background(), signer.Sign(t, claims))
+	if err != nil {
+		t.Fatalf("Verify returned error: %v", err)
+	}
+	if want := []Role{RoleAuthor, RoleAdmin}; !slices.Equal(principal.Roles, want) {
+		t.Fatalf("expected roles %v, got %v", want, principal.Roles)
+	}
+
+	claims["realm_access"] = map[string]any{}
+	principal, err = verifier.Verify(context.Background(), signer.Sign(t, claims))
+	if err != nil {
+		t.Fatalf("Verify returned error: %v", err)
+	}
+	if len(principal.Roles) != 0 || !slices.Equal(principal.Scopes, []Scope{ScopeArticlesRead}) {
+		t.Fatalf("expected no roles and scopes from the scope claim, got %+v", principal)
+	}
+}
+
+func TestJWKS_PicksUpRotatedKeys(t *testing.T) {
+	old := authtest.NewSigner(t, "2026-01", "ES256")
+	next := authtest.NewSigner(t, "2026-02", "ES256")
+	path := authtest.WriteJWKS(t, old)
+
+	now := time.Now()
+	keys := JWKSFromFile(path, time.Hour)
+	keys.now = func() time.Time { return now }
+	verifier := newTestVerifier(keys)
+
+	if _, err := verifier.Verify(context.Background(), old.Sign(t, authtest.Claims("user-1"))); err != nil {
+		t.Fatalf("Verify returned error: %v", err)
+	}
+
+	authtest.RewriteJWKS(t, path, next)
+
+	// Right after a fetch an unknown kid does not trigger another one.
+	if _, err := verifier.Verify(context.Background(), next.Sign(t, authtest.Claims("user-1"))); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected ErrInvalidToken before the refetch interval, got %v", err)
+	}
+
+	now = now.Add(jwksMinRefetch)
+	if _, err := verifier.Verify(context.Background(), next.Sign(t, authtest.Claims("user-1"))); err != nil {
+		t.Fatalf("expected the rotated key to be fetched, got %v", err)
+	}
+	if _, err := verifier.Verify(context.Background(), old.Sign(t, authtest.Claims("user-1"))); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected the retired key to be refused, got %v", err)
+	}
+}
+
+func TestJWKS_KeepsKeysWhenRefreshFails(t *testing.T) {
+	signer := authtest.NewSigner(t, "ec", "ES256")
+	path := authtest.WriteJWKS(t, signer)
+
+	now := time.Now()
+	keys := JWKSFromFile(path, time.Minute)
+	keys.now = func() time.Time { return now }
+	verifier := newTestVerifier(keys)
+	token := signer.Sign(t, authtest.Claims("user-1"))
+
+	if _, err := verifier.Verify(context.Background(), token); err != nil {
+		t.Fatalf("Verify returned error: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("{not json"), 0o600); err != nil {
+		t.Fatalf("corrupt JWKS: %v", err)
+	}
+	now = now.Add(time.Hour)
+	if _, err := verifier.Verify(context.Background(), token); err != nil {
+		t.Fatalf("expected cached keys to be kept, got %v", err)
+	}
+}
+
+func TestJWKS_FetchesWithoutBlockingCachedKeys(t *testing.T) {
+	set := authtest.JWKS(t, authtest.NewSigner(t, "ec", "ES256"))
+	var fetches atomic.Int32
+	started, release := make(chan struct{}, 1), make(chan struct{})
+	keys := NewJWKS(func(context.Context) ([]byte, error) {
+		if fetches.Add(1) > 1 {
+			started <- struct{}{}
+			<-release
+		}
+		return set, nil
+	}, time.Hour)
+	now := time.Now()
+	keys.now = func() time.Time { return now }
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := keys.Key(context.Background(), "ec"); err != nil {
+				t.Errorf("Key returned error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := fetches.Load(); got != 1 {
+		t.Fatalf("expected concurrent first uses to share one fetch, got %d", got)
+	}
+
+	now = now.Add(jwksMinRefetch)
+	refetched := make(chan error, 1)
+	go func() {
+		_, err := keys.Key(context.Background(), "rotated")
+		refetched <- err
+	}()
+	<-started
+
+	// The unknown kid's fetch is still running; the cached key must not wait
+	// for it.
+	served := make(chan error, 1)
+	go func() {
+		_, err := keys.Key(context.Background(), "ec")
+		served <- err
+	}()
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Fatalf("Key returned error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a cached key to be served while a fetch is running")
+	}
+
+	close(release)
+	if err := <-refetched; !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected ErrInvalidToken for an unknown key, got %v", err)
+	}
+}
+
+func TestJWKS_NoKeysIsNotATokenError(t *testing.T) {
+	keys := JWKSFromFile(t.TempDir()+"/missing.json", time.Hour)
+
+	_, err := keys.Key(context.Background(), "ec")
+	if err == nil || errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected a load error, got %v", err)
+	}
+}
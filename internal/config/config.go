@@ -0,0 +1,405 @@
+// Package config loads the service configuration from environment variables.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	StorageDatabase = "database"
+	StorageMemory   = "memory"
+
+	SQLiteScheme = "sqlite://"
+
+	TracingNone   = "none"
+	TracingStdout = "stdout"
+	TracingOTLP   = "otlp"
+)
+
+type Config struct {
+	Storage             string
+	HTTP                HTTP
+	Database            Database
+	Tracing             Tracing
+	Auth                Auth
+	RateLimit           RateLimit
+	LogLevel            slog.Level
+	SoftDeleteRetention time.Duration
+	SearchLanguage      string
+	// IdempotencyTTL is how long responses to Idempotency-Key requests are
+	// kept for replay.
+	IdempotencyTTL time.Duration
+	// PublishInterval is how often articles scheduled with publish_at are
+	// checked for being due.
+	PublishInterval time.Duration
+}
+
+type HTTP struct {
+	Port              string
+	ReadHeaderTimeout time.Duration
+	ShutdownTimeout   time.Duration
+	// TrustedProxies are the addresses or CIDR ranges whose X-Forwarded-For
+	// is believed when telling clients apart.
+	TrustedProxies []string
+}
+
+type Database struct {
+	URL             string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	QueryTimeout    time.Duration
+	AutoMigrate     bool
+}
+
+// Auth controls who may call the API.
+type Auth struct {
+	// Enabled requires an API key or bearer token with the right scope for
+	// changes and for reading unpublished articles. It is off by default so
+	// that deployments predating authentication keep working when upgraded.
+	Enabled bool
+	JWT     JWT
+}
+
+// JWT configures bearer tokens. Tokens are verified with exactly one of
+// JWKSURL, JWKSFile, PublicKeyFile and Secret; with none set, bearer tokens
+// are not accepted.
+type JWT struct {
+	Issuer   string
+	Audience string
+	JWKSURL  string
+	JWKSFile string
+	// PublicKeyFile is a PEM public key or certificate.
+	PublicKeyFile string
+	// Secret is an HMAC key for HS256, HS384 and HS512 tokens.
+	Secret string
+	// JWKSRefresh is how often a JWKS is fetched again to pick up rotated
+	// keys.
+	JWKSRefresh time.Duration
+	// RolesClaim is the claim roles are read from; dots reach into nested
+	// objects.
+	RolesClaim string
+	// RoleMap translates the issuer's role names to the service's; empty
+	// takes them as they are.
+	RoleMap map[string]string
+	Leeway  time.Duration
+}
+
+// Configured reports whether a key to verify tokens with is set.
+func (j JWT) Configured() bool {
+	return j.JWKSURL != "" || j.JWKSFile != "" || j.PublicKeyFile != "" || j.Secret != ""
+}
+
+// RateLimit sets the per-client request budgets: Read requests to GET
+// routes and Write changes, each refilled over Window. Auth requests
+// carrying credentials are allowed per client IP, whoever they authenticate
+// as. Zero turns a budget off.
+type RateLimit struct {
+	Read   int
+	Write  int
+	Auth   int
+	Window time.Duration
+}
+
+// Tracing selects where spans go. The OTLP exporter takes its endpoint and
+// headers from the standard OTEL_EXPORTER_OTLP_* variables.
+type Tracing struct {
+	Exporter string
+	// File receives the stdout exporter's output instead of stdout when set.
+	File string
+}
+
+// IsSQLite reports whether URL selects the SQLite driver.
+func (d Database) IsSQLite() bool {
+	return strings.HasPrefix(d.URL, SQLiteScheme)
+}
+
+// SQLitePath is the file path part of a sqlite:// URL.
+func (d Database) SQLitePath() string {
+	return strings.TrimPrefix(d.URL, SQLiteScheme)
+}
+
+// Defaults mirror .env.example.
+const (
+	defaultHTTPPort            = "8080"
+	defaultReadHeaderTimeout   = 5 * time.Second
+	defaultShutdownTimeout     = 5 * time.Second
+	defaultMaxOpenConns        = 10
+	defaultMaxIdleConns        = 5
+	defaultConnMaxLifetime     = 30 * time.Minute
+	defaultQueryTimeout        = 3 * time.Second
+	defaultSoftDeleteRetention = 30 * 24 * time.Hour
+	defaultSearchLanguage      = "english"
+	defaultIdempotencyTTL      = 24 * time.Hour
+	defaultPublishInterval     = 10 * time.Second
+	defaultRateLimitRead       = 300
+	defaultRateLimitWrite      = 60
+	defaultRateLimitAuth       = 600
+	defaultRateLimitWindow     = time.Minute
+	defaultJWKSRefresh         = 15 * time.Minute
+	defaultJWTRolesClaim       = "roles"
+	defaultJWTLeeway           = 30 * time.Second
+
+	// minJWTSecretBytes keeps HMAC secrets at least as long as the SHA-256
+	// output, as RFC 7518 requires.
+	minJWTSecretBytes = 32
+)
+
+// Load reads the configuration through getenv (os.Getenv in production).
+// Every invalid variable is reported in the returned error, not just the
+// first one.
+func Load(getenv func(string) string) (Config, error) {
+	l := &loader{getenv: getenv}
+
+	cfg := Config{
+		Storage: l.oneOf("STORAGE", StorageDatabase, StorageDatabase, StorageMemory),
+		HTTP: HTTP{
+			Port:              l.port("HTTP_PORT", defaultHTTPPort),
+			ReadHeaderTimeout: l.duration("READ_HEADER_TIMEOUT", defaultReadHeaderTimeout),
+			ShutdownTimeout:   l.duration("SHUTDOWN_TIMEOUT", defaultShutdownTimeout),
+			TrustedProxies:    l.addresses("TRUSTED_PROXIES"),
+		},
+		Database: Database{
+			URL:             getenv("DATABASE_URL"),
+			MaxOpenConns:    l.int("DB_MAX_OPEN_CONNS", defaultMaxOpenConns, 1),
+			MaxIdleConns:    l.int("DB_MAX_IDLE_CONNS", defaultMaxIdleConns, 0),
+			ConnMaxLifetime: l.duration("DB_CONN_MAX_LIFE", defaultConnMaxLifetime),
+			QueryTimeout:    l.duration("DB_QUERY_TIMEOUT", defaultQueryTimeout),
+			AutoMigrate:     l.bool("AUTO_MIGRATE", false),
+		},
+		Tracing: Tracing{
+			Exporter: l.oneOf("TRACING_EXPORTER", TracingNone, TracingNone, TracingStdout, TracingOTLP),
+			File:     getenv("TRACING_FILE"),
+		},
+		Auth: Auth{
+			Enabled: l.bool("AUTH_ENABLED", false),
+			JWT: JWT{
+				Issuer:        getenv("JWT_ISSUER"),
+				Audience:      getenv("JWT_AUDIENCE"),
+				JWKSURL:       getenv("JWT_JWKS_URL"),
+				JWKSFile:      getenv("JWT_JWKS_FILE"),
+				PublicKeyFile: getenv("JWT_PUBLIC_KEY_FILE"),
+				Secret:        getenv("JWT_SECRET"),
+				JWKSRefresh:   l.duration("JWT_JWKS_REFRESH", defaultJWKSRefresh),
+				RolesClaim:    l.string("JWT_ROLES_CLAIM", defaultJWTRolesClaim),
+				RoleMap:       l.pairs("JWT_ROLE_MAP"),
+				Leeway:        l.duration("JWT_LEEWAY", defaultJWTLeeway),
+			},
+		},
+		RateLimit: RateLimit{
+			Read:   l.int("RATE_LIMIT_READ", defaultRateLimitRead, 0),
+			Write:  l.int("RATE_LIMIT_WRITE", defaultRateLimitWrite, 0),
+			Auth:   l.int("RATE_LIMIT_AUTH", defaultRateLimitAuth, 0),
+			Window: l.duration("RATE_LIMIT_WINDOW", defaultRateLimitWindow),
+		},
+		LogLevel:            l.level("LOG_LEVEL", slog.LevelInfo),
+		SoftDeleteRetention: l.duration("SOFT_DELETE_RETENTION", defaultSoftDeleteRetention),
+		SearchLanguage:      l.string("SEARCH_LANGUAGE", defaultSearchLanguage),
+		IdempotencyTTL:      l.duration("IDEMPOTENCY_TTL", defaultIdempotencyTTL),
+		PublishInterval:     l.duration("PUBLISH_INTERVAL", defaultPublishInterval),
+	}
+
+	if cfg.Storage == StorageDatabase {
+		if err := validateDatabaseURL(cfg.Database.URL); err != nil {
+			l.errs = append(l.errs, err)
+		}
+	}
+	validateJWT(l, cfg.Auth.JWT)
+	if cfg.Storage == StorageMemory && cfg.Auth.Enabled && !cfg.Auth.JWT.Configured() {
+		// Keys are minted by `api keys create` in another process, which
+		// cannot reach an in-memory store; bearer tokens need no store.
+		l.fail("AUTH_ENABLED", "must be false with STORAGE=%s unless JWT verification is configured", StorageMemory)
+	}
+	if cfg.Database.MaxIdleConns > cfg.Database.MaxOpenConns {
+		l.fail("DB_MAX_IDLE_CONNS", "must not exceed DB_MAX_OPEN_CONNS (%d), got %d", cfg.Database.MaxOpenConns, cfg.Database.MaxIdleConns)
+	}
+
+	if err := errors.Join(l.errs...); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+func validateDatabaseURL(databaseURL string) error {
+	switch {
+	case databaseURL == "":
+		return errors.New("DATABASE_URL is required")
+	case strings.HasPrefix(databaseURL, SQLiteScheme):
+		if strings.TrimPrefix(databaseURL, SQLiteScheme) == "" {
+			return fmt.Errorf("DATABASE_URL %q has no SQLite path", databaseURL)
+		}
+		return nil
+	case strings.HasPrefix(databaseURL, "postgres://"), strings.HasPrefix(databaseURL, "postgresql://"):
+		return nil
+	default:
+		return fmt.Errorf("DATABASE_URL must start with postgres://, postgresql:// or %s", SQLiteScheme)
+	}
+}
+
+func validateJWT(l *loader, jwt JWT) {
+	var sources []string
+	for key, value := range map[string]string{
+		"JWT_JWKS_URL":        jwt.JWKSURL,
+		"JWT_JWKS_FILE":       jwt.JWKSFile,
+		"JWT_PUBLIC_KEY_FILE": jwt.PublicKeyFile,
+		"JWT_SECRET":          jwt.Secret,
+	} {
+		if value != "" {
+			sources = append(sources, key)
+		}
+	}
+	if len(sources) > 1 {
+		slices.Sort(sources)
+		l.errs = append(l.errs, fmt.Errorf("only one of %s may be set", strings.Join(sources, ", ")))
+	}
+	if !jwt.Configured() {
+		return
+	}
+
+	if jwt.Issuer == "" {
+		l.fail("JWT_ISSUER", "is required to accept bearer tokens")
+	}
+	if jwt.Audience == "" {
+		l.fail("JWT_AUDIENCE", "is required to accept bearer tokens")
+	}
+	if jwt.Secret != "" && len(jwt.Secret) < minJWTSecretBytes {
+		l.fail("JWT_SECRET", "must be at least %d bytes", minJWTSecretBytes)
+	}
+	if jwt.JWKSURL != "" && !strings.HasPrefix(jwt.JWKSURL, "https://") && !strings.HasPrefix(jwt.JWKSURL, "http://") {
+		l.fail("JWT_JWKS_URL", "must be an http:// or https:// URL, got %q", jwt.JWKSURL)
+	}
+}
+
+// loader collects parse errors so Load can report all of them together.
+type loader struct {
+	getenv func(string) string
+	errs   []error
+}
+
+func (l *loader) fail(key, format string, args ...any) {
+	l.errs = append(l.errs, fmt.Errorf("%s %s", key, fmt.Sprintf(format, args...)))
+}
+
+func (l *loader) string(key, fallback string) string {
+	if raw := l.getenv(key); raw != "" {
+		return raw
+	}
+	return fallback
+}
+
+func (l *loader) oneOf(key, fallback string, allowed ...string) string {
+	value := l.string(key, fallback)
+	for _, candidate := range allowed {
+		if value == candidate {
+			return value
+		}
+	}
+	l.fail(key, "must be one of %s, got %q", strings.Join(allowed, ", "), value)
+	return fallback
+}
+
+func (l *loader) int(key string, fallback, min int) int {
+	raw := l.getenv(key)
+	if raw == "" {
+		return fallback
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < min {
+		l.fail(key, "must be an integer of at least %d, got %q", min, raw)
+		return fallback
+	}
+	return value
+}
+
+func (l *loader) duration(key string, fallback time.Duration) time.Duration {
+	raw := l.getenv(key)
+	if raw == "" {
+		return fallback
+	}
+	value, err := time.ParseDuration(raw)
+	if err != nil || value <= 0 {
+		l.fail(key, "must be a positive duration such as 5s or 30m, got %q", raw)
+		return fallback
+	}
+	return value
+}
+
+func (l *loader) bool(key string, fallback bool) bool {
+	raw := l.getenv(key)
+	if raw == "" {
+		return fallback
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		l.fail(key, "must be a boolean, got %q", raw)
+		return fallback
+	}
+	return value
+}
+
+// pairs parses a comma separated list of from=to pairs.
+func (l *loader) pairs(key string) map[string]string {
+	raw := l.getenv(key)
+	if raw == "" {
+		return nil
+	}
+	pairs := make(map[string]string)
+	for _, item := range strings.Split(raw, ",") {
+		from, to, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok || from == "" || to == "" {
+			l.fail(key, "must be a comma separated list of from=to pairs, got %q", raw)
+			return nil
+		}
+		pairs[from] = to
+	}
+	return pairs
+}
+
+// addresses parses a comma separated list of IP addresses and CIDR ranges.
+func (l *loader) addresses(key string) []string {
+	raw := l.getenv(key)
+	if raw == "" {
+		return nil
+	}
+	var addresses []string
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		if _, err := netip.ParsePrefix(item); err != nil {
+			if _, err := netip.ParseAddr(item); err != nil {
+				l.fail(key, "must be a comma separated list of IP addresses or CIDR ranges, got %q", item)
+				return nil
+			}
+		}
+		addresses = append(addresses, item)
+	}
+	return addresses
+}
+
+func (l *loader) level(key string, fallback slog.Level) slog.Level {
+	raw := l.getenv(key)
+	if raw == "" {
+		return fallback
+	}
+	var value slog.Level
+	if err := value.UnmarshalText([]byte(raw)); err != nil {
+		l.fail(key, "must be debug, info, warn or error, got %q", raw)
+		return fallback
+	}
+	return value
+}
+
+func (l *loader) port(key, fallback string) string {
+	value := l.string(key, fallback)
+	if n, err := strconv.Atoi(value); err != nil || n < 1 || n > 65535 {
+		l.fail(key, "must be a port number between 1 and 65535, got %q", value)
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,227 @@
+package config
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func env(vars map[string]string) func(string) string {
+	return func(key string) string { return vars[key] }
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := Load(env(map[string]string{
+		"DATABASE_URL": "postgres://localhost/articles",
+	}))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if cfg.Storage != StorageDatabase {
+		t.Fatalf("expected storage %q, got %q", StorageDatabase, cfg.Storage)
+	}
+	if cfg.HTTP.Port != "8080" || cfg.HTTP.ReadHeaderTimeout != 5*time.Second || cfg.HTTP.ShutdownTimeout != 5*time.Second {
+		t.Fatalf("unexpected HTTP defaults: %+v", cfg.HTTP)
+	}
+	want := Database{
+		URL:             "postgres://localhost/articles",
+		MaxOpenConns:    10,
+		MaxIdleConns:    5,
+		ConnMaxLifetime: 30 * time.Minute,
+		QueryTimeout:    3 * time.Second,
+	}
+	if cfg.Database != want {
+		t.Fatalf("expected database defaults %+v, got %+v", want, cfg.Database)
+	}
+	if cfg.Tracing.Exporter != TracingNone {
+		t.Fatalf("expected tracing exporter %q, got %q", TracingNone, cfg.Tracing.Exporter)
+	}
+	if cfg.IdempotencyTTL != 24*time.Hour {
+		t.Fatalf("expected idempotency TTL 24h, got %v", cfg.IdempotencyTTL)
+	}
+	if cfg.PublishInterval != 10*time.Second {
+		t.Fatalf("expected publish interval 10s, got %v", cfg.PublishInterval)
+	}
+	if cfg.Auth.Enabled {
+		t.Fatal("expected auth to be disabled by default")
+	}
+	if want := (RateLimit{Read: 300, Write: 60, Auth: 600, Window: time.Minute}); cfg.RateLimit != want {
+		t.Fatalf("expected rate limit defaults %+v, got %+v", want, cfg.RateLimit)
+	}
+	if cfg.HTTP.TrustedProxies != nil {
+		t.Fatalf("expected no trusted proxies by default, got %v", cfg.HTTP.TrustedProxies)
+	}
+}
+
+func TestLoad_ParsesTuningVariables(t *testing.T) {
+	cfg, err := Load(env(map[string]string{
+		"DATABASE_URL":        "sqlite:///tmp/articles.db",
+		"HTTP_PORT":           "9090",
+		"DB_MAX_OPEN_CONNS":   "20",
+		"DB_MAX_IDLE_CONNS":   "0",
+		"DB_CONN_MAX_LIFE":    "1h",
+		"DB_QUERY_TIMEOUT":    "750ms",
+		"READ_HEADER_TIMEOUT": "2s",
+		"SHUTDOWN_TIMEOUT":    "20s",
+		"AUTO_MIGRATE":        "true",
+		"LOG_LEVEL":           "debug",
+		"IDEMPOTENCY_TTL":     "90m",
+		"PUBLISH_INTERVAL":    "1m",
+		"RATE_LIMIT_READ":     "0",
+		"RATE_LIMIT_WRITE":    "10",
+		"RATE_LIMIT_AUTH":     "20",
+		"RATE_LIMIT_WINDOW":   "10s",
+		"TRUSTED_PROXIES":     "10.0.0.0/8, 192.0.2.1",
+	}))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if cfg.HTTP.Port != "9090" || cfg.HTTP.ReadHeaderTimeout != 2*time.Second || cfg.HTTP.ShutdownTimeout != 20*time.Second {
+		t.Fatalf("unexpected HTTP config: %+v", cfg.HTTP)
+	}
+	db := cfg.Database
+	if db.MaxOpenConns != 20 || db.MaxIdleConns != 0 || db.ConnMaxLifetime != time.Hour || db.QueryTimeout != 750*time.Millisecond || !db.AutoMigrate {
+		t.Fatalf("unexpected database config: %+v", db)
+	}
+	if cfg.LogLevel != slog.LevelDebug {
+		t.Fatalf("expected log level debug, got %v", cfg.LogLevel)
+	}
+	if cfg.IdempotencyTTL != 90*time.Minute {
+		t.Fatalf("expected idempotency TTL 90m, got %v", cfg.IdempotencyTTL)
+	}
+	if cfg.PublishInterval != time.Minute {
+		t.Fatalf("expected publish interval 1m, got %v", cfg.PublishInterval)
+	}
+	if want := (RateLimit{Read: 0, Write: 10, Auth: 20, Window: 10 * time.Second}); cfg.RateLimit != want {
+		t.Fatalf("expected rate limits %+v, got %+v", want, cfg.RateLimit)
+	}
+	if got := strings.Join(cfg.HTTP.TrustedProxies, ","); got != "10.0.0.0/8,192.0.2.1" {
+		t.Fatalf("expected trusted proxies 10.0.0.0/8,192.0.2.1, got %q", got)
+	}
+	if !db.IsSQLite() || db.SQLitePath() != "/tmp/articles.db" {
+		t.Fatalf("expected SQLite path /tmp/articles.db, got %q", db.SQLitePath())
+	}
+}
+
+func TestLoad_ReportsEveryInvalidField(t *testing.T) {
+	_, err := Load(env(map[string]string{
+		"STORAGE":             "redis",
+		"HTTP_PORT":           "http",
+		"DB_MAX_OPEN_CONNS":   "many",
+		"DB_MAX_IDLE_CONNS":   "-1",
+		"DB_CONN_MAX_LIFE":    "forever",
+		"DB_QUERY_TIMEOUT":    "0s",
+		"READ_HEADER_TIMEOUT": "5",
+		"SHUTDOWN_TIMEOUT":    "-5s",
+		"AUTO_MIGRATE":        "maybe",
+		"TRACING_EXPORTER":    "jaeger",
+		"LOG_LEVEL":           "loud",
+		"IDEMPOTENCY_TTL":     "1d",
+		"PUBLISH_INTERVAL":    "often",
+		"RATE_LIMIT_READ":     "-1",
+		"RATE_LIMIT_WRITE":    "lots",
+		"RATE_LIMIT_WINDOW":   "0s",
+		"TRUSTED_PROXIES":     "proxy.local",
+	}))
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	for _, key := range []string{
+		"STORAGE", "HTTP_PORT", "DB_MAX_OPEN_CONNS", "DB_MAX_IDLE_CONNS", "DB_CONN_MAX_LIFE",
+		"DB_QUERY_TIMEOUT", "READ_HEADER_TIMEOUT", "SHUTDOWN_TIMEOUT", "AUTO_MIGRATE", "TRACING_EXPORTER",
+		"LOG_LEVEL", "IDEMPOTENCY_TTL", "PUBLISH_INTERVAL", "RATE_LIMIT_READ", "RATE_LIMIT_WRITE",
+		"RATE_LIMIT_WINDOW", "TRUSTED_PROXIES",
+	} {
+		if !strings.Contains(err.Error(), key) {
+			t.Fatalf("expected error to mention %s, got %v", key, err)
+		}
+	}
+}
+
+func TestLoad_DatabaseURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		vars    map[string]string
+		wantErr bool
+	}{
+		{name: "missing", vars: map[string]string{}, wantErr: true},
+		{name: "unknown scheme", vars: map[string]string{"DATABASE_URL": "mysql://localhost/db"}, wantErr: true},
+		{name: "empty sqlite path", vars: map[string]string{"DATABASE_URL": "sqlite://"}, wantErr: true},
+		{name: "postgresql scheme", vars: map[string]string{"DATABASE_URL": "postgresql://localhost/db"}},
+		{name: "not needed for memory", vars: map[string]string{"STORAGE": "memory"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(env(tt.vars))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error=%t, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoad_MemoryStorageNeedsAuthDisabled(t *testing.T) {
+	_, err := Load(env(map[string]string{"STORAGE": "memory", "AUTH_ENABLED": "true"}))
+	if err == nil || !strings.Contains(err.Error(), "AUTH_ENABLED") {
+		t.Fatalf("expected an AUTH_ENABLED error, got %v", err)
+	}
+}
+
+func TestLoad_JWT(t *testing.T) {
+	cfg, err := Load(env(map[string]string{
+		"STORAGE":         "memory",
+		"JWT_JWKS_FILE":   "/etc/articles/jwks.json",
+		"JWT_ISSUER":      "https://issuer.example",
+		"JWT_AUDIENCE":    "articles",
+		"JWT_ROLES_CLAIM": "realm_access.roles",
+		"JWT_ROLE_MAP":    "cms-admin=admin, cms-author=author",
+	}))
+	if err != nil {
+		t.Fatalf("expected memory storage to be allowed with JWT, got %v", err)
+	}
+
+	jwt := cfg.Auth.JWT
+	if !jwt.Configured() || jwt.JWKSRefresh != 15*time.Minute || jwt.Leeway != 30*time.Second {
+		t.Fatalf("unexpected JWT config: %+v", jwt)
+	}
+	if jwt.RolesClaim != "realm_access.roles" || jwt.RoleMap["cms-admin"] != "admin" || jwt.RoleMap["cms-author"] != "author" {
+		t.Fatalf("unexpected JWT role config: %+v", jwt)
+	}
+}
+
+func TestLoad_JWTInvalid(t *testing.T) {
+	_, err := Load(env(map[string]string{
+		"DATABASE_URL": "postgres://localhost/articles",
+		"JWT_JWKS_URL": "ftp://issuer.example/jwks",
+		"JWT_SECRET":   "short",
+		"JWT_ROLE_MAP": "admin",
+		"JWT_LEEWAY":   "soon",
+	}))
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	for _, want := range []string{
+		"only one of JWT_JWKS_URL, JWT_SECRET", "JWT_ISSUER", "JWT_AUDIENCE", "JWT_SECRET must be", "JWT_JWKS_URL must be", "JWT_ROLE_MAP", "JWT_LEEWAY",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected error to mention %q, got %v", want, err)
+		}
+	}
+}
+
+func TestLoad_IdleConnsMustNotExceedOpenConns(t *testing.T) {
+	_, err := Load(env(map[string]string{
+		"DATABASE_URL":      "postgres://localhost/articles",
+		"DB_MAX_OPEN_CONNS": "2",
+		"DB_MAX_IDLE_CONNS": "4",
+	}))
+	if err == nil || !strings.Contains(err.Error(), "DB_MAX_IDLE_CONNS") {
+		t.Fatalf("expected DB_MAX_IDLE_CONNS error, got %v", err)
+	}
+}
@@ -1,26 +1,129 @@
 package domain
 
 import (
+	"errors"
 	"strings"
 	"time"
+	"unicode"
 )
 
-const MaxTitleLength = 140
+const (
+	MaxTitleLength    = 140
+	MaxSummaryLength  = 500
+	MaxBodyLength     = 100000
+	MaxAuthorIDLength = 64
+)
 
 type Article struct {
-	ID        int64
-	Title     string
+	ID    int64
+	Title string
+	// Slug is unique among every slug any article has had; repositories
+	// make it so by adding a suffix to the one NewArticle derives.
+	Slug string
+	// Body holds Markdown and is stored exactly as submitted.
+	Body     string
+	Summary  string
+	AuthorID string
+	// Tags are normalized (see NormalizeTags) and sorted.
+	Tags   []string
+	Status ArticleStatus
+	// PublishAt is when an article that is not published yet is due to be,
+	// and when a published article was; nil when neither is known.
+	PublishAt *time.Time
+	Version   int64
 	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ArticleInput carries the user-supplied fields of an article.
+type ArticleInput struct {
+	Title    string
+	Body     string
+	Summary  string
+	AuthorID string
+	Tags     []string
+	// Status defaults to StatusDraft.
+	Status    ArticleStatus
+	PublishAt *time.Time
+}
+
+// ArticlePatch carries the fields of an update; nil fields keep their
+// current value. The author of an article never changes.
+type ArticlePatch struct {
+	Title   *string
+	Body    *string
+	Summary *string
+	Tags    *[]string
+	Status  *ArticleStatus
+	// PublishAt set to the zero time clears the schedule.
+	PublishAt *time.Time
 }
 
-func NewArticle(title string) (Article, error) {
-	normalized := strings.TrimSpace(title)
-	if normalized == "" {
-		return Article{}, ErrInvalidTitle
+// NewArticle validates input and returns the normalized article. When only
+// one field is invalid its error is returned as is; several invalid fields
+// come back together as an errors.Join.
+func NewArticle(input ArticleInput) (Article, error) {
+	var errs []error
+
+	title := strings.TrimSpace(input.Title)
+	switch {
+	case title == "":
+		errs = append(errs, ErrInvalidTitle)
+	case len([]rune(title)) > MaxTitleLength:
+		errs = append(errs, ErrTitleTooLong)
+	}
+
+	if len([]rune(input.Body)) > MaxBodyLength {
+		errs = append(errs, ErrBodyTooLong)
+	}
+
+	summary := strings.TrimSpace(input.Summary)
+	if len([]rune(summary)) > MaxSummaryLength {
+		errs = append(errs, ErrSummaryTooLong)
 	}
-	if len([]rune(normalized)) > MaxTitleLength {
-		return Article{}, ErrTitleTooLong
+
+	authorID := strings.TrimSpace(input.AuthorID)
+	if len([]rune(authorID)) > MaxAuthorIDLength || strings.ContainsFunc(authorID, unicode.IsSpace) {
+		errs = append(errs, ErrInvalidAuthorID)
+	}
+
+	tags, err := NormalizeTags(input.Tags)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	status := StatusDraft
+	if input.Status != "" {
+		if status, err = ParseArticleStatus(string(input.Status)); err != nil {
+			errs = append(errs, err)
+		}
 	}
 
-	return Article{Title: normalized}, nil
+	switch len(errs) {
+	case 0:
+	case 1:
+		return Article{}, errs[0]
+	default:
+		return Article{}, errors.Join(errs...)
+	}
+
+	return Article{
+		Title:     title,
+		Slug:      Slugify(title),
+		Body:      input.Body,
+		Summary:   summary,
+		AuthorID:  authorID,
+		Tags:      tags,
+		Status:    status,
+		PublishAt: publishAt(input.PublishAt),
+	}, nil
+}
+
+// publishAt returns t in UTC, or nil for a nil or zero t.
+func publishAt(t *time.Time) *time.Time {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
 }
@@ -1,12 +1,13 @@
 package domain
 
 import (
+	"errors"
 	"strings"
 	"testing"
 )
 
 func TestNewArticle_TrimsAndCreates(t *testing.T) {
-	article, err := NewArticle("  Hello  ")
+	article, err := NewArticle(ArticleInput{Title: "  Hello  "})
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -16,7 +17,7 @@ func TestNewArticle_TrimsAndCreates(t *testing.T) {
 }
 
 func TestNewArticle_InvalidTitle(t *testing.T) {
-	_, err := NewArticle("   ")
+	_, err := NewArticle(ArticleInput{Title: "   "})
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -26,7 +27,7 @@ func TestNewArticle_InvalidTitle(t *testing.T) {
 }
 
 func TestNewArticle_TitleTooLong(t *testing.T) {
-	_, err := NewArticle(strings.Repeat("a", MaxTitleLength+1))
+	_, err := NewArticle(ArticleInput{Title: strings.Repeat("a", MaxTitleLength+1)})
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -34,3 +35,64 @@ func TestNewArticle_TitleTooLong(t *testing.T) {
 		t.Fatalf("expected ErrTitleTooLong, got %v", err)
 	}
 }
+
+func TestNewArticle_CarriesBodySummaryAndAuthor(t *testing.T) {
+	article, err := NewArticle(ArticleInput{
+		Title:    "Hello",
+		Body:     "    indented code\n",
+		Summary:  "  Short  ",
+		AuthorID: " user-1 ",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if article.Body != "    indented code\n" {
+		t.Fatalf("expected body to be kept verbatim, got %q", article.Body)
+	}
+	if article.Summary != "Short" {
+		t.Fatalf("expected summary to be trimmed to %q, got %q", "Short", article.Summary)
+	}
+	if article.AuthorID != "user-1" {
+		t.Fatalf("expected author id to be trimmed to %q, got %q", "user-1", article.AuthorID)
+	}
+}
+
+func TestNewArticle_BodyTooLong(t *testing.T) {
+	_, err := NewArticle(ArticleInput{Title: "Hello", Body: strings.Repeat("a", MaxBodyLength+1)})
+	if err != ErrBodyTooLong {
+		t.Fatalf("expected ErrBodyTooLong, got %v", err)
+	}
+}
+
+func TestNewArticle_SummaryTooLong(t *testing.T) {
+	_, err := NewArticle(ArticleInput{Title: "Hello", Summary: strings.Repeat("a", MaxSummaryLength+1)})
+	if err != ErrSummaryTooLong {
+		t.Fatalf("expected ErrSummaryTooLong, got %v", err)
+	}
+}
+
+func TestNewArticle_InvalidAuthorID(t *testing.T) {
+	for _, authorID := range []string{"two words", strings.Repeat("a", MaxAuthorIDLength+1)} {
+		_, err := NewArticle(ArticleInput{Title: "Hello", AuthorID: authorID})
+		if err != ErrInvalidAuthorID {
+			t.Fatalf("expected ErrInvalidAuthorID for %q, got %v", authorID, err)
+		}
+	}
+}
+
+func TestNewArticle_ReportsEveryInvalidField(t *testing.T) {
+	_, err := NewArticle(ArticleInput{
+		Title:    strings.Repeat("a", MaxTitleLength+1),
+		Summary:  strings.Repeat("a", MaxSummaryLength+1),
+		AuthorID: "two words",
+	})
+
+	for _, want := range []error{ErrTitleTooLong, ErrSummaryTooLong, ErrInvalidAuthorID} {
+		if !errors.Is(err, want) {
+			t.Fatalf("expected %v in %v", want, err)
+		}
+	}
+	if errors.Is(err, ErrBodyTooLong) {
+		t.Fatalf("did not expect ErrBodyTooLong in %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package domain
+
+// MaxBatchSize caps the number of articles created or fetched by one batch
+// call.
+const MaxBatchSize = 100
+
+// BatchMode decides what happens to the valid items of a batch when some
+// items fail validation.
+type BatchMode string
+
+const (
+	// BatchAtomic creates nothing unless every item is valid.
+	BatchAtomic BatchMode = "atomic"
+	// BatchBestEffort creates the valid items and reports the others.
+	BatchBestEffort BatchMode = "best_effort"
+)
+
+// ParseBatchMode maps a client-supplied mode to a BatchMode. An empty value
+// selects BatchAtomic.
+func ParseBatchMode(raw string) (BatchMode, error) {
+	switch mode := BatchMode(raw); mode {
+	case "":
+		return BatchAtomic, nil
+	case BatchAtomic, BatchBestEffort:
+		return mode, nil
+	default:
+		return "", ErrInvalidBatchMode
+	}
+}
+
+// BatchItemResult is the outcome of one item, in request order. Err is nil
+// when Article was created; items of an atomic batch that were valid but not
+// created because of another item carry ErrBatchAborted.
+type BatchItemResult struct {
+	Article Article
+	Err     error
+}
+
+type BatchCreateResult struct {
+	Mode    BatchMode
+	Items   []BatchItemResult
+	Created int
+}
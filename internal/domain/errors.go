@@ -3,8 +3,34 @@ package domain
 import "errors"
 
 var (
-	ErrArticleNotFound = errors.New("article not found")
-	ErrInvalidID       = errors.New("id must be a positive integer")
-	ErrInvalidTitle    = errors.New("title is required")
-	ErrTitleTooLong    = errors.New("title must be at most 140 characters")
+	ErrArticleNotFound   = errors.New("article not found")
+	ErrInvalidID         = errors.New("id must be a positive integer")
+	ErrInvalidTitle      = errors.New("title is required")
+	ErrTitleTooLong      = errors.New("title must be at most 140 characters")
+	ErrBodyTooLong       = errors.New("body must be at most 100000 characters")
+	ErrSummaryTooLong    = errors.New("summary must be at most 500 characters")
+	ErrInvalidAuthorID   = errors.New("author_id must be at most 64 characters without whitespace")
+	ErrInvalidCursor     = errors.New("cursor is invalid")
+	ErrInvalidLimit      = errors.New("limit must be between 1 and 100")
+	ErrVersionConflict   = errors.New("article was modified by another request")
+	ErrInvalidRetention  = errors.New("retention must be a positive duration")
+	ErrInvalidSearch     = errors.New("q is required and must be at most 200 characters")
+	ErrInvalidBatchSize  = errors.New("batch must contain between 1 and 100 items")
+	ErrInvalidBatchMode  = errors.New("mode must be atomic or best_effort")
+	ErrBatchAborted      = errors.New("not created because another item in the batch is invalid")
+	ErrInvalidIDs        = errors.New("ids must be a comma-separated list of 1 to 100 positive integers")
+	ErrInvalidFormat     = errors.New("format must be ndjson or csv")
+	ErrInvalidRecord     = errors.New("record is not a valid article")
+	ErrInvalidCSVHeader  = errors.New("csv header must name a title column")
+	ErrInvalidTag        = errors.New("tags must be 1 to 32 letters, digits or -_.+# characters")
+	ErrTooManyTags       = errors.New("an article can have at most 10 tags")
+	ErrInvalidTagFilter  = errors.New("tag filter takes 1 to 10 valid tags")
+	ErrInvalidTagMatch   = errors.New("tag_match must be all or any")
+	ErrRevisionNotFound  = errors.New("revision not found")
+	ErrInvalidRevision   = errors.New("revision must be a positive integer")
+	ErrInvalidDiffFormat = errors.New("format must be unified or word")
+	ErrInvalidStatus     = errors.New("status must be draft, in_review, published or archived")
+	ErrStatusTransition  = errors.New("article cannot move to that status from its current one")
+	ErrInvalidPublishAt  = errors.New("publish_at can only be set on draft or in_review articles")
+	ErrForbidden         = errors.New("caller is not allowed to make this change")
 )
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// ArticleCursor marks the position of the last article on a page. Articles are
+// ordered newest first, so the next page starts strictly after this key.
+type ArticleCursor struct {
+	CreatedAt time.Time
+	ID        int64
+}
+
+type ListArticlesQuery struct {
+	Limit int
+	After *ArticleCursor
+	Tags  TagFilter
+	// Status keeps only articles with that status; empty keeps all.
+	Status ArticleStatus
+	// Owner, when set, also keeps the articles whose AuthorID it is,
+	// whatever their status.
+	Owner string
+}
+
+type ArticlePage struct {
+	Items      []Article
+	NextCursor *ArticleCursor
+}
+
+// Encode returns the opaque representation handed out to clients.
+func (c ArticleCursor) Encode() string {
+	raw := strconv.FormatInt(c.CreatedAt.UnixNano(), 10) + ":" + strconv.FormatInt(c.ID, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func ParseArticleCursor(encoded string) (ArticleCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return ArticleCursor{}, ErrInvalidCursor
+	}
+
+	rawTime, rawID, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return ArticleCursor{}, ErrInvalidCursor
+	}
+
+	nanos, err := strconv.ParseInt(rawTime, 10, 64)
+	if err != nil {
+		return ArticleCursor{}, ErrInvalidCursor
+	}
+	id, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil || id <= 0 {
+		return ArticleCursor{}, ErrInvalidCursor
+	}
+
+	return ArticleCursor{CreatedAt: time.Unix(0, nanos).UTC(), ID: id}, nil
+}
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestArticleCursor_RoundTrip(t *testing.T) {
+	cursor := ArticleCursor{CreatedAt: time.Date(2025, 12, 17, 19, 38, 28, 991780000, time.UTC), ID: 42}
+
+	got, err := ParseArticleCursor(cursor.Encode())
+	if err != nil {
+		t.Fatalf("ParseArticleCursor returned error: %v", err)
+	}
+	if got.ID != cursor.ID || !got.CreatedAt.Equal(cursor.CreatedAt) {
+		t.Fatalf("expected %+v, got %+v", cursor, got)
+	}
+}
+
+func TestParseArticleCursor_Invalid(t *testing.T) {
+	for _, encoded := range []string{"", "not base64!", "bm9jb2xvbg", "MTIzOmFiYw", "MTIzOjA"} {
+		if _, err := ParseArticleCursor(encoded); err != ErrInvalidCursor {
+			t.Fatalf("expected ErrInvalidCursor for %q, got %v", encoded, err)
+		}
+	}
+}
@@ -1,8 +1,47 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type ArticleRepository interface {
+	// Save and SaveMany store article.Slug, or the first free suffixed form
+	// of it (see UniqueSlug), and return the slug actually stored. They keep
+	// a non-zero CreatedAt and stamp the rest with the current time.
 	Save(ctx context.Context, article Article) (Article, error)
+	// SaveMany inserts articles in a single transaction: either all of them
+	// are stored or none is. The result follows the order of articles.
+	SaveMany(ctx context.Context, articles []Article) ([]Article, error)
 	GetByID(ctx context.Context, id int64) (Article, error)
+	// GetByIDs returns the articles among ids that exist and are not
+	// deleted, in no particular order.
+	GetByIDs(ctx context.Context, ids []int64) ([]Article, error)
+	// GetBySlug finds the live article whose current or former slug is slug.
+	// The returned article carries its current slug.
+	GetBySlug(ctx context.Context, slug string) (Article, error)
+	List(ctx context.Context, query ListArticlesQuery) ([]Article, error)
+	// ListScheduled returns up to limit live in_review articles whose
+	// PublishAt is at or before due, earliest first.
+	ListScheduled(ctx context.Context, due time.Time, limit int) ([]Article, error)
+	// Update persists article if its stored version still equals
+	// article.Version and returns the row with the version incremented. A
+	// changed Slug is made unique as in Save; the old one keeps resolving.
+	Update(ctx context.Context, article Article) (Article, error)
+	// Delete hides the article from every other read; the row is kept until
+	// PurgeDeleted removes it.
+	Delete(ctx context.Context, id int64) error
+	Restore(ctx context.Context, id int64) (Article, error)
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error)
+	// ListTags counts the live articles carrying each tag, most used first.
+	// Tags no live article carries are left out. A non-empty status only
+	// counts the articles in that status.
+	ListTags(ctx context.Context, status ArticleStatus) ([]TagCount, error)
+}
+
+// Transactor runs fn as one unit of work: the repository calls fn makes
+// with the ctx it is given are committed together when fn returns nil and
+// rolled back when it returns an error.
+type Transactor interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
 }
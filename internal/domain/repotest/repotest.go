@@ -0,0 +1,761 @@
+// Package repotest holds the behaviour every domain.ArticleRepository must
+// share. Storage adapters call Run from their own tests so that backends
+// cannot drift apart.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"articles/internal/domain"
+)
+
+// Factory returns an empty repository. It is called once per subtest; any
+// cleanup should be registered with t.Cleanup. Repositories must stamp
+// articles with the wall clock.
+type Factory func(t *testing.T) domain.ArticleRepository
+
+func Run(t *testing.T, newRepo Factory) {
+	t.Helper()
+
+	tests := []struct {
+		name string
+		run  func(t *testing.T, repo domain.ArticleRepository)
+	}{
+		{"SaveAssignsIDAndTimestamps", testSaveAssignsIDAndTimestamps},
+		{"SaveAssignsDistinctIDs", testSaveAssignsDistinctIDs},
+		{"GetByIDReturnsSavedContent", testGetByIDReturnsSavedContent},
+		{"GetByIDNotFound", testGetByIDNotFound},
+		{"UnicodeRoundTrip", testUnicodeRoundTrip},
+		{"ConcurrentSaves", testConcurrentSaves},
+		{"ListNewestFirstWithCursor", testListNewestFirstWithCursor},
+		{"UpdateBumpsVersion", testUpdateBumpsVersion},
+		{"UpdateNotFound", testUpdateNotFound},
+		{"DeleteHidesArticle", testDeleteHidesArticle},
+		{"Restore", testRestore},
+		{"PurgeDeleted", testPurgeDeleted},
+		{"SaveManyKeepsOrder", testSaveManyKeepsOrder},
+		{"SaveKeepsCreatedAt", testSaveKeepsCreatedAt},
+		{"GetByIDsSkipsMissingAndDeleted", testGetByIDsSkipsMissingAndDeleted},
+		{"SearchIfSupported", testSearchIfSupported},
+		{"TagsRoundTrip", testTagsRoundTrip},
+		{"ListFiltersByTags", testListFiltersByTags},
+		{"ListTagsCountsLiveArticles", testListTagsCountsLiveArticles},
+		{"SlugsAreUnique", testSlugsAreUnique},
+		{"GetBySlugFollowsOldSlugs", testGetBySlugFollowsOldSlugs},
+		{"StatusRoundTrip", testStatusRoundTrip},
+		{"ListFiltersByStatus", testListFiltersByStatus},
+		{"ListScheduled", testListScheduled},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.run(t, newRepo(t))
+		})
+	}
+}
+
+func testSaveAssignsIDAndTimestamps(t *testing.T, repo domain.ArticleRepository) {
+	before := time.Now().Add(-time.Minute)
+
+	created, err := repo.Save(context.Background(), domain.Article{Title: "Hello"})
+	if err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+
+	if created.ID <= 0 {
+		t.Fatalf("expected a positive ID, got %d", created.ID)
+	}
+	if created.Title != "Hello" {
+		t.Fatalf("expected title %q, got %q", "Hello", created.Title)
+	}
+	if created.Version != 1 {
+		t.Fatalf("expected version 1, got %d", created.Version)
+	}
+	if created.CreatedAt.Before(before) || created.CreatedAt.After(time.Now().Add(time.Minute)) {
+		t.Fatalf("expected CreatedAt close to now, got %v", created.CreatedAt)
+	}
+	if created.UpdatedAt.IsZero() {
+		t.Fatalf("expected UpdatedAt to be set")
+	}
+}
+
+func testSaveAssignsDistinctIDs(t *testing.T, repo domain.ArticleRepository) {
+	first := mustSave(t, repo, domain.Article{Title: "First"})
+	second := mustSave(t, repo, domain.Article{Title: "Second"})
+
+	if first.ID == second.ID {
+		t.Fatalf("expected distinct IDs, both got %d", first.ID)
+	}
+}
+
+func testGetByIDReturnsSavedContent(t *testing.T, repo domain.ArticleRepository) {
+	saved := mustSave(t, repo, domain.Article{Title: "Hello", Body: "# Heading\n\n    code", Summary: "Short", AuthorID: "user-1"})
+
+	got, err := repo.GetByID(context.Background(), saved.ID)
+	if err != nil {
+		t.Fatalf("GetByID returned error: %v", err)
+	}
+
+	if got.ID != saved.ID || got.Title != saved.Title || got.Body != saved.Body ||
+		got.Summary != saved.Summary || got.AuthorID != saved.AuthorID || got.Version != saved.Version {
+		t.Fatalf("expected %+v, got %+v", saved, got)
+	}
+	if !got.CreatedAt.Equal(saved.CreatedAt) {
+		t.Fatalf("expected CreatedAt %v, got %v", saved.CreatedAt, got.CreatedAt)
+	}
+}
+
+func testGetByIDNotFound(t *testing.T, repo domain.ArticleRepository) {
+	_, err := repo.GetByID(context.Background(), 404)
+	if !errors.Is(err, domain.ErrArticleNotFound) {
+		t.Fatalf("expected ErrArticleNotFound, got %v", err)
+	}
+}
+
+func testUnicodeRoundTrip(t *testing.T, repo domain.ArticleRepository) {
+	titles := []string{
+		"Привет, мир",
+		"日本語のタイトル",
+		"مرحبا بالعالم",
+		"Emoji 🚀🔥 and ZWJ 👩‍💻",
+		"Combining e\u0301 vs \u00e9",
+		strings.Repeat("ж", domain.MaxTitleLength),
+	}
+
+	for _, title := range titles {
+		saved := mustSave(t, repo, domain.Article{Title: title, Body: title})
+
+		got, err := repo.GetByID(context.Background(), saved.ID)
+		if err != nil {
+			t.Fatalf("GetByID returned error: %v", err)
+		}
+		if got.Title != title || got.Body != title {
+			t.Fatalf("expected %q to round-trip, got title %q body %q", title, got.Title, got.Body)
+		}
+	}
+}
+
+func testConcurrentSaves(t *testing.T, repo domain.ArticleRepository) {
+	const writers = 20
+
+	ids := make(chan int64, writers)
+	var wg sync.WaitGroup
+	for range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			article, err := repo.Save(context.Background(), domain.Article{Title: "Concurrent"})
+			if err != nil {
+				t.Errorf("Save returned error: %v", err)
+				return
+			}
+			ids <- article.ID
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	seen := make(map[int64]bool, writers)
+	for id := range ids {
+		if seen[id] {
+			t.Fatalf("duplicate ID %d", id)
+		}
+		seen[id] = true
+	}
+	if len(seen) != writers {
+		t.Fatalf("expected %d unique IDs, got %d", writers, len(seen))
+	}
+}
+
+func testListNewestFirstWithCursor(t *testing.T, repo domain.ArticleRepository) {
+	var saved []domain.Article
+	for _, title := range []string{"First", "Second", "Third", "Fourth", "Fifth"} {
+		saved = append(saved, mustSave(t, repo, domain.Article{Title: title}))
+	}
+	deleted := mustSave(t, repo, domain.Article{Title: "Deleted"})
+	if err := repo.Delete(context.Background(), deleted.ID); err != nil {
+		t.Fatalf("Delete returned error: %v", err)
+	}
+
+	var ids []int64
+	query := domain.ListArticlesQuery{Limit: 2}
+	for pages := 0; ; pages++ {
+		if pages > len(saved) {
+			t.Fatalf("pagination did not terminate, got ids %v", ids)
+		}
+
+		page, err := repo.List(context.Background(), query)
+		if err != nil {
+			t.Fatalf("List returned error: %v", err)
+		}
+		if len(page) > query.Limit {
+			t.Fatalf("expected at most %d articles, got %d", query.Limit, len(page))
+		}
+		for _, article := range page {
+			ids = append(ids, article.ID)
+		}
+		if len(page) < query.Limit {
+			break
+		}
+
+		// Round-trip through the opaque form, as clients do.
+		last := page[len(page)-1]
+		after, err := domain.ParseArticleCursor(domain.ArticleCursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode())
+		if err != nil {
+			t.Fatalf("ParseArticleCursor returned error: %v", err)
+		}
+		query.After = &after
+	}
+
+	if len(ids) != len(saved) {
+		t.Fatalf("expected %d live articles, got ids %v", len(saved), ids)
+	}
+	for i, id := range ids {
+		if want := saved[len(saved)-1-i].ID; id != want {
+			t.Fatalf("expected newest-first order, got ids %v", ids)
+		}
+	}
+}
+
+func testUpdateBumpsVersion(t *testing.T, repo domain.ArticleRepository) {
+	saved := mustSave(t, repo, domain.Article{Title: "Helo", AuthorID: "user-1"})
+
+	changed := saved
+	changed.Title = "Hello"
+	changed.Body = "Body"
+	changed.Summary = "Summary"
+	updated, err := repo.Update(context.Background(), changed)
+	if err != nil {
+		t.Fatalf("Update returned error: %v", err)
+	}
+	if updated.Title != "Hello" || updated.Body != "Body" || updated.Summary != "Summary" {
+		t.Fatalf("expected content to change, got %+v", updated)
+	}
+	if updated.Version != saved.Version+1 {
+		t.Fatalf("expected version %d, got %d", saved.Version+1, updated.Version)
+	}
+	if updated.AuthorID != saved.AuthorID || !updated.CreatedAt.Equal(saved.CreatedAt) {
+		t.Fatalf("expected author and CreatedAt to be kept, got %+v", updated)
+	}
+
+	if _, err := repo.Update(context.Background(), changed); !errors.Is(err, domain.ErrVersionConflict) {
+		t.Fatalf("expected ErrVersionConflict for a stale version, got %v", err)
+	}
+}
+
+func testUpdateNotFound(t *testing.T, repo domain.ArticleRepository) {
+	_, err := repo.Update(context.Background(), domain.Article{ID: 404, Title: "Hello", Version: 1})
+	if !errors.Is(err, domain.ErrArticleNotFound) {
+		t.Fatalf("expected ErrArticleNotFound, got %v", err)
+	}
+}
+
+func testDeleteHidesArticle(t *testing.T, repo domain.ArticleRepository) {
+	saved := mustSave(t, repo, domain.Article{Title: "Hello"})
+
+	if err := repo.Delete(context.Background(), saved.ID); err != nil {
+		t.Fatalf("Delete returned error: %v", err)
+	}
+	if _, err := repo.GetByID(context.Background(), saved.ID); !errors.Is(err, domain.ErrArticleNotFound) {
+		t.Fatalf("expected ErrArticleNotFound after Delete, got %v", err)
+	}
+	if _, err := repo.Update(context.Background(), saved); !errors.Is(err, domain.ErrArticleNotFound) {
+		t.Fatalf("expected Update of a deleted article to return ErrArticleNotFound, got %v", err)
+	}
+	if err := repo.Delete(context.Background(), saved.ID); !errors.Is(err, domain.ErrArticleNotFound) {
+		t.Fatalf("expected second Delete to return ErrArticleNotFound, got %v", err)
+	}
+	if err := repo.Delete(context.Background(), 404); !errors.Is(err, domain.ErrArticleNotFound) {
+		t.Fatalf("expected Delete of a missing article to return ErrArticleNotFound, got %v", err)
+	}
+}
+
+func testRestore(t *testing.T, repo domain.ArticleRepository) {
+	saved := mustSave(t, repo, domain.Article{Title: "Hello"})
+
+	if _, err := repo.Restore(context.Background(), saved.ID); !errors.Is(err, domain.ErrArticleNotFound) {
+		t.Fatalf("expected Restore of a live article to return ErrArticleNotFound, got %v", err)
+	}
+	if err := repo.Delete(context.Background(), saved.ID); err != nil {
+		t.Fatalf("Delete returned error: %v", err)
+	}
+
+	restored, err := repo.Restore(context.Background(), saved.ID)
+	if err != nil {
+		t.Fatalf("Restore returned error: %v", err)
+	}
+	if restored.ID != saved.ID || restored.Title != saved.Title {
+		t.Fatalf("expected %+v back, got %+v", saved, restored)
+	}
+	if restored.Version <= saved.Version {
+		t.Fatalf("expected Restore to bump the version past %d, got %d", saved.Version, restored.Version)
+	}
+	if _, err := repo.GetByID(context.Background(), saved.ID); err != nil {
+		t.Fatalf("expected restored article to be readable, got %v", err)
+	}
+}
+
+func testPurgeDeleted(t *testing.T, repo domain.ArticleRepository) {
+	deleted := mustSave(t, repo, domain.Article{Title: "Gone"})
+	live := mustSave(t, repo, domain.Article{Title: "Kept"})
+	if err := repo.Delete(context.Background(), deleted.ID); err != nil {
+		t.Fatalf("Delete returned error: %v", err)
+	}
+
+	purged, err := repo.PurgeDeleted(context.Background(), time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("PurgeDeleted returned error: %v", err)
+	}
+	if purged != 0 {
+		t.Fatalf("expected a recent deletion to be retained, purged %d", purged)
+	}
+
+	purged, err = repo.PurgeDeleted(context.Background(), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("PurgeDeleted returned error: %v", err)
+	}
+	if purged != 1 {
+		t.Fatalf("expected 1 purged article, got %d", purged)
+	}
+	if _, err := repo.Restore(context.Background(), deleted.ID); !errors.Is(err, domain.ErrArticleNotFound) {
+		t.Fatalf("expected purged article to be gone, got %v", err)
+	}
+	if _, err := repo.GetByID(context.Background(), live.ID); err != nil {
+		t.Fatalf("expected live article to survive the purge, got %v", err)
+	}
+}
+
+func testSaveManyKeepsOrder(t *testing.T, repo domain.ArticleRepository) {
+	titles := []string{"First", "Second", "Third"}
+	articles := make([]domain.Article, 0, len(titles))
+	for _, title := range titles {
+		articles = append(articles, domain.Article{Title: title, AuthorID: "author-1"})
+	}
+
+	saved, err := repo.SaveMany(context.Background(), articles)
+	if err != nil {
+		t.Fatalf("SaveMany returned error: %v", err)
+	}
+	if len(saved) != len(titles) {
+		t.Fatalf("expected %d articles, got %d", len(titles), len(saved))
+	}
+
+	seen := make(map[int64]bool)
+	for i, article := range saved {
+		if article.Title != titles[i] || article.AuthorID != "author-1" {
+			t.Fatalf("expected item %d to be %q, got %+v", i, titles[i], article)
+		}
+		if article.ID <= 0 || seen[article.ID] || article.Version != 1 {
+			t.Fatalf("expected a new distinct ID and version 1, got %+v", article)
+		}
+		seen[article.ID] = true
+
+		got, err := repo.GetByID(context.Background(), article.ID)
+		if err != nil {
+			t.Fatalf("GetByID returned error: %v", err)
+		}
+		if got.Title != article.Title {
+			t.Fatalf("expected stored title %q, got %q", article.Title, got.Title)
+		}
+	}
+
+	empty, err := repo.SaveMany(context.Background(), nil)
+	if err != nil || len(empty) != 0 {
+		t.Fatalf("expected an empty batch to be a no-op, got %v, %v", empty, err)
+	}
+}
+
+func testSaveKeepsCreatedAt(t *testing.T, repo domain.ArticleRepository) {
+	ctx := context.Background()
+	createdAt := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+
+	one, err := repo.Save(ctx, domain.Article{Title: "One", CreatedAt: createdAt})
+	if err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	many, err := repo.SaveMany(ctx, []domain.Article{{Title: "Two", CreatedAt: createdAt}, {Title: "Three"}})
+	if err != nil {
+		t.Fatalf("SaveMany returned error: %v", err)
+	}
+
+	for _, article := range []domain.Article{one, many[0]} {
+		got, err := repo.GetByID(ctx, article.ID)
+		if err != nil {
+			t.Fatalf("GetByID returned error: %v", err)
+		}
+		if !article.CreatedAt.Equal(createdAt) || !got.CreatedAt.Equal(createdAt) {
+			t.Fatalf("expected CreatedAt %v to be kept, got %v and %v", createdAt, article.CreatedAt, got.CreatedAt)
+		}
+		if got.UpdatedAt.Before(time.Now().Add(-time.Minute)) {
+			t.Fatalf("expected UpdatedAt to be stamped now, got %v", got.UpdatedAt)
+		}
+	}
+	if many[1].CreatedAt.Before(time.Now().Add(-time.Minute)) {
+		t.Fatalf("expected a zero CreatedAt to be stamped now, got %v", many[1].CreatedAt)
+	}
+}
+
+func testGetByIDsSkipsMissingAndDeleted(t *testing.T, repo domain.ArticleRepository) {
+	first := mustSave(t, repo, domain.Article{Title: "First"})
+	second := mustSave(t, repo, domain.Article{Title: "Second"})
+	deleted := mustSave(t, repo, domain.Article{Title: "Deleted"})
+	if err := repo.Delete(context.Background(), deleted.ID); err != nil {
+		t.Fatalf("Delete returned error: %v", err)
+	}
+
+	got, err := repo.GetByIDs(context.Background(), []int64{second.ID, 404, deleted.ID, first.ID})
+	if err != nil {
+		t.Fatalf("GetByIDs returned error: %v", err)
+	}
+
+	titles := make(map[int64]string)
+	for _, article := range got {
+		titles[article.ID] = article.Title
+	}
+	if len(got) != 2 || titles[first.ID] != "First" || titles[second.ID] != "Second" {
+		t.Fatalf("expected First and Second only, got %+v", got)
+	}
+}
+
+func testSearchIfSupported(t *testing.T, repo domain.ArticleRepository) {
+	searcher, ok := repo.(domain.ArticleSearcher)
+	if !ok {
+		t.Skip("repository has no native search")
+	}
+
+	inBody := mustSave(t, repo, domain.Article{Title: "Weekly notes", Body: "We tuned the database planner."})
+	inTitle := mustSave(t, repo, domain.Article{Title: "Database tips", Body: "Indexes matter."})
+	deleted := mustSave(t, repo, domain.Article{Title: "Database internals"})
+	if err := repo.Delete(context.Background(), deleted.ID); err != nil {
+		t.Fatalf("Delete returned error: %v", err)
+	}
+	mustSave(t, repo, domain.Article{Title: "Unrelated"})
+
+	results, err := searcher.Search(context.Background(), domain.SearchQuery{Text: "database", Limit: 10})
+	if err != nil {
+		t.Fatalf("Search returned error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %+v", results)
+	}
+	if results[0].Article.ID != inTitle.ID || results[1].Article.ID != inBody.ID {
+		t.Fatalf("expected the title match to rank first, got %+v", results)
+	}
+	if results[0].Rank < results[1].Rank {
+		t.Fatalf("expected descending ranks, got %v then %v", results[0].Rank, results[1].Rank)
+	}
+}
+
+func testTagsRoundTrip(t *testing.T, repo domain.ArticleRepository) {
+	saved := mustSave(t, repo, domain.Article{Title: "Tagged", Tags: []string{"db", "go"}})
+	if !slices.Equal(saved.Tags, []string{"db", "go"}) {
+		t.Fatalf("expected Save to return tags [db go], got %v", saved.Tags)
+	}
+
+	got, err := repo.GetByID(context.Background(), saved.ID)
+	if err != nil {
+		t.Fatalf("GetByID returned error: %v", err)
+	}
+	if !slices.Equal(got.Tags, []string{"db", "go"}) {
+		t.Fatalf("expected tags [db go], got %v", got.Tags)
+	}
+
+	got.Tags = []string{"go", "sql"}
+	updated, err := repo.Update(context.Background(), got)
+	if err != nil {
+		t.Fatalf("Update returned error: %v", err)
+	}
+	if !slices.Equal(updated.Tags, []string{"go", "sql"}) {
+		t.Fatalf("expected Update to return tags [go sql], got %v", updated.Tags)
+	}
+
+	updated.Tags = nil
+	if _, err := repo.Update(context.Background(), updated); err != nil {
+		t.Fatalf("Update returned error: %v", err)
+	}
+	got, err = repo.GetByID(context.Background(), saved.ID)
+	if err != nil {
+		t.Fatalf("GetByID returned error: %v", err)
+	}
+	if len(got.Tags) != 0 {
+		t.Fatalf("expected tags to be cleared, got %v", got.Tags)
+	}
+
+	many, err := repo.SaveMany(context.Background(), []domain.Article{
+		{Title: "One", Tags: []string{"go"}},
+		{Title: "Two"},
+	})
+	if err != nil {
+		t.Fatalf("SaveMany returned error: %v", err)
+	}
+	fetched, err := repo.GetByIDs(context.Background(), []int64{many[0].ID, many[1].ID})
+	if err != nil {
+		t.Fatalf("GetByIDs returned error: %v", err)
+	}
+	for _, article := range fetched {
+		want := []string(nil)
+		if article.ID == many[0].ID {
+			want = []string{"go"}
+		}
+		if !slices.Equal(article.Tags, want) {
+			t.Fatalf("expected article %d to have tags %v, got %v", article.ID, want, article.Tags)
+		}
+	}
+}
+
+func testListFiltersByTags(t *testing.T, repo domain.ArticleRepository) {
+	goOnly := mustSave(t, repo, domain.Article{Title: "Go", Tags: []string{"go"}})
+	both := mustSave(t, repo, domain.Article{Title: "Go and DB", Tags: []string{"db", "go"}})
+	dbOnly := mustSave(t, repo, domain.Article{Title: "DB", Tags: []string{"db"}})
+	mustSave(t, repo, domain.Article{Title: "Untagged"})
+
+	ids := func(filter domain.TagFilter) map[int64]bool {
+		t.Helper()
+		articles, err := repo.List(context.Background(), domain.ListArticlesQuery{Limit: 10, Tags: filter})
+		if err != nil {
+			t.Fatalf("List returned error: %v", err)
+		}
+		found := make(map[int64]bool, len(articles))
+		for _, article := range articles {
+			found[article.ID] = true
+		}
+		return found
+	}
+
+	all := ids(domain.TagFilter{Tags: []string{"db", "go"}, Match: domain.TagMatchAll})
+	if len(all) != 1 || !all[both.ID] {
+		t.Fatalf("expected only article %d to have both tags, got %v", both.ID, all)
+	}
+
+	anyOf := ids(domain.TagFilter{Tags: []string{"db", "go"}, Match: domain.TagMatchAny})
+	if len(anyOf) != 3 || !anyOf[goOnly.ID] || !anyOf[both.ID] || !anyOf[dbOnly.ID] {
+		t.Fatalf("expected the three tagged articles, got %v", anyOf)
+	}
+
+	if none := ids(domain.TagFilter{Tags: []string{"rust"}, Match: domain.TagMatchAny}); len(none) != 0 {
+		t.Fatalf("expected no article tagged rust, got %v", none)
+	}
+	if unfiltered := ids(domain.TagFilter{}); len(unfiltered) != 4 {
+		t.Fatalf("expected an empty filter to match all 4 articles, got %v", unfiltered)
+	}
+}
+
+func testListTagsCountsLiveArticles(t *testing.T, repo domain.ArticleRepository) {
+	mustSave(t, repo, domain.Article{Title: "One", Tags: []string{"db", "go"}, Status: domain.StatusPublished})
+	mustSave(t, repo, domain.Article{Title: "Two", Tags: []string{"go"}, Status: domain.StatusPublished})
+	mustSave(t, repo, domain.Article{Title: "Draft", Tags: []string{"go", "wip"}})
+	deleted := mustSave(t, repo, domain.Article{Title: "Gone", Tags: []string{"db", "rust"}})
+	if err := repo.Delete(context.Background(), deleted.ID); err != nil {
+		t.Fatalf("Delete returned error: %v", err)
+	}
+
+	tags, err := repo.ListTags(context.Background(), "")
+	if err != nil {
+		t.Fatalf("ListTags returned error: %v", err)
+	}
+	want := []domain.TagCount{{Name: "go", Count: 3}, {Name: "db", Count: 1}, {Name: "wip", Count: 1}}
+	if !slices.Equal(tags, want) {
+		t.Fatalf("expected %v, got %v", want, tags)
+	}
+
+	tags, err = repo.ListTags(context.Background(), domain.StatusPublished)
+	if err != nil {
+		t.Fatalf("ListTags returned error: %v", err)
+	}
+	want = []domain.TagCount{{Name: "go", Count: 2}, {Name: "db", Count: 1}}
+	if !slices.Equal(tags, want) {
+		t.Fatalf("expected only published articles to count, got %v", tags)
+	}
+}
+
+func testSlugsAreUnique(t *testing.T, repo domain.ArticleRepository) {
+	first := mustSave(t, repo, domain.Article{Title: "Hello World", Slug: "hello-world"})
+	second := mustSave(t, repo, domain.Article{Title: "Hello, world!", Slug: "hello-world"})
+	if first.Slug != "hello-world" || second.Slug != "hello-world-2" {
+		t.Fatalf("expected slugs hello-world and hello-world-2, got %q and %q", first.Slug, second.Slug)
+	}
+
+	batch, err := repo.SaveMany(context.Background(), []domain.Article{
+		{Title: "Hello World", Slug: "hello-world"},
+		{Title: "Untitled"},
+	})
+	if err != nil {
+		t.Fatalf("SaveMany returned error: %v", err)
+	}
+	if batch[0].Slug != "hello-world-3" || batch[1].Slug != "untitled" {
+		t.Fatalf("expected slugs hello-world-3 and untitled, got %q and %q", batch[0].Slug, batch[1].Slug)
+	}
+
+	got, err := repo.GetByID(context.Background(), second.ID)
+	if err != nil {
+		t.Fatalf("GetByID returned error: %v", err)
+	}
+	if got.Slug != second.Slug {
+		t.Fatalf("expected stored slug %q, got %q", second.Slug, got.Slug)
+	}
+}
+
+func testGetBySlugFollowsOldSlugs(t *testing.T, repo domain.ArticleRepository) {
+	saved := mustSave(t, repo, domain.Article{Title: "Draft", Slug: "draft"})
+
+	renamed := saved
+	renamed.Title = "Final"
+	renamed.Slug = "final"
+	renamed, err := repo.Update(context.Background(), renamed)
+	if err != nil {
+		t.Fatalf("Update returned error: %v", err)
+	}
+	if renamed.Slug != "final" {
+		t.Fatalf("expected slug final, got %q", renamed.Slug)
+	}
+
+	for _, slug := range []string{"draft", "final"} {
+		got, err := repo.GetBySlug(context.Background(), slug)
+		if err != nil {
+			t.Fatalf("GetBySlug(%q) returned error: %v", slug, err)
+		}
+		if got.ID != saved.ID || got.Slug != "final" {
+			t.Fatalf("expected GetBySlug(%q) to find article %d at slug final, got %+v", slug, saved.ID, got)
+		}
+	}
+
+	// The old slug stays with its article rather than going to a new one.
+	other := mustSave(t, repo, domain.Article{Title: "Draft", Slug: "draft"})
+	if other.Slug != "draft-2" {
+		t.Fatalf("expected slug draft-2, got %q", other.Slug)
+	}
+
+	// Renaming back reclaims the article's own old slug.
+	renamed.Slug = "draft"
+	back, err := repo.Update(context.Background(), renamed)
+	if err != nil {
+		t.Fatalf("Update returned error: %v", err)
+	}
+	if back.Slug != "draft" {
+		t.Fatalf("expected slug draft again, got %q", back.Slug)
+	}
+
+	if err := repo.Delete(context.Background(), saved.ID); err != nil {
+		t.Fatalf("Delete returned error: %v", err)
+	}
+	if _, err := repo.GetBySlug(context.Background(), "final"); !errors.Is(err, domain.ErrArticleNotFound) {
+		t.Fatalf("expected ErrArticleNotFound for a deleted article, got %v", err)
+	}
+	if _, err := repo.GetBySlug(context.Background(), "missing"); !errors.Is(err, domain.ErrArticleNotFound) {
+		t.Fatalf("expected ErrArticleNotFound for an unknown slug, got %v", err)
+	}
+}
+
+func testStatusRoundTrip(t *testing.T, repo domain.ArticleRepository) {
+	publishAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	saved := mustSave(t, repo, domain.Article{Title: "Hello", Status: domain.StatusInReview, PublishAt: &publishAt})
+	if saved.Status != domain.StatusInReview || saved.PublishAt == nil || !saved.PublishAt.Equal(publishAt) {
+		t.Fatalf("expected in_review due at %v, got %+v", publishAt, saved)
+	}
+
+	got, err := repo.GetByID(context.Background(), saved.ID)
+	if err != nil {
+		t.Fatalf("GetByID returned error: %v", err)
+	}
+	if got.Status != domain.StatusInReview || got.PublishAt == nil || !got.PublishAt.Equal(publishAt) {
+		t.Fatalf("expected in_review due at %v, got %+v", publishAt, got)
+	}
+
+	got.Status = domain.StatusPublished
+	got.PublishAt = nil
+	updated, err := repo.Update(context.Background(), got)
+	if err != nil {
+		t.Fatalf("Update returned error: %v", err)
+	}
+	if updated.Status != domain.StatusPublished || updated.PublishAt != nil {
+		t.Fatalf("expected published without publish_at, got %+v", updated)
+	}
+
+	// Articles saved without a status are drafts.
+	draft := mustSave(t, repo, domain.Article{Title: "Draft"})
+	if draft.Status != domain.StatusDraft {
+		t.Fatalf("expected status draft, got %q", draft.Status)
+	}
+}
+
+func testListFiltersByStatus(t *testing.T, repo domain.ArticleRepository) {
+	mustSave(t, repo, domain.Article{Title: "Draft", Status: domain.StatusDraft})
+	published := mustSave(t, repo, domain.Article{Title: "Published", Status: domain.StatusPublished})
+
+	got, err := repo.List(context.Background(), domain.ListArticlesQuery{Limit: 10, Status: domain.StatusPublished})
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != published.ID {
+		t.Fatalf("expected only article %d, got %+v", published.ID, got)
+	}
+
+	all, err := repo.List(context.Background(), domain.ListArticlesQuery{Limit: 10})
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("expected both articles without a status filter, got %d", len(all))
+	}
+
+	own := mustSave(t, repo, domain.Article{Title: "Own draft", AuthorID: "author-1", Status: domain.StatusDraft})
+	mustSave(t, repo, domain.Article{Title: "No author", Status: domain.StatusDraft})
+	owned, err := repo.List(context.Background(), domain.ListArticlesQuery{Limit: 10, Status: domain.StatusPublished, Owner: "author-1"})
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if len(owned) != 2 || owned[0].ID != own.ID || owned[1].ID != published.ID {
+		t.Fatalf("expected the owner's draft and article %d, got %+v", published.ID, owned)
+	}
+}
+
+func testListScheduled(t *testing.T, repo domain.ArticleRepository) {
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		when := now.Add(d)
+		return &when
+	}
+
+	later := mustSave(t, repo, domain.Article{Title: "Later", Status: domain.StatusInReview, PublishAt: at(-time.Minute)})
+	earlier := mustSave(t, repo, domain.Article{Title: "Earlier", Status: domain.StatusInReview, PublishAt: at(-time.Hour)})
+	mustSave(t, repo, domain.Article{Title: "Future", Status: domain.StatusInReview, PublishAt: at(time.Minute)})
+	mustSave(t, repo, domain.Article{Title: "Unscheduled", Status: domain.StatusInReview})
+	mustSave(t, repo, domain.Article{Title: "Draft", Status: domain.StatusDraft, PublishAt: at(-time.Hour)})
+	deleted := mustSave(t, repo, domain.Article{Title: "Deleted", Status: domain.StatusInReview, PublishAt: at(-time.Hour)})
+	if err := repo.Delete(context.Background(), deleted.ID); err != nil {
+		t.Fatalf("Delete returned error: %v", err)
+	}
+
+	got, err := repo.ListScheduled(context.Background(), now, 10)
+	if err != nil {
+		t.Fatalf("ListScheduled returned error: %v", err)
+	}
+	if len(got) != 2 || got[0].ID != earlier.ID || got[1].ID != later.ID {
+		t.Fatalf("expected articles %d and %d, got %+v", earlier.ID, later.ID, got)
+	}
+
+	got, err = repo.ListScheduled(context.Background(), now, 1)
+	if err != nil {
+		t.Fatalf("ListScheduled returned error: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != earlier.ID {
+		t.Fatalf("expected only article %d, got %+v", earlier.ID, got)
+	}
+}
+
+func mustSave(t *testing.T, repo domain.ArticleRepository, article domain.Article) domain.Article {
+	t.Helper()
+
+	saved, err := repo.Save(context.Background(), article)
+	if err != nil {
+		t.Fatalf("seed Save returned error: %v", err)
+	}
+	return saved
+}
@@ -0,0 +1,118 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"articles/internal/domain"
+)
+
+// RevisionFactory returns an empty article repository and the revision
+// repository recording its articles' revisions.
+type RevisionFactory func(t *testing.T) (domain.ArticleRepository, domain.RevisionRepository)
+
+// RunRevisions holds the behaviour every domain.RevisionRepository must
+// share.
+func RunRevisions(t *testing.T, newRepos RevisionFactory) {
+	t.Helper()
+
+	tests := []struct {
+		name string
+		run  func(t *testing.T, articles domain.ArticleRepository, revisions domain.RevisionRepository)
+	}{
+		{"ListOldestFirst", testRevisionsListOldestFirst},
+		{"DuplicateIsSkipped", testRevisionDuplicateIsSkipped},
+		{"GetRevision", testGetRevision},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			articles, revisions := newRepos(t)
+			tc.run(t, articles, revisions)
+		})
+	}
+}
+
+func testRevisionsListOldestFirst(t *testing.T, articles domain.ArticleRepository, revisions domain.RevisionRepository) {
+	article := mustSave(t, articles, domain.Article{Title: "Hello"})
+	other := mustSave(t, articles, domain.Article{Title: "Other"})
+	at := time.Unix(1700000000, 0).UTC()
+
+	err := revisions.SaveRevisions(context.Background(), []domain.Revision{
+		{ArticleID: article.ID, Number: 2, Title: "Hello again", Tags: []string{"db", "go"}, ChangedBy: "user-2", CreatedAt: at.Add(time.Minute)},
+		{ArticleID: article.ID, Number: 1, Title: "Hello", Body: "Body", Summary: "Sum", Slug: "hello", CreatedAt: at},
+		{ArticleID: other.ID, Number: 1, Title: "Other", CreatedAt: at},
+	})
+	if err != nil {
+		t.Fatalf("SaveRevisions returned error: %v", err)
+	}
+
+	got, err := revisions.ListRevisions(context.Background(), article.ID)
+	if err != nil {
+		t.Fatalf("ListRevisions returned error: %v", err)
+	}
+	if len(got) != 2 || got[0].Number != 1 || got[1].Number != 2 {
+		t.Fatalf("expected revisions 1 and 2 of article %d, got %+v", article.ID, got)
+	}
+	first := got[0]
+	if first.ArticleID != article.ID || first.Title != "Hello" || first.Body != "Body" || first.Summary != "Sum" ||
+		first.Slug != "hello" || len(first.Tags) != 0 || !first.CreatedAt.Equal(at) {
+		t.Fatalf("unexpected first revision: %+v", first)
+	}
+	if !slices.Equal(got[1].Tags, []string{"db", "go"}) || got[1].ChangedBy != "user-2" {
+		t.Fatalf("unexpected second revision: %+v", got[1])
+	}
+
+	none, err := revisions.ListRevisions(context.Background(), 404)
+	if err != nil {
+		t.Fatalf("ListRevisions returned error: %v", err)
+	}
+	if len(none) != 0 {
+		t.Fatalf("expected no revisions for an unknown article, got %+v", none)
+	}
+}
+
+func testRevisionDuplicateIsSkipped(t *testing.T, articles domain.ArticleRepository, revisions domain.RevisionRepository) {
+	article := mustSave(t, articles, domain.Article{Title: "Hello"})
+	at := time.Unix(1700000000, 0).UTC()
+
+	for _, title := range []string{"First", "Second"} {
+		err := revisions.SaveRevisions(context.Background(), []domain.Revision{{ArticleID: article.ID, Number: 1, Title: title, CreatedAt: at}})
+		if err != nil {
+			t.Fatalf("SaveRevisions returned error: %v", err)
+		}
+	}
+
+	got, err := revisions.ListRevisions(context.Background(), article.ID)
+	if err != nil {
+		t.Fatalf("ListRevisions returned error: %v", err)
+	}
+	if len(got) != 1 || got[0].Title != "First" {
+		t.Fatalf("expected the first revision to be kept as is, got %+v", got)
+	}
+}
+
+func testGetRevision(t *testing.T, articles domain.ArticleRepository, revisions domain.RevisionRepository) {
+	article := mustSave(t, articles, domain.Article{Title: "Hello"})
+	at := time.Unix(1700000000, 0).UTC()
+
+	err := revisions.SaveRevisions(context.Background(), []domain.Revision{{ArticleID: article.ID, Number: 1, Title: "Hello", Tags: []string{"go"}, CreatedAt: at}})
+	if err != nil {
+		t.Fatalf("SaveRevisions returned error: %v", err)
+	}
+
+	got, err := revisions.GetRevision(context.Background(), article.ID, 1)
+	if err != nil {
+		t.Fatalf("GetRevision returned error: %v", err)
+	}
+	if got.Title != "Hello" || !slices.Equal(got.Tags, []string{"go"}) {
+		t.Fatalf("unexpected revision: %+v", got)
+	}
+
+	if _, err := revisions.GetRevision(context.Background(), article.ID, 2); !errors.Is(err, domain.ErrRevisionNotFound) {
+		t.Fatalf("expected ErrRevisionNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Revision is the content of an article as one change left it. Revisions are
+// never modified; Number is the article version the change produced.
+type Revision struct {
+	ArticleID int64
+	Number    int64
+	Title     string
+	Slug      string
+	Body      string
+	Summary   string
+	Tags      []string
+	// ChangedBy identifies who made the change, empty when unknown.
+	ChangedBy string
+	CreatedAt time.Time
+}
+
+// RevisionRepository stores revisions. It only ever appends.
+type RevisionRepository interface {
+	// SaveRevisions stores revisions; a revision whose article and number
+	// are already stored is skipped.
+	SaveRevisions(ctx context.Context, revisions []Revision) error
+	// ListRevisions returns the revisions of an article, oldest first.
+	ListRevisions(ctx context.Context, articleID int64) ([]Revision, error)
+	GetRevision(ctx context.Context, articleID, number int64) (Revision, error)
+}
+
+type DiffFormat string
+
+const (
+	// DiffUnified compares fields line by line, as diff -u does.
+	DiffUnified DiffFormat = "unified"
+	// DiffWords compares fields word by word.
+	DiffWords DiffFormat = "word"
+)
+
+// ParseDiffFormat maps a client-supplied format name to a DiffFormat. An
+// empty value selects DiffUnified.
+func ParseDiffFormat(raw string) (DiffFormat, error) {
+	switch format := DiffFormat(raw); format {
+	case "":
+		return DiffUnified, nil
+	case DiffUnified, DiffWords:
+		return format, nil
+	default:
+		return "", ErrInvalidDiffFormat
+	}
+}
+
+type DiffOp string
+
+const (
+	DiffEqual  DiffOp = "equal"
+	DiffInsert DiffOp = "insert"
+	DiffDelete DiffOp = "delete"
+)
+
+// DiffSpan is a run of text that is kept, inserted or deleted.
+type DiffSpan struct {
+	Op   DiffOp
+	Text string
+}
+
+// FieldDiff is the change to one field. Unified is set for DiffUnified and
+// Spans for DiffWords.
+type FieldDiff struct {
+	Field   string
+	Unified string
+	Spans   []DiffSpan
+}
+
+// RevisionDiff lists the fields that differ between two revisions.
+type RevisionDiff struct {
+	ArticleID int64
+	From      int64
+	To        int64
+	Format    DiffFormat
+	Fields    []FieldDiff
+}
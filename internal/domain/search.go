@@ -0,0 +1,29 @@
+package domain
+
+import "context"
+
+const MaxSearchQueryLength = 200
+
+type SearchQuery struct {
+	Text  string
+	Limit int
+	// Status keeps only articles with that status; empty keeps all.
+	Status ArticleStatus
+	// Owner, when set, also keeps the articles whose AuthorID it is,
+	// whatever their status.
+	Owner string
+}
+
+type SearchResult struct {
+	Article Article
+	Rank    float64
+	// Snippet is an HTML-escaped excerpt of the article with matched terms
+	// wrapped in <b></b>, safe to insert into a page as it is.
+	Snippet string
+}
+
+// ArticleSearcher is implemented by repositories that can rank matches
+// natively. Repositories without it are searched in process.
+type ArticleSearcher interface {
+	Search(ctx context.Context, query SearchQuery) ([]SearchResult, error)
+}
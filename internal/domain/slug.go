@@ -0,0 +1,146 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxSlugLength bounds the slug derived from a title, in bytes; a collision
+// suffix may add a few characters to it.
+const MaxSlugLength = 80
+
+// fallbackSlug is used for titles without a single letter or digit, such as
+// emoji.
+const fallbackSlug = "article"
+
+// transliterations spells letters that do not decompose into ASCII. Other
+// Latin letters lose their diacritics through Unicode decomposition.
+var transliterations = map[rune]string{
+	// Apostrophes join rather than split words: "don't" becomes "dont".
+	'\'': "", '’': "",
+
+	// Latin letters without a decomposition.
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'ł': "l", 'đ': "d", 'ð': "d", 'þ': "th", 'ı': "i",
+
+	// Cyrillic (Russian, Ukrainian, Belarusian).
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya", 'є': "ye", 'і': "i", 'ї': "yi", 'ґ': "g", 'ў': "u",
+
+	// Greek.
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i", 'θ': "th",
+	'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p",
+	'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps",
+	'ω': "o",
+}
+
+// Slugify derives a URL-safe slug from title: words of lowercase letters and
+// digits separated by single hyphens, at most MaxSlugLength bytes long.
+// Latin, Cyrillic and Greek are spelled in ASCII; letters of other scripts,
+// such as Chinese or Arabic, are kept as they are and percent-encoded in
+// URLs. It does not make the slug unique; see UniqueSlug.
+func Slugify(title string) string {
+	var b strings.Builder
+	hyphen := false
+	// kept is set after a letter of a script without an ASCII spelling,
+	// whose combining marks belong to the word.
+	kept := false
+	write := func(r rune) {
+		if hyphen && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		hyphen = false
+		b.WriteRune(r)
+	}
+	emit := func(r rune) {
+		switch {
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			write(r)
+			kept = false
+		case r < utf8.RuneSelf:
+			hyphen = true
+		case unicode.In(r, unicode.Mn, unicode.Mc):
+			if kept {
+				write(r)
+			}
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			write(r)
+			kept = true
+		default:
+			hyphen = true
+		}
+	}
+	spell := func(r rune) bool {
+		spelled, ok := transliterations[r]
+		for _, s := range spelled {
+			emit(s)
+		}
+		if ok {
+			kept = false
+		}
+		return ok
+	}
+
+	for _, r := range strings.ToLower(title) {
+		if spell(r) {
+			continue
+		}
+		// Decomposition splits "é" into "e" and a combining accent, which
+		// emit drops without breaking the word. Letters that do not start
+		// with an ASCII one are kept whole, in their compatibility form.
+		decomposed := norm.NFKD.String(string(r))
+		first, _ := utf8.DecodeRuneInString(decomposed)
+		if _, ok := transliterations[first]; first >= utf8.RuneSelf && !ok {
+			decomposed = norm.NFKC.String(string(r))
+		}
+		for _, d := range decomposed {
+			if !spell(d) {
+				emit(d)
+			}
+		}
+	}
+
+	// Marks kept apart from their letter, as in half-width kana, join it.
+	slug := norm.NFC.String(b.String())
+	if len(slug) > MaxSlugLength {
+		cut := MaxSlugLength
+		for !utf8.RuneStart(slug[cut]) {
+			cut--
+		}
+		// Cut between words when the slug has more than one, else between
+		// letters.
+		if i := strings.LastIndexByte(slug[:cut+1], '-'); i > 0 {
+			cut = i
+		}
+		slug = strings.TrimSuffix(slug[:cut], "-")
+	}
+	if slug == "" {
+		return fallbackSlug
+	}
+	return slug
+}
+
+// UniqueSlug returns base if it is free, otherwise the first of base-2,
+// base-3, ... that taken does not report.
+func UniqueSlug(base string, taken func(slug string) bool) string {
+	slug := base
+	for n := 2; taken(slug); n++ {
+		slug = base + "-" + strconv.Itoa(n)
+	}
+	return slug
+}
+
+// BaseSlug is the slug a repository makes unique when storing a: its Slug,
+// or one derived from its title when it has none.
+func (a Article) BaseSlug() string {
+	if a.Slug != "" {
+		return a.Slug
+	}
+	return Slugify(a.Title)
+}
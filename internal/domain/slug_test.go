@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSlugify(t *testing.T) {
+	for _, tc := range []struct {
+		title string
+		want  string
+	}{
+		{"Hello, World!", "hello-world"},
+		{"  I'm NARUTO UZUMAKI  ", "im-naruto-uzumaki"},
+		{"Crème brûlée à la française", "creme-brulee-a-la-francaise"},
+		{"Straße & Ærø", "strasse-aero"},
+		{"Привет, мир", "privet-mir"},
+		{"Щедрість і їжа", "shchedrist-i-yizha"},
+		{"Καλημέρα κόσμε", "kalimera-kosme"},
+		{"Go 1.25 released", "go-1-25-released"},
+		{"--already--slugged--", "already-slugged"},
+		{"ｆｕｌｌｗｉｄｔｈ ﬁle", "fullwidth-file"},
+		{"日本語のブログ", "日本語のブログ"},
+		{"Go 言語 入門", "go-言語-入門"},
+		{"한국어 제목", "한국어-제목"},
+		{"ﾌﾞﾛｸﾞ", "ブログ"},
+		{"नमस्ते दुनिया", "नमस्ते-दुनिया"},
+		{"مرحبا بالعالم", "مرحبا-بالعالم"},
+		{"Café 東京", "cafe-東京"},
+		{"🚀", "article"},
+		{"🚀 ✨", "article"},
+	} {
+		if got := Slugify(tc.title); got != tc.want {
+			t.Fatalf("Slugify(%q): expected %q, got %q", tc.title, tc.want, got)
+		}
+		if again := Slugify(tc.want); again != tc.want {
+			t.Fatalf("expected slug %q to slugify to itself, got %q", tc.want, again)
+		}
+	}
+}
+
+func TestSlugify_TruncatesAtWordBoundary(t *testing.T) {
+	title := strings.Repeat("abcdefghi ", 20)
+
+	got := Slugify(title)
+	if len(got) > MaxSlugLength || strings.HasSuffix(got, "-") {
+		t.Fatalf("expected at most %d characters without a trailing hyphen, got %q", MaxSlugLength, got)
+	}
+	if !strings.HasSuffix(got, "abcdefghi") {
+		t.Fatalf("expected the cut to fall between words, got %q", got)
+	}
+}
+
+func TestSlugify_TruncatesOtherScriptsAtRuneBoundary(t *testing.T) {
+	got := Slugify(strings.Repeat("日本", 30))
+
+	if len(got) > MaxSlugLength || !utf8.ValidString(got) {
+		t.Fatalf("expected at most %d bytes of valid UTF-8, got %q", MaxSlugLength, got)
+	}
+	if want := strings.Repeat("日本", 13); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestUniqueSlug(t *testing.T) {
+	taken := map[string]bool{"hello": true, "hello-2": true}
+
+	if got := UniqueSlug("hello", func(s string) bool { return taken[s] }); got != "hello-3" {
+		t.Fatalf("expected hello-3, got %q", got)
+	}
+	if got := UniqueSlug("fresh", func(s string) bool { return taken[s] }); got != "fresh" {
+		t.Fatalf("expected fresh, got %q", got)
+	}
+}
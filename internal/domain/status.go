@@ -0,0 +1,45 @@
+package domain
+
+import "slices"
+
+// ArticleStatus is where an article stands in the editorial workflow. Only
+// published articles are shown to unauthenticated readers.
+type ArticleStatus string
+
+const (
+	StatusDraft     ArticleStatus = "draft"
+	StatusInReview  ArticleStatus = "in_review"
+	StatusPublished ArticleStatus = "published"
+	StatusArchived  ArticleStatus = "archived"
+)
+
+// statusTransitions lists the statuses each status may change to. The empty
+// status stands for an article that is being created.
+var statusTransitions = map[ArticleStatus][]ArticleStatus{
+	"":              {StatusDraft, StatusInReview},
+	StatusDraft:     {StatusInReview},
+	StatusInReview:  {StatusDraft, StatusPublished},
+	StatusPublished: {StatusArchived},
+	StatusArchived:  nil,
+}
+
+// ParseArticleStatus maps a client-supplied status to an ArticleStatus.
+func ParseArticleStatus(raw string) (ArticleStatus, error) {
+	status := ArticleStatus(raw)
+	if _, ok := statusTransitions[status]; !ok || status == "" {
+		return "", ErrInvalidStatus
+	}
+	return status, nil
+}
+
+// CanTransition reports whether an article with status from may be given
+// status to; a from of "" asks whether an article may be created with it.
+func CanTransition(from, to ArticleStatus) bool {
+	return slices.Contains(statusTransitions[from], to)
+}
+
+// Schedulable reports whether an article with status s may be given a
+// publish_at in the future.
+func (s ArticleStatus) Schedulable() bool {
+	return s == StatusDraft || s == StatusInReview
+}
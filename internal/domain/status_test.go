@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCanTransition(t *testing.T) {
+	for _, tc := range []struct {
+		from, to ArticleStatus
+		want     bool
+	}{
+		{"", StatusDraft, true},
+		{"", StatusInReview, true},
+		{"", StatusPublished, false},
+		{StatusDraft, StatusInReview, true},
+		{StatusDraft, StatusPublished, false},
+		{StatusInReview, StatusDraft, true},
+		{StatusInReview, StatusPublished, true},
+		{StatusPublished, StatusArchived, true},
+		{StatusPublished, StatusDraft, false},
+		{StatusArchived, StatusPublished, false},
+	} {
+		if got := CanTransition(tc.from, tc.to); got != tc.want {
+			t.Fatalf("CanTransition(%q, %q): expected %v, got %v", tc.from, tc.to, tc.want, got)
+		}
+	}
+}
+
+func TestParseArticleStatus(t *testing.T) {
+	if got, err := ParseArticleStatus("in_review"); err != nil || got != StatusInReview {
+		t.Fatalf("expected in_review, got %q, %v", got, err)
+	}
+	for _, raw := range []string{"", "live", "Published"} {
+		if _, err := ParseArticleStatus(raw); !errors.Is(err, ErrInvalidStatus) {
+			t.Fatalf("ParseArticleStatus(%q): expected ErrInvalidStatus, got %v", raw, err)
+		}
+	}
+}
+
+func TestNewArticle_DefaultsToDraft(t *testing.T) {
+	article, err := NewArticle(ArticleInput{Title: "Hello"})
+	if err != nil {
+		t.Fatalf("NewArticle returned error: %v", err)
+	}
+	if article.Status != StatusDraft {
+		t.Fatalf("expected status draft, got %q", article.Status)
+	}
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	MaxTagLength      = 32
+	MaxTagsPerArticle = 10
+)
+
+// TagMatch decides whether a tag filter needs every tag or any of them.
+type TagMatch string
+
+const (
+	TagMatchAll TagMatch = "all"
+	TagMatchAny TagMatch = "any"
+)
+
+// TagFilter restricts a listing to articles carrying Tags. An empty filter
+// matches every article.
+type TagFilter struct {
+	Tags  []string
+	Match TagMatch
+}
+
+type TagCount struct {
+	Name  string
+	Count int64
+}
+
+// NormalizeTag lowercases and trims tag and checks it is 1 to MaxTagLength
+// letters, digits or one of "-_.+#".
+func NormalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" || len([]rune(tag)) > MaxTagLength {
+		return "", ErrInvalidTag
+	}
+	for _, r := range tag {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("-_.+#", r) {
+			return "", ErrInvalidTag
+		}
+	}
+	return tag, nil
+}
+
+// NormalizeTags normalizes every tag and returns them sorted without
+// duplicates, or nil when there are none.
+func NormalizeTags(tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag, err := NormalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	if len(normalized) > MaxTagsPerArticle {
+		return nil, ErrTooManyTags
+	}
+
+	sort.Strings(normalized)
+	return normalized, nil
+}
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want string
+		err  error
+	}{
+		{"  Go ", "go", nil},
+		{"c++", "c++", nil},
+		{"c#", "c#", nil},
+		{"Über", "über", nil},
+		{"", "", ErrInvalidTag},
+		{"two words", "", ErrInvalidTag},
+		{"comma,separated", "", ErrInvalidTag},
+		{strings.Repeat("a", MaxTagLength), strings.Repeat("a", MaxTagLength), nil},
+		{strings.Repeat("a", MaxTagLength+1), "", ErrInvalidTag},
+	} {
+		got, err := NormalizeTag(tc.in)
+		if !errors.Is(err, tc.err) || got != tc.want {
+			t.Fatalf("NormalizeTag(%q): expected %q, %v, got %q, %v", tc.in, tc.want, tc.err, got, err)
+		}
+	}
+}
+
+func TestNormalizeTags_DedupesAndSorts(t *testing.T) {
+	got, err := NormalizeTags([]string{"sql", "Go", "go ", "db"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !slices.Equal(got, []string{"db", "go", "sql"}) {
+		t.Fatalf("expected [db go sql], got %v", got)
+	}
+
+	if got, err := NormalizeTags([]string{}); err != nil || got != nil {
+		t.Fatalf("expected nil for no tags, got %v, %v", got, err)
+	}
+}
+
+func TestNormalizeTags_TooMany(t *testing.T) {
+	tags := make([]string, 0, MaxTagsPerArticle+1)
+	for i := range MaxTagsPerArticle + 1 {
+		tags = append(tags, fmt.Sprintf("tag%d", i))
+	}
+
+	if _, err := NormalizeTags(tags); !errors.Is(err, ErrTooManyTags) {
+		t.Fatalf("expected ErrTooManyTags, got %v", err)
+	}
+	// Duplicates do not count towards the limit.
+	if _, err := NormalizeTags(append(tags[:MaxTagsPerArticle], tags[0])); err != nil {
+		t.Fatalf("expected duplicates to be ignored, got %v", err)
+	}
+}
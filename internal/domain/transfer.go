@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// ImportRecord is one record read from an import file. Err is set when the
+// record could not be parsed; the rest of the file is still imported.
+type ImportRecord struct {
+	// Line is where the record starts in the file, counting from 1.
+	Line  int
+	Input ArticleInput
+	// Slug and CreatedAt carry an exported article's slug and creation time
+	// over; either is assigned anew when empty. A slug already taken gets a
+	// suffix as in UniqueSlug.
+	Slug      string
+	CreatedAt time.Time
+	Err       error
+}
+
+// ImportLineError reports a record that was not imported.
+type ImportLineError struct {
+	Line int
+	Err  error
+}
+
+type ImportResult struct {
+	Imported int
+	Failed   int
+	// Errors holds the first failures in file order; Failed counts all of
+	// them.
+	Errors []ImportLineError
+}
@@ -0,0 +1,45 @@
+// Package idempotency defines the records and storage behind the
+// Idempotency-Key support of the HTTP API: the first response to a key is
+// kept for a while and replayed to retries of the same request.
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Record is the stored outcome of the first request made with a key.
+// StatusCode stays 0 while that request is still being served.
+type Record struct {
+	Key string
+	// Fingerprint identifies the request (method, path and body) so a key
+	// reused for a different request can be refused.
+	Fingerprint string
+	StatusCode  int
+	Header      http.Header
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// Completed reports whether the response has been stored.
+func (r Record) Completed() bool {
+	return r.StatusCode != 0
+}
+
+// Store persists records. Implementations must make Reserve atomic: when
+// several requests race on one key, exactly one of them may win.
+type Store interface {
+	// Reserve claims record.Key for a request that is about to be served.
+	// If an unexpired record already holds the key it is returned with
+	// reserved == false; expired records are replaced.
+	Reserve(ctx context.Context, record Record, now time.Time) (existing Record, reserved bool, err error)
+	// Complete stores the response for a key claimed with Reserve.
+	Complete(ctx context.Context, key string, statusCode int, header http.Header, body []byte) error
+	// Release drops a reservation, letting the next request with the key
+	// run again. Used when serving the request failed on the server side.
+	Release(ctx context.Context, key string) error
+	// DeleteExpired removes records whose ExpiresAt is not after now.
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
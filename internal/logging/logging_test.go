@@ -0,0 +1,70 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func decodeLine(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("decode log line %q: %v", buf.String(), err)
+	}
+	return line
+}
+
+func TestNew_AddsRequestIDFromContext(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, slog.LevelInfo).With("component", "test")
+
+	ctx := WithRequestID(context.Background(), "req-123")
+	logger.InfoContext(ctx, "hello", "answer", 42)
+
+	line := decodeLine(t, &buf)
+	if line["msg"] != "hello" || line["request_id"] != "req-123" || line["component"] != "test" || line["answer"] != float64(42) {
+		t.Fatalf("unexpected log line: %v", line)
+	}
+	if _, ok := line["trace_id"]; ok {
+		t.Fatalf("did not expect trace_id without a span, got %v", line)
+	}
+}
+
+func TestNew_AddsTraceIDs(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, slog.LevelInfo)
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+	logger.InfoContext(ctx, "traced")
+
+	line := decodeLine(t, &buf)
+	if line["trace_id"] != traceID.String() || line["span_id"] != spanID.String() {
+		t.Fatalf("expected trace and span IDs, got %v", line)
+	}
+}
+
+func TestNew_RespectsLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, slog.LevelWarn)
+
+	logger.Info("dropped")
+	if buf.Len() != 0 {
+		t.Fatalf("expected info to be dropped at warn level, got %q", buf.String())
+	}
+
+	logger.Warn("kept")
+	if decodeLine(t, &buf)["level"] != "WARN" {
+		t.Fatalf("expected warn line, got %q", buf.String())
+	}
+}
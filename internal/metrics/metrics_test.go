@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"database/sql"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	body, err := io.ReadAll(rec.Body)
+	if err != nil {
+		t.Fatalf("read metrics: %v", err)
+	}
+	return string(body)
+}
+
+func TestMiddleware_LabelsByRouteTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := New()
+
+	router := gin.New()
+	router.Use(m.Middleware())
+	router.GET("/article/:id", func(c *gin.Context) {
+		c.String(http.StatusOK, "hello")
+	})
+
+	for _, path := range []string{"/article/1", "/article/2", "/nope"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	body := scrape(t, m)
+	for _, want := range []string{
+		`articles_http_requests_total{method="GET",route="/article/:id",status="200"} 2`,
+		`articles_http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`articles_http_request_duration_seconds_count{method="GET",route="/article/:id"} 2`,
+		`articles_http_response_size_bytes_sum{method="GET",route="/article/:id"} 10`,
+		`articles_http_requests_in_flight 0`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected metrics to contain %q, got:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, `route="/article/1"`) {
+		t.Fatalf("expected raw paths not to be used as labels, got:\n%s", body)
+	}
+}
+
+func TestObserveDomainError(t *testing.T) {
+	m := New()
+	m.ObserveDomainError("article.not_found")
+	m.ObserveDomainError("article.not_found")
+
+	body := scrape(t, m)
+	if !strings.Contains(body, `articles_domain_errors_total{code="article.not_found"} 2`) {
+		t.Fatalf("expected domain error counter, got:\n%s", body)
+	}
+}
+
+func TestRegisterDB(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "metrics.db"))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	m := New()
+	if err := m.RegisterDB("articles", db); err != nil {
+		t.Fatalf("RegisterDB returned error: %v", err)
+	}
+
+	body := scrape(t, m)
+	if !strings.Contains(body, `go_sql_open_connections{db_name="articles"}`) {
+		t.Fatalf("expected pool gauges, got:\n%s", body)
+	}
+}
@@ -0,0 +1,332 @@
+// Package migrate applies versioned SQL migrations from an fs.FS. Progress is
+// recorded in a schema_migrations table compatible with golang-migrate, so
+// databases previously migrated with the migrate CLI keep working.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// lockKey identifies the schema advisory lock in Postgres. It only has to be
+// unique among advisory locks taken by this service.
+const lockKey int64 = 0x61727469636c6573 // "articles"
+
+var ErrDirty = errors.New("schema is dirty: a previous migration failed, fix the schema and run force")
+
+type Dialect int
+
+const (
+	Postgres Dialect = iota
+	SQLite
+)
+
+type Migration struct {
+	Version int64
+	Name    string
+	up      string
+	down    string
+}
+
+type Status struct {
+	Version int64
+	Dirty   bool
+	Applied []Migration
+	Pending []Migration
+}
+
+type Migrator struct {
+	db         *sql.DB
+	dialect    Dialect
+	migrations []Migration
+	afterUp    AfterUp
+}
+
+// AfterUp runs at the end of every Up, on the connection holding the schema
+// lock, with the version Up started from. It is where data fixes that must
+// follow a migration go, so that concurrent migrators run them once.
+type AfterUp func(ctx context.Context, conn *sql.Conn, from int64) error
+
+type Option func(*Migrator)
+
+// WithAfterUp sets the hook Up runs once the pending migrations are applied.
+func WithAfterUp(hook AfterUp) Option {
+	return func(m *Migrator) {
+		m.afterUp = hook
+	}
+}
+
+// New reads every NNNN_name.up.sql and NNNN_name.down.sql pair at the root
+// of source.
+func New(db *sql.DB, dialect Dialect, source fs.FS, opts ...Option) (*Migrator, error) {
+	files, err := fs.Glob(source, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, file := range files {
+		version, name, direction, err := parseFileName(file)
+		if err != nil {
+			return nil, err
+		}
+
+		contents, err := fs.ReadFile(source, file)
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		}
+		if direction == "up" {
+			migration.up = string(contents)
+		} else {
+			migration.down = string(contents)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	m := &Migrator{db: db, dialect: dialect, migrations: migrations}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m, nil
+}
+
+// Up applies every pending migration, then runs the AfterUp hook, and
+// returns how many migrations ran.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	applied := 0
+	err := m.WithLock(ctx, func(conn *sql.Conn) error {
+		current, err := m.checkedVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if migration.Version <= current {
+				continue
+			}
+			if err := m.apply(ctx, conn, migration.up, migration.Version); err != nil {
+				return fmt.Errorf("apply %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			applied++
+		}
+		if m.afterUp != nil {
+			return m.afterUp(ctx, conn, current)
+		}
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down reverts up to steps applied migrations, newest first, and returns how
+// many were reverted.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	reverted := 0
+	err := m.WithLock(ctx, func(conn *sql.Conn) error {
+		current, err := m.checkedVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && reverted < steps; i-- {
+			migration := m.migrations[i]
+			if migration.Version > current {
+				continue
+			}
+			if migration.down == "" {
+				return fmt.Errorf("migration %d_%s has no down file", migration.Version, migration.Name)
+			}
+
+			var previous int64
+			if i > 0 {
+				previous = m.migrations[i-1].Version
+			}
+			if err := m.apply(ctx, conn, migration.down, previous); err != nil {
+				return fmt.Errorf("revert %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			reverted++
+		}
+		return nil
+	})
+
+	return reverted, err
+}
+
+func (m *Migrator) Status(ctx context.Context) (Status, error) {
+	var status Status
+	err := m.WithLock(ctx, func(conn *sql.Conn) error {
+		version, dirty, err := m.version(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		status.Version, status.Dirty = version, dirty
+		for _, migration := range m.migrations {
+			if migration.Version <= version {
+				status.Applied = append(status.Applied, migration)
+			} else {
+				status.Pending = append(status.Pending, migration)
+			}
+		}
+		return nil
+	})
+
+	return status, err
+}
+
+// Force records version as applied and clean without running any SQL. It is
+// the way out of a dirty state once the schema has been repaired by hand.
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	if version < 0 {
+		return fmt.Errorf("version must not be negative, got %d", version)
+	}
+
+	return m.WithLock(ctx, func(conn *sql.Conn) error {
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		if err := setVersion(ctx, tx, version); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+}
+
+// WithLock runs fn on a single connection holding the schema lock, so that
+// concurrent replicas migrating on startup take turns. fn must not use
+// another connection to the database, which SQLite would never hand out.
+func (m *Migrator) WithLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if m.dialect == Postgres {
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+			return fmt.Errorf("acquire migration lock: %w", err)
+		}
+		defer func() {
+			// The lock dies with the session anyway, so a failed unlock only
+			// delays the next migrator until this connection is closed.
+			_, _ = conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockKey)
+		}()
+	}
+
+	if _, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)`); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+func (m *Migrator) checkedVersion(ctx context.Context, conn *sql.Conn) (int64, error) {
+	version, dirty, err := m.version(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+	if dirty {
+		return 0, fmt.Errorf("version %d: %w", version, ErrDirty)
+	}
+	return version, nil
+}
+
+func (m *Migrator) version(ctx context.Context, conn *sql.Conn) (int64, bool, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+	err := conn.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("read schema version: %w", err)
+	}
+	return version, dirty, nil
+}
+
+// apply runs script and records version in one transaction; both supported
+// databases have transactional DDL, so a failure leaves no trace.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, script string, version int64) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if err := setVersion(ctx, tx, version); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func setVersion(ctx context.Context, tx *sql.Tx, version int64) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations"); err != nil {
+		return fmt.Errorf("clear schema version: %w", err)
+	}
+	if version == 0 {
+		return nil
+	}
+	// version is an int64, so formatting it into the statement is safe and
+	// sidesteps the placeholder differences between drivers.
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("INSERT INTO schema_migrations (version, dirty) VALUES (%d, false)", version)); err != nil {
+		return fmt.Errorf("record schema version: %w", err)
+	}
+	return nil
+}
+
+func parseFileName(file string) (int64, string, string, error) {
+	base, direction, ok := cutDirection(file)
+	if !ok {
+		return 0, "", "", fmt.Errorf("migration %s must end in .up.sql or .down.sql", file)
+	}
+
+	rawVersion, name, ok := strings.Cut(base, "_")
+	if !ok {
+		return 0, "", "", fmt.Errorf("migration %s must be named NNNN_name", file)
+	}
+	version, err := strconv.ParseInt(rawVersion, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, "", "", fmt.Errorf("migration %s has an invalid version", file)
+	}
+
+	return version, name, direction, nil
+}
+
+func cutDirection(file string) (string, string, bool) {
+	if base, ok := strings.CutSuffix(file, ".up.sql"); ok {
+		return base, "up", true
+	}
+	if base, ok := strings.CutSuffix(file, ".down.sql"); ok {
+		return base, "down", true
+	}
+	return "", "", false
+}
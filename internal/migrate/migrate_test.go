@@ -0,0 +1,176 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+var testMigrations = fstest.MapFS{
+	"0001_create_things.up.sql":   {Data: []byte("CREATE TABLE things (id INTEGER PRIMARY KEY);")},
+	"0001_create_things.down.sql": {Data: []byte("DROP TABLE things;")},
+	"0002_add_name.up.sql":        {Data: []byte("ALTER TABLE things ADD COLUMN name TEXT;\nCREATE INDEX things_name_idx ON things (name);")},
+	"0002_add_name.down.sql":      {Data: []byte("DROP INDEX things_name_idx;\nALTER TABLE things DROP COLUMN name;")},
+}
+
+func setupMigrator(t *testing.T, source fstest.MapFS) (*Migrator, *sql.DB) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "migrate.db"))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	migrator, err := New(db, SQLite, source)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	return migrator, db
+}
+
+func TestMigrator_UpDownStatus(t *testing.T) {
+	migrator, db := setupMigrator(t, testMigrations)
+	ctx := context.Background()
+
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up returned error: %v", err)
+	}
+	if applied != 2 {
+		t.Fatalf("expected 2 applied migrations, got %d", applied)
+	}
+	if _, err := db.Exec("INSERT INTO things (name) VALUES ('a')"); err != nil {
+		t.Fatalf("expected migrated schema, got %v", err)
+	}
+
+	applied, err = migrator.Up(ctx)
+	if err != nil || applied != 0 {
+		t.Fatalf("expected second Up to be a no-op, got %d, %v", applied, err)
+	}
+
+	reverted, err := migrator.Down(ctx, 1)
+	if err != nil || reverted != 1 {
+		t.Fatalf("expected 1 reverted migration, got %d, %v", reverted, err)
+	}
+
+	status, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status returned error: %v", err)
+	}
+	if status.Version != 1 || status.Dirty || len(status.Applied) != 1 || len(status.Pending) != 1 || status.Pending[0].Version != 2 {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+
+	reverted, err = migrator.Down(ctx, 5)
+	if err != nil || reverted != 1 {
+		t.Fatalf("expected 1 reverted migration, got %d, %v", reverted, err)
+	}
+	status, err = migrator.Status(ctx)
+	if err != nil || status.Version != 0 || len(status.Pending) != 2 {
+		t.Fatalf("expected an empty schema, got %+v, %v", status, err)
+	}
+}
+
+func TestMigrator_FailedMigrationRollsBack(t *testing.T) {
+	source := fstest.MapFS{
+		"0001_create_things.up.sql": testMigrations["0001_create_things.up.sql"],
+		"0002_broken.up.sql":        {Data: []byte("ALTER TABLE things ADD COLUMN name TEXT;\nTHIS IS NOT SQL;")},
+	}
+	migrator, db := setupMigrator(t, source)
+	ctx := context.Background()
+
+	if _, err := migrator.Up(ctx); err == nil {
+		t.Fatal("expected Up to fail")
+	}
+
+	status, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status returned error: %v", err)
+	}
+	if status.Version != 1 || status.Dirty {
+		t.Fatalf("expected version 1 and a clean schema, got %+v", status)
+	}
+	if _, err := db.Exec("INSERT INTO things (name) VALUES ('a')"); err == nil {
+		t.Fatal("expected the partial migration to be rolled back")
+	}
+}
+
+func TestMigrator_DirtyRequiresForce(t *testing.T) {
+	migrator, db := setupMigrator(t, testMigrations)
+	ctx := context.Background()
+
+	// Simulate a failure left behind by golang-migrate.
+	if _, err := db.Exec("CREATE TABLE schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL); INSERT INTO schema_migrations VALUES (1, true); CREATE TABLE things (id INTEGER PRIMARY KEY);"); err != nil {
+		t.Fatalf("seed dirty state: %v", err)
+	}
+
+	if _, err := migrator.Up(ctx); !errors.Is(err, ErrDirty) {
+		t.Fatalf("expected ErrDirty, got %v", err)
+	}
+
+	if err := migrator.Force(ctx, 1); err != nil {
+		t.Fatalf("Force returned error: %v", err)
+	}
+	applied, err := migrator.Up(ctx)
+	if err != nil || applied != 1 {
+		t.Fatalf("expected 1 applied migration after Force, got %d, %v", applied, err)
+	}
+}
+
+func TestMigrator_AfterUpRunsUnderTheLock(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "migrate.db"))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	// With a single connection, a hook not running on the locked one would
+	// block forever.
+	db.SetMaxOpenConns(1)
+
+	var froms []int64
+	migrator, err := New(db, SQLite, testMigrations, WithAfterUp(func(ctx context.Context, conn *sql.Conn, from int64) error {
+		froms = append(froms, from)
+		_, err := conn.ExecContext(ctx, "INSERT INTO things (name) VALUES ('backfilled')")
+		return err
+	}))
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for range 2 {
+		if _, err := migrator.Up(ctx); err != nil {
+			t.Fatalf("Up returned error: %v", err)
+		}
+	}
+	if len(froms) != 2 || froms[0] != 0 || froms[1] != 2 {
+		t.Fatalf("expected the hook to see versions 0 then 2, got %v", froms)
+	}
+
+	hookErr := errors.New("backfill failed")
+	failing, err := New(db, SQLite, testMigrations, WithAfterUp(func(context.Context, *sql.Conn, int64) error {
+		return hookErr
+	}))
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	if _, err := failing.Up(ctx); !errors.Is(err, hookErr) {
+		t.Fatalf("expected the hook error, got %v", err)
+	}
+}
+
+func TestNew_InvalidFileName(t *testing.T) {
+	_, err := New(nil, SQLite, fstest.MapFS{"create_things.up.sql": {Data: []byte("SELECT 1")}})
+	if err == nil {
+		t.Fatal("expected an error for a migration without a version")
+	}
+}
@@ -0,0 +1,93 @@
+// Package ratelimit defines the token buckets behind the per-client request
+// budgets of the HTTP API and the storage they are kept in. A bucket holds
+// up to Limit.Burst tokens and refills at Burst tokens per Limit.Period;
+// every request takes one token, and a request finding the bucket empty is
+// refused.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Limit is a budget: Burst requests at once, refilled evenly over Period.
+type Limit struct {
+	Burst  int
+	Period time.Duration
+}
+
+// Enabled reports whether l limits anything; a zero Burst turns a budget
+// off.
+func (l Limit) Enabled() bool {
+	return l.Burst > 0 && l.Period > 0
+}
+
+// rate is the number of tokens added per second.
+func (l Limit) rate() float64 {
+	return float64(l.Burst) / l.Period.Seconds()
+}
+
+// Result is the outcome of taking a token.
+type Result struct {
+	Allowed bool
+	Limit   int
+	// Remaining is the number of whole tokens left after this request.
+	Remaining int
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is how long until the next token, when Allowed is false.
+	RetryAfter time.Duration
+}
+
+// Bucket is the state of one client's budget. Stores persist it as it is.
+type Bucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// Take refills b for the time elapsed since it was last updated and takes a
+// token from it if there is one. A zero Bucket starts full.
+func (b Bucket) Take(limit Limit, now time.Time) (Bucket, Result) {
+	burst := float64(limit.Burst)
+	switch {
+	case b.UpdatedAt.IsZero():
+		b.Tokens = burst
+	case now.After(b.UpdatedAt):
+		b.Tokens = math.Min(burst, b.Tokens+now.Sub(b.UpdatedAt).Seconds()*limit.rate())
+	}
+	if now.After(b.UpdatedAt) {
+		b.UpdatedAt = now
+	}
+
+	result := Result{Limit: limit.Burst}
+	if b.Tokens >= 1 {
+		b.Tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = limit.duration(1 - b.Tokens)
+	}
+	result.Remaining = int(b.Tokens)
+	result.Reset = limit.duration(burst - b.Tokens)
+	return b, result
+}
+
+// Full reports whether b has refilled completely by now, at which point it
+// can be forgotten: a zero Bucket starts full too.
+func (b Bucket) Full(limit Limit, now time.Time) bool {
+	return now.Sub(b.UpdatedAt) >= limit.duration(float64(limit.Burst)-b.Tokens)
+}
+
+// duration is how long limit takes to add tokens.
+func (l Limit) duration(tokens float64) time.Duration {
+	return time.Duration(math.Ceil(tokens / l.rate() * float64(time.Second)))
+}
+
+// Store keeps buckets by key. Take must be atomic per key, so that
+// concurrent requests, possibly on other replicas sharing the store, cannot
+// spend the same token twice.
+type Store interface {
+	// Take refills the bucket at key and takes a token from it, as
+	// Bucket.Take does, and stores the result.
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
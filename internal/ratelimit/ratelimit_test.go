@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBucket_Take(t *testing.T) {
+	limit := Limit{Burst: 2, Period: 2 * time.Second}
+	now := time.Unix(1_700_000_000, 0)
+
+	var (
+		bucket Bucket
+		result Result
+	)
+	for i, wantRemaining := range []int{1, 0} {
+		bucket, result = bucket.Take(limit, now)
+		if !result.Allowed || result.Remaining != wantRemaining {
+			t.Fatalf("take %d: expected allowed with %d remaining, got %+v", i, wantRemaining, result)
+		}
+	}
+	if result.Reset != 2*time.Second {
+		t.Fatalf("expected an empty bucket to be full in 2s, got %v", result.Reset)
+	}
+
+	bucket, result = bucket.Take(limit, now)
+	if result.Allowed || result.RetryAfter != time.Second {
+		t.Fatalf("expected a refusal with retry after 1s, got %+v", result)
+	}
+
+	bucket, result = bucket.Take(limit, now.Add(time.Second))
+	if !result.Allowed || result.Remaining != 0 {
+		t.Fatalf("expected one token to be refilled after 1s, got %+v", result)
+	}
+
+	if bucket.Full(limit, now.Add(2*time.Second)) {
+		t.Fatal("expected the bucket not to be full 1s after it was emptied")
+	}
+	if !bucket.Full(limit, now.Add(3*time.Second)) {
+		t.Fatal("expected the bucket to be full 2s after it was emptied")
+	}
+
+	_, result = bucket.Take(limit, now.Add(time.Hour))
+	if !result.Allowed || result.Remaining != 1 {
+		t.Fatalf("expected refills to stop at the burst, got %+v", result)
+	}
+}
+
+func TestLimit_Enabled(t *testing.T) {
+	if (Limit{Burst: 0, Period: time.Minute}).Enabled() {
+		t.Fatal("expected a zero burst to turn the limit off")
+	}
+	if !(Limit{Burst: 10, Period: time.Minute}).Enabled() {
+		t.Fatal("expected a burst of 10 per minute to be enabled")
+	}
+}
@@ -0,0 +1,156 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	httpadapter "articles/internal/adapter/http"
+	"articles/internal/auth"
+	"articles/internal/usecase"
+)
+
+const apiKeyHeader = "X-API-Key"
+
+// TokenVerifier resolves a bearer token to the principal it was issued to.
+// Errors wrapping auth.ErrInvalidToken mean the token was refused.
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (auth.Principal, error)
+}
+
+// Authenticate resolves the X-API-Key header or an Authorization: Bearer
+// token to a principal and stores it in the request context, where the
+// usecases see it as the actor and, for tokens, as the author of new
+// articles. Either of keys and tokens may be nil to turn that credential
+// off. Requests without credentials go on anonymously; a key that is unknown
+// or revoked, or a token that fails verification, is refused with 401 rather
+// than treated as anonymous, so a client is told its credential stopped
+// working.
+func Authenticate(keys auth.KeyStore, tokens TokenVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		secret := c.GetHeader(apiKeyHeader)
+		token, hasToken := bearerToken(c.GetHeader("Authorization"))
+		if keys == nil {
+			secret = ""
+		}
+		if tokens == nil {
+			hasToken = false
+		}
+
+		var (
+			principal auth.Principal
+			ok        bool
+		)
+		switch {
+		case secret != "" && hasToken:
+			unauthenticated(c, "send either an API key or a bearer token, not both")
+			return
+		case secret != "":
+			principal, ok = authenticateKey(c, keys, secret)
+		case hasToken:
+			principal, ok = authenticateToken(c, tokens, token)
+		default:
+			c.Next()
+			return
+		}
+		if !ok {
+			return
+		}
+
+		ctx := auth.WithPrincipal(c.Request.Context(), principal)
+		ctx = usecase.WithActor(ctx, principal.Subject)
+		if principal.AuthorID != "" {
+			ctx = usecase.WithAuthor(ctx, principal.AuthorID)
+		}
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+func authenticateKey(c *gin.Context, keys auth.KeyStore, secret string) (auth.Principal, bool) {
+	ctx := c.Request.Context()
+	key, err := keys.GetKeyByHash(ctx, auth.HashKey(secret))
+	switch {
+	case errors.Is(err, auth.ErrKeyNotFound), err == nil && key.Revoked():
+		unauthenticated(c, "API key is unknown or revoked")
+		return auth.Principal{}, false
+	case err != nil:
+		slog.ErrorContext(ctx, "look up api key failed", "error", err)
+		internalError(c)
+		return auth.Principal{}, false
+	}
+	return key.Principal(), true
+}
+
+func authenticateToken(c *gin.Context, tokens TokenVerifier, token string) (auth.Principal, bool) {
+	ctx := c.Request.Context()
+	principal, err := tokens.Verify(ctx, token)
+	switch {
+	case errors.Is(err, auth.ErrInvalidToken):
+		slog.InfoContext(ctx, "bearer token refused", "error", err)
+		c.Header("WWW-Authenticate", `Bearer realm="articles", error="invalid_token"`)
+		httpadapter.WriteProblem(c, httpadapter.NewProblem(http.StatusUnauthorized, httpadapter.CodeUnauthenticated,
+			"Authentication required", "bearer token is invalid or expired"))
+		return auth.Principal{}, false
+	case err != nil:
+		slog.ErrorContext(ctx, "verify bearer token failed", "error", err)
+		internalError(c)
+		return auth.Principal{}, false
+	}
+	return principal, true
+}
+
+// bearerToken reads the token of an Authorization header using the Bearer
+// scheme, whose name is case-insensitive.
+func bearerToken(header string) (string, bool) {
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// trustCallers stands in for Authenticate on servers running with
+// authentication off: no caller can be told apart, so every one may read
+// articles of any status.
+func trustCallers() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(usecase.WithTrusted(c.Request.Context()))
+		c.Next()
+	}
+}
+
+// RequireScope lets requests whose principal has scope through. Anonymous
+// requests get 401, unless allowAnonymous is set: then they are served with
+// what the usecases show anonymous callers.
+func RequireScope(scope auth.Scope, allowAnonymous bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.PrincipalFrom(c.Request.Context())
+		switch {
+		case !ok && allowAnonymous:
+			c.Next()
+		case !ok:
+			unauthenticated(c, "credentials granting the "+string(scope)+" scope are required")
+		case !principal.HasScope(scope):
+			httpadapter.WriteProblem(c, httpadapter.NewProblem(http.StatusForbidden, httpadapter.CodeInsufficientScope,
+				"Insufficient scope", "the credentials lack the "+string(scope)+" scope"))
+		default:
+			c.Next()
+		}
+	}
+}
+
+func unauthenticated(c *gin.Context, detail string) {
+	c.Header("WWW-Authenticate", `ApiKey realm="articles", header="`+apiKeyHeader+`"`)
+	c.Writer.Header().Add("WWW-Authenticate", `Bearer realm="articles"`)
+	httpadapter.WriteProblem(c, httpadapter.NewProblem(http.StatusUnauthorized, httpadapter.CodeUnauthenticated, "Authentication required", detail))
+}
+
+func internalError(c *gin.Context) {
+	httpadapter.WriteProblem(c, httpadapter.NewProblem(http.StatusInternalServerError, httpadapter.CodeInternal, "Internal server error", "internal server error"))
+}
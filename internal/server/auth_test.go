@@ -0,0 +1,239 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	httpadapter "articles/internal/adapter/http"
+	"articles/internal/adapter/storage/memory"
+	"articles/internal/auth"
+	"articles/internal/auth/authtest"
+	"articles/internal/usecase"
+)
+
+// newAuthRouter serves the API over in-memory storage with API keys
+// required, and mints a key for every scope set in scopes.
+func newAuthRouter(t *testing.T, keys *memory.APIKeyStore, scopes ...[]auth.Scope) (*gin.Engine, []string) {
+	t.Helper()
+
+	secrets := make([]string, 0, len(scopes))
+	for i, keyScopes := range scopes {
+		secret, key, err := auth.NewAPIKey("key-"+strconv.Itoa(i), keyScopes, time.Now())
+		if err != nil {
+			t.Fatalf("NewAPIKey returned error: %v", err)
+		}
+		if _, err := keys.CreateKey(context.Background(), key); err != nil {
+			t.Fatalf("CreateKey returned error: %v", err)
+		}
+		secrets = append(secrets, secret)
+	}
+
+	service := usecase.NewArticleService(memory.NewArticleRepository())
+	return NewRouter(httpadapter.NewArticleHandler(service), nil, WithAPIKeys(keys)), secrets
+}
+
+func doWithKey(router http.Handler, method, path, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(apiKeyHeader, key)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAuth_AnonymousCanReadButNotWrite(t *testing.T) {
+	router, _ := newAuthRouter(t, memory.NewAPIKeyStore())
+
+	if rec := doWithKey(router, http.MethodGet, "/article", "", ""); rec.Code != http.StatusOK {
+		t.Fatalf("expected anonymous list to get %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	rec := doWithKey(router, http.MethodPost, "/article", "", `{"title":"Hello"}`)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+	if rec.Header().Get("WWW-Authenticate") == "" {
+		t.Fatal("expected a WWW-Authenticate challenge")
+	}
+}
+
+func TestAuth_ScopesPerRoute(t *testing.T) {
+	router, secrets := newAuthRouter(t, memory.NewAPIKeyStore(),
+		[]auth.Scope{auth.ScopeArticlesRead},
+		[]auth.Scope{auth.ScopeArticlesRead, auth.ScopeArticlesWrite},
+	)
+	reader, writer := secrets[0], secrets[1]
+
+	rec := doWithKey(router, http.MethodPost, "/article", reader, `{"title":"Hello"}`)
+	if rec.Code != http.StatusForbidden || !strings.Contains(rec.Body.String(), httpadapter.CodeInsufficientScope) {
+		t.Fatalf("expected 403 %s, got %d: %s", httpadapter.CodeInsufficientScope, rec.Code, rec.Body.String())
+	}
+
+	rec = doWithKey(router, http.MethodPost, "/article", writer, `{"title":"Hello"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+	var created struct {
+		ID int64 `json:"id"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&created); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	path := "/article/" + strconv.FormatInt(created.ID, 10)
+
+	// The new article is a draft: keys with articles:write act as editors
+	// and see it, read-only keys and anonymous callers do not.
+	if rec := doWithKey(router, http.MethodGet, path, writer, ""); rec.Code != http.StatusOK {
+		t.Fatalf("expected the writer to get %d, got %d", http.StatusOK, rec.Code)
+	}
+	if rec := doWithKey(router, http.MethodGet, path, reader, ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected the reader to get %d, got %d", http.StatusNotFound, rec.Code)
+	}
+	if rec := doWithKey(router, http.MethodGet, path, "", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected anonymous callers to get %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestAuth_DisabledTrustsEveryCaller(t *testing.T) {
+	service := usecase.NewArticleService(memory.NewArticleRepository())
+	router := NewRouter(httpadapter.NewArticleHandler(service), nil)
+
+	rec := doWithKey(router, http.MethodPost, "/article", "", `{"title":"Hello","tags":["go"]}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+	var created struct {
+		ID int64 `json:"id"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&created); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	// Without authentication the draft reads back as it would for a key.
+	if rec := doWithKey(router, http.MethodGet, "/article/"+strconv.FormatInt(created.ID, 10), "", ""); rec.Code != http.StatusOK {
+		t.Fatalf("expected the draft to be readable, got %d", rec.Code)
+	}
+	if rec := doWithKey(router, http.MethodGet, "/tags", "", ""); !strings.Contains(rec.Body.String(), `"go"`) {
+		t.Fatalf("expected the draft's tags to be listed, got %s", rec.Body.String())
+	}
+}
+
+func TestAuth_RejectsUnknownAndRevokedKeys(t *testing.T) {
+	keys := memory.NewAPIKeyStore()
+	router, secrets := newAuthRouter(t, keys, []auth.Scope{auth.ScopeArticlesRead})
+	if err := keys.RevokeKey(context.Background(), 1, time.Now()); err != nil {
+		t.Fatalf("RevokeKey returned error: %v", err)
+	}
+
+	for _, key := range []string{secrets[0], "ak_unknown"} {
+		rec := doWithKey(router, http.MethodGet, "/article", key, "")
+		if rec.Code != http.StatusUnauthorized || !strings.Contains(rec.Body.String(), httpadapter.CodeUnauthenticated) {
+			t.Fatalf("expected 401 %s, got %d: %s", httpadapter.CodeUnauthenticated, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+// newTokenRouter serves the API over in-memory storage, authenticating
+// bearer tokens signed by signer.
+func newTokenRouter(t *testing.T, signer *authtest.Signer) *gin.Engine {
+	t.Helper()
+
+	verifier := auth.NewJWTVerifier(auth.JWKSFromFile(authtest.WriteJWKS(t, signer), time.Hour),
+		auth.WithIssuer(authtest.Issuer), auth.WithAudience(authtest.Audience))
+	service := usecase.NewArticleService(memory.NewArticleRepository())
+	return NewRouter(httpadapter.NewArticleHandler(service), nil, WithBearerTokens(verifier))
+}
+
+func doWithToken(router http.Handler, method, path, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAuth_BearerTokenSetsAuthor(t *testing.T) {
+	signer := authtest.NewSigner(t, "ec", "ES256")
+	router := newTokenRouter(t, signer)
+
+	token := signer.Sign(t, authtest.Claims("user-1", "author"))
+	rec := doWithToken(router, http.MethodPost, "/article", token, `{"title":"Hello","author_id":"someone-else"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+	var created struct {
+		AuthorID string `json:"author_id"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&created); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if created.AuthorID != "user-1" {
+		t.Fatalf("expected author %q, got %q", "user-1", created.AuthorID)
+	}
+
+	reader := signer.Sign(t, authtest.Claims("user-2", "reader"))
+	rec = doWithToken(router, http.MethodPost, "/article", reader, `{"title":"Hello"}`)
+	if rec.Code != http.StatusForbidden || !strings.Contains(rec.Body.String(), httpadapter.CodeInsufficientScope) {
+		t.Fatalf("expected 403 %s, got %d: %s", httpadapter.CodeInsufficientScope, rec.Code, rec.Body.String())
+	}
+}
+
+func TestAuth_RejectsInvalidBearerTokens(t *testing.T) {
+	signer := authtest.NewSigner(t, "ec", "ES256")
+	router := newTokenRouter(t, signer)
+
+	claims := authtest.Claims("user-1", "reader")
+	claims["exp"] = time.Now().Add(-time.Hour).Unix()
+	for _, token := range []string{"garbage", signer.Sign(t, claims)} {
+		rec := doWithToken(router, http.MethodGet, "/article", token, "")
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusUnauthorized, rec.Code, rec.Body.String())
+		}
+		if got := rec.Header().Get("WWW-Authenticate"); !strings.Contains(got, `error="invalid_token"`) {
+			t.Fatalf("expected an invalid_token challenge, got %q", got)
+		}
+	}
+}
+
+func TestAuth_AuthorsEditOnlyTheirOwnArticles(t *testing.T) {
+	signer := authtest.NewSigner(t, "ec", "ES256")
+	router := newTokenRouter(t, signer)
+	alice := signer.Sign(t, authtest.Claims("alice", "author"))
+	bob := signer.Sign(t, authtest.Claims("bob", "author"))
+	editor := signer.Sign(t, authtest.Claims("carol", "editor"))
+
+	rec := doWithToken(router, http.MethodPost, "/article", alice, `{"title":"Hello"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+
+	patch := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPatch, "/article/1", strings.NewReader(`{"summary":"Changed"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("If-Match", "*")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := patch(bob); rec.Code != http.StatusForbidden || !strings.Contains(rec.Body.String(), "auth.forbidden") {
+		t.Fatalf("expected 403 auth.forbidden for another author, got %d: %s", rec.Code, rec.Body.String())
+	}
+	for _, token := range []string{alice, editor} {
+		if rec := patch(token); rec.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	httpadapter "articles/internal/adapter/http"
+	"articles/internal/idempotency"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	idempotencyInProgressWait = time.Second
+)
+
+// replayedHeaders are the response headers kept with a stored response;
+// everything else (Date, request IDs, rate limits) describes the retry.
+var replayedHeaders = []string{"Content-Type", "ETag", "Location"}
+
+// Idempotency makes the routes it wraps safe to retry. The first response to
+// an Idempotency-Key is stored for ttl and replayed to later requests from
+// the same client with the same key; reusing a key for a different request
+// is refused with 422. Clients never see each other's keys.
+// Requests without the header are served as usual. Server errors are not
+// stored, so a retry after a 5xx runs the handler again.
+func Idempotency(store idempotency.Store, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			httpadapter.WriteProblem(c, httpadapter.NewProblem(http.StatusBadRequest, httpadapter.CodeIdempotencyInvalidKey,
+				"Invalid idempotency key", "Idempotency-Key must be at most "+strconv.Itoa(maxIdempotencyKeyLength)+" characters"))
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				httpadapter.WriteProblem(c, httpadapter.NewProblem(http.StatusRequestEntityTooLarge, httpadapter.CodeBodyTooLarge, "Request body too large", "request body too large"))
+				return
+			}
+			httpadapter.WriteProblem(c, httpadapter.NewProblem(http.StatusBadRequest, httpadapter.CodeInvalidBody, "Invalid request body", "invalid request body"))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Header values cannot hold a line break, so the key ends at the
+		// first one and the client that sent it follows.
+		key += "\n" + client(c)
+		now := time.Now()
+		record := idempotency.Record{
+			Key:         key,
+			Fingerprint: fingerprint(c.Request, body),
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(ttl),
+		}
+
+		ctx := c.Request.Context()
+		existing, reserved, err := store.Reserve(ctx, record, now)
+		if err != nil {
+			slog.ErrorContext(ctx, "reserve idempotency key failed", "error", err)
+			httpadapter.WriteProblem(c, httpadapter.NewProblem(http.StatusInternalServerError, httpadapter.CodeInternal, "Internal server error", "internal server error"))
+			return
+		}
+		if !reserved {
+			switch {
+			case existing.Fingerprint != record.Fingerprint:
+				httpadapter.WriteProblem(c, httpadapter.NewProblem(http.StatusUnprocessableEntity, httpadapter.CodeIdempotencyKeyReused,
+					"Idempotency key reused", "Idempotency-Key was already used for a different request"))
+			case !existing.Completed():
+				c.Header("Retry-After", strconv.Itoa(int(idempotencyInProgressWait.Seconds())))
+				httpadapter.WriteProblem(c, httpadapter.NewProblem(http.StatusConflict, httpadapter.CodeIdempotencyInProgress,
+					"Request in progress", "a request with this Idempotency-Key is still being processed"))
+			default:
+				replay(c, existing)
+			}
+			return
+		}
+
+		// The outcome is recorded even if the client has gone away meanwhile.
+		storeCtx := context.WithoutCancel(ctx)
+		writer := &capturingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		served := false
+		defer func() {
+			if served {
+				return
+			}
+			// The handler panicked: free the key so the retry is not stuck
+			// behind a request that will never complete.
+			if err := store.Release(storeCtx, key); err != nil {
+				slog.ErrorContext(ctx, "release idempotency key failed", "error", err)
+			}
+		}()
+
+		c.Next()
+		served = true
+		c.Writer = writer.ResponseWriter
+
+		status := writer.Status()
+		if status >= http.StatusInternalServerError {
+			if err := store.Release(storeCtx, key); err != nil {
+				slog.ErrorContext(ctx, "release idempotency key failed", "error", err)
+			}
+			return
+		}
+
+		header := make(http.Header, len(replayedHeaders))
+		for _, name := range replayedHeaders {
+			if value := writer.Header().Get(name); value != "" {
+				header.Set(name, value)
+			}
+		}
+		if err := store.Complete(storeCtx, key, status, header, writer.body.Bytes()); err != nil {
+			slog.ErrorContext(ctx, "store idempotent response failed", "error", err)
+		}
+	}
+}
+
+// fingerprint identifies a request by method, path and body.
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method)
+	h.Write([]byte{0})
+	io.WriteString(h, r.URL.Path)
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func replay(c *gin.Context, record idempotency.Record) {
+	for name, values := range record.Header {
+		for _, value := range values {
+			c.Writer.Header().Add(name, value)
+		}
+	}
+	c.Header(idempotentReplayedHeader, "true")
+	c.Status(record.StatusCode)
+	_, _ = c.Writer.Write(record.Body)
+	c.Abort()
+}
+
+// capturingWriter keeps a copy of the response body while writing it
+// through.
+type capturingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *capturingWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *capturingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
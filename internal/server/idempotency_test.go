@@ -0,0 +1,212 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	httpadapter "articles/internal/adapter/http"
+	"articles/internal/adapter/storage/memory"
+	"articles/internal/domain"
+	"articles/internal/idempotency"
+	"articles/internal/usecase"
+)
+
+// newIdempotentRouter serves POST /things with handler behind the
+// Idempotency middleware.
+func newIdempotentRouter(store idempotency.Store, handler gin.HandlerFunc) *gin.Engine {
+	router := gin.New()
+	router.POST("/things", Idempotency(store, time.Hour), handler)
+	return router
+}
+
+func postThing(router http.Handler, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/things", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(idempotencyKeyHeader, key)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIdempotency_ReplaysFirstResponse(t *testing.T) {
+	calls := 0
+	router := newIdempotentRouter(memory.NewIdempotencyStore(), func(c *gin.Context) {
+		calls++
+		c.Header("Location", "/things/1")
+		c.JSON(http.StatusCreated, gin.H{"call": calls})
+	})
+
+	first := postThing(router, "key-1", `{"name":"a"}`)
+	second := postThing(router, "key-1", `{"name":"a"}`)
+
+	if calls != 1 {
+		t.Fatalf("expected handler to run once, ran %d times", calls)
+	}
+	if second.Code != http.StatusCreated {
+		t.Fatalf("expected replayed status %d, got %d", http.StatusCreated, second.Code)
+	}
+	if second.Body.String() != first.Body.String() {
+		t.Fatalf("expected replayed body %q, got %q", first.Body.String(), second.Body.String())
+	}
+	if got := second.Header().Get("Location"); got != "/things/1" {
+		t.Fatalf("expected replayed Location /things/1, got %q", got)
+	}
+	if got := second.Header().Get("Content-Type"); got != first.Header().Get("Content-Type") {
+		t.Fatalf("expected replayed Content-Type %q, got %q", first.Header().Get("Content-Type"), got)
+	}
+	if first.Header().Get(idempotentReplayedHeader) != "" || second.Header().Get(idempotentReplayedHeader) != "true" {
+		t.Fatalf("expected only the replay to be marked, got %q and %q",
+			first.Header().Get(idempotentReplayedHeader), second.Header().Get(idempotentReplayedHeader))
+	}
+}
+
+func TestIdempotency_KeysAreScopedToTheClient(t *testing.T) {
+	calls := 0
+	router := newIdempotentRouter(memory.NewIdempotencyStore(), func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusCreated, gin.H{"call": calls})
+	})
+
+	for _, addr := range []string{"192.0.2.1:1234", "192.0.2.2:1234"} {
+		req := httptest.NewRequest(http.MethodPost, "/things", strings.NewReader(`{"name":"a"}`))
+		req.Header.Set(idempotencyKeyHeader, "key-1")
+		req.RemoteAddr = addr
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusCreated || rec.Header().Get(idempotentReplayedHeader) != "" {
+			t.Fatalf("expected %s to get its own response, got %d: %s", addr, rec.Code, rec.Body.String())
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("expected handler to run for each client, ran %d times", calls)
+	}
+}
+
+func TestIdempotency_KeyReusedWithDifferentBody(t *testing.T) {
+	router := newIdempotentRouter(memory.NewIdempotencyStore(), func(c *gin.Context) {
+		c.JSON(http.StatusCreated, gin.H{})
+	})
+
+	postThing(router, "key-1", `{"name":"a"}`)
+	rec := postThing(router, "key-1", `{"name":"b"}`)
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status %d, got %d", http.StatusUnprocessableEntity, rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), httpadapter.CodeIdempotencyKeyReused) {
+		t.Fatalf("expected code %s, got %s", httpadapter.CodeIdempotencyKeyReused, rec.Body.String())
+	}
+}
+
+func TestIdempotency_InFlightKeyConflicts(t *testing.T) {
+	var (
+		router *gin.Engine
+		retry  *httptest.ResponseRecorder
+	)
+	router = newIdempotentRouter(memory.NewIdempotencyStore(), func(c *gin.Context) {
+		// A retry arriving while the first request is still being served.
+		retry = postThing(router, "key-1", `{}`)
+		c.JSON(http.StatusCreated, gin.H{})
+	})
+
+	postThing(router, "key-1", `{}`)
+
+	if retry.Code != http.StatusConflict {
+		t.Fatalf("expected status %d, got %d", http.StatusConflict, retry.Code)
+	}
+	if retry.Header().Get("Retry-After") == "" {
+		t.Fatal("expected Retry-After header")
+	}
+}
+
+func TestIdempotency_WithoutKeyPassesThrough(t *testing.T) {
+	calls := 0
+	router := newIdempotentRouter(memory.NewIdempotencyStore(), func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusCreated, gin.H{})
+	})
+
+	postThing(router, "", `{}`)
+	rec := postThing(router, "", `{}`)
+
+	if calls != 2 {
+		t.Fatalf("expected handler to run twice, ran %d times", calls)
+	}
+	if rec.Header().Get(idempotentReplayedHeader) != "" {
+		t.Fatalf("did not expect %s header", idempotentReplayedHeader)
+	}
+}
+
+func TestIdempotency_ServerErrorReleasesKey(t *testing.T) {
+	calls := 0
+	router := newIdempotentRouter(memory.NewIdempotencyStore(), func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			c.JSON(http.StatusInternalServerError, gin.H{})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{})
+	})
+
+	postThing(router, "key-1", `{}`)
+	rec := postThing(router, "key-1", `{}`)
+
+	if calls != 2 {
+		t.Fatalf("expected the retry to run the handler again, ran %d times", calls)
+	}
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, rec.Code)
+	}
+}
+
+func TestIdempotency_RejectsLongKey(t *testing.T) {
+	router := newIdempotentRouter(memory.NewIdempotencyStore(), func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+
+	rec := postThing(router, strings.Repeat("k", maxIdempotencyKeyLength+1), `{}`)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestRouter_CreateArticleIsIdempotent(t *testing.T) {
+	repo := memory.NewArticleRepository()
+	router := NewRouter(httpadapter.NewArticleHandler(usecase.NewArticleService(repo)), nil,
+		WithIdempotency(memory.NewIdempotencyStore(), time.Hour))
+
+	body := `{"title":"Hello","author_id":"author-1"}`
+	first := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/article", strings.NewReader(body))
+	req.Header.Set(idempotencyKeyHeader, "create-1")
+	router.ServeHTTP(first, req)
+
+	second := httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/article", strings.NewReader(body))
+	req.Header.Set(idempotencyKeyHeader, "create-1")
+	router.ServeHTTP(second, req)
+
+	if first.Code != http.StatusCreated || second.Code != http.StatusCreated {
+		t.Fatalf("expected both responses to be %d, got %d and %d", http.StatusCreated, first.Code, second.Code)
+	}
+	if second.Header().Get("ETag") != first.Header().Get("ETag") {
+		t.Fatalf("expected replayed ETag %q, got %q", first.Header().Get("ETag"), second.Header().Get("ETag"))
+	}
+
+	articles, err := repo.List(context.Background(), domain.ListArticlesQuery{Limit: 10})
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if len(articles) != 1 {
+		t.Fatalf("expected 1 article, got %d", len(articles))
+	}
+}
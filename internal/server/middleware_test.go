@@ -0,0 +1,150 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	httpadapter "articles/internal/adapter/http"
+	"articles/internal/adapter/storage/memory"
+	"articles/internal/logging"
+	"articles/internal/usecase"
+)
+
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(logging.New(&buf, slog.LevelDebug))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	return &buf
+}
+
+func logLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var lines []map[string]any
+	for _, raw := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var line map[string]any
+		if err := json.Unmarshal([]byte(raw), &line); err != nil {
+			t.Fatalf("decode log line %q: %v", raw, err)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestRequestID_EchoesAndLogsCallerID(t *testing.T) {
+	logs := captureLogs(t)
+	router := NewRouter(httpadapter.NewArticleHandler(usecase.NewArticleService(memory.NewArticleRepository())), nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/article", strings.NewReader(`{"title":"Logged"}`))
+	req.Header.Set("X-Request-ID", "client-supplied-1")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, rec.Code)
+	}
+	if got := rec.Header().Get("X-Request-ID"); got != "client-supplied-1" {
+		t.Fatalf("expected X-Request-ID to be echoed, got %q", got)
+	}
+
+	messages := map[string]bool{}
+	for _, line := range logLines(t, logs) {
+		if line["request_id"] != "client-supplied-1" {
+			t.Fatalf("expected every line to carry the request ID, got %v", line)
+		}
+		messages[line["msg"].(string)] = true
+	}
+	for _, want := range []string{"article created", "request completed"} {
+		if !messages[want] {
+			t.Fatalf("expected a %q line, got %v", want, messages)
+		}
+	}
+}
+
+func TestRequestID_GeneratedWhenMissingOrInvalid(t *testing.T) {
+	captureLogs(t)
+	router := NewRouter(httpadapter.NewArticleHandler(usecase.NewArticleService(nil)), nil)
+
+	for _, header := range []string{"", "has spaces in it", strings.Repeat("x", maxRequestIDLength+1)} {
+		req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+		if header != "" {
+			req.Header.Set("X-Request-ID", header)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		got := rec.Header().Get("X-Request-ID")
+		if len(got) != 32 || got == header {
+			t.Fatalf("expected a generated 32-character request ID for %q, got %q", header, got)
+		}
+	}
+}
+
+func TestRecovery_LogsPanicWithRequestID(t *testing.T) {
+	logs := captureLogs(t)
+	router := NewRouter(httpadapter.NewArticleHandler(usecase.NewArticleService(nil)), nil)
+	router.GET("/boom", func(*gin.Context) { panic("kaboom") })
+
+	req := httptest.NewRequest(http.MethodGet, "/boom", nil)
+	req.Header.Set("X-Request-ID", "panicking")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status %d, got %d", http.StatusInternalServerError, rec.Code)
+	}
+	lines := logLines(t, logs)
+	if lines[0]["msg"] != "panic recovered" || lines[0]["panic"] != "kaboom" || lines[0]["request_id"] != "panicking" {
+		t.Fatalf("expected panic to be logged with the request ID, got %v", lines[0])
+	}
+}
+
+func TestRecovery_PassesAbortHandlerOn(t *testing.T) {
+	router := NewRouter(httpadapter.NewArticleHandler(usecase.NewArticleService(nil)), nil)
+	router.GET("/abort", func(c *gin.Context) {
+		c.String(http.StatusOK, "partial")
+		panic(http.ErrAbortHandler)
+	})
+
+	defer func() {
+		if recovered := recover(); recovered != http.ErrAbortHandler {
+			t.Fatalf("expected http.ErrAbortHandler to reach net/http, got %v", recovered)
+		}
+	}()
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/abort", nil))
+	t.Fatal("expected ServeHTTP to panic")
+}
+
+func TestLimitRequestBody_PerRoute(t *testing.T) {
+	router := gin.New()
+	router.Use(limitRequestBody(4, map[string]int64{"/big": 16}))
+	read := func(c *gin.Context) {
+		if _, err := io.ReadAll(c.Request.Body); err != nil {
+			c.Status(http.StatusRequestEntityTooLarge)
+			return
+		}
+		c.Status(http.StatusOK)
+	}
+	router.POST("/small", read)
+	router.POST("/big", read)
+
+	for path, want := range map[string]int{"/small": http.StatusRequestEntityTooLarge, "/big": http.StatusOK} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader("0123456789")))
+		if rec.Code != want {
+			t.Fatalf("expected status %d for %s, got %d", want, path, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	httpadapter "articles/internal/adapter/http"
+	"articles/internal/auth"
+	"articles/internal/ratelimit"
+)
+
+// RateLimit spends a token of the caller's budget named budget on every
+// request. Callers are told apart by their principal, so every API key or
+// token user has its own budget, and anonymous callers by client IP.
+// Responses carry RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset and
+// RateLimit-Policy headers; a caller out of tokens gets 429 with
+// Retry-After. When the store fails the request is let through: a broken
+// limiter should not take the API down with it.
+func RateLimit(store ratelimit.Store, budget string, limit ratelimit.Limit) gin.HandlerFunc {
+	policy := strconv.Itoa(limit.Burst) + ";w=" + strconv.Itoa(ceilSeconds(limit.Period))
+
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		result, err := store.Take(ctx, budget+":"+client(c), limit, time.Now())
+		if err != nil {
+			slog.ErrorContext(ctx, "rate limit store failed", "error", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+		c.Header("RateLimit-Policy", policy)
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			httpadapter.WriteProblem(c, httpadapter.NewProblem(http.StatusTooManyRequests, httpadapter.CodeRateLimited,
+				"Too many requests", "the "+budget+" budget of "+strconv.Itoa(limit.Burst)+" requests per "+limit.Period.String()+" is spent"))
+			return
+		}
+		c.Next()
+	}
+}
+
+// RateLimitCredentials spends the budget named budget, kept per client IP,
+// on every request carrying an API key or bearer token. It goes ahead of
+// Authenticate, so a caller guessing credentials runs out of budget whether
+// or not they are accepted; requests without credentials pass untouched.
+func RateLimitCredentials(store ratelimit.Store, budget string, limit ratelimit.Limit) gin.HandlerFunc {
+	limited := RateLimit(store, budget, limit)
+	return func(c *gin.Context) {
+		if c.GetHeader(apiKeyHeader) == "" && c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+		limited(c)
+	}
+}
+
+// client identifies the caller of c: its principal once authenticated,
+// otherwise its address. Rate limit budgets and idempotency keys are kept
+// per client.
+func client(c *gin.Context) string {
+	if principal, ok := auth.PrincipalFrom(c.Request.Context()); ok {
+		return "principal:" + principal.Subject
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// ceilSeconds rounds d up to whole seconds, as the headers carry them.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	httpadapter "articles/internal/adapter/http"
+	"articles/internal/adapter/storage/memory"
+	"articles/internal/auth"
+	"articles/internal/ratelimit"
+	"articles/internal/usecase"
+)
+
+func newRateLimitedRouter(opts ...Option) *gin.Engine {
+	service := usecase.NewArticleService(memory.NewArticleRepository())
+	opts = append([]Option{WithRateLimit(memory.NewRateLimitStore(),
+		ratelimit.Limit{Burst: 2, Period: time.Minute},
+		ratelimit.Limit{Burst: 1, Period: time.Minute},
+	)}, opts...)
+	return NewRouter(httpadapter.NewArticleHandler(service), nil, opts...)
+}
+
+func doFrom(router http.Handler, method, path, remoteAddr string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(`{"title":"Hello"}`))
+	req.RemoteAddr = remoteAddr
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimit_SeparateReadAndWriteBudgets(t *testing.T) {
+	router := newRateLimitedRouter()
+	const client = "192.0.2.1:1234"
+
+	for i, wantRemaining := range []string{"1", "0"} {
+		rec := doFrom(router, http.MethodGet, "/article", client, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("read %d: expected status %d, got %d", i, http.StatusOK, rec.Code)
+		}
+		if got := rec.Header().Get("RateLimit-Remaining"); got != wantRemaining {
+			t.Fatalf("read %d: expected RateLimit-Remaining %s, got %q", i, wantRemaining, got)
+		}
+		if got := rec.Header().Get("RateLimit-Limit"); got != "2" {
+			t.Fatalf("expected RateLimit-Limit 2, got %q", got)
+		}
+		if got := rec.Header().Get("RateLimit-Policy"); got != "2;w=60" {
+			t.Fatalf("expected RateLimit-Policy 2;w=60, got %q", got)
+		}
+	}
+
+	rec := doFrom(router, http.MethodGet, "/article", client, nil)
+	if rec.Code != http.StatusTooManyRequests || !strings.Contains(rec.Body.String(), httpadapter.CodeRateLimited) {
+		t.Fatalf("expected 429 %s, got %d: %s", httpadapter.CodeRateLimited, rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Retry-After"); got != "30" {
+		t.Fatalf("expected Retry-After 30, got %q", got)
+	}
+
+	// Writes have their own budget.
+	if rec := doFrom(router, http.MethodPost, "/article", client, nil); rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+	if rec := doFrom(router, http.MethodPost, "/article", client, nil); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected the second write to get %d, got %d", http.StatusTooManyRequests, rec.Code)
+	}
+
+	if rec := doFrom(router, http.MethodGet, "/healthz", client, nil); rec.Code != http.StatusOK {
+		t.Fatalf("expected /healthz to be unlimited, got %d", rec.Code)
+	}
+}
+
+func TestRateLimit_KeyedByClientIP(t *testing.T) {
+	forwarded := map[string]string{"X-Forwarded-For": "198.51.100.7"}
+
+	router := newRateLimitedRouter()
+	for range 2 {
+		doFrom(router, http.MethodGet, "/article", "192.0.2.1:1234", nil)
+	}
+	if rec := doFrom(router, http.MethodGet, "/article", "192.0.2.1:1234", forwarded); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected X-Forwarded-For from an untrusted peer to be ignored, got %d", rec.Code)
+	}
+	if rec := doFrom(router, http.MethodGet, "/article", "192.0.2.2:1234", nil); rec.Code != http.StatusOK {
+		t.Fatalf("expected another client to have its own budget, got %d", rec.Code)
+	}
+
+	router = newRateLimitedRouter(WithTrustedProxies([]string{"10.0.0.0/8"}))
+	for range 2 {
+		doFrom(router, http.MethodGet, "/article", "10.0.0.1:1234", nil)
+	}
+	if rec := doFrom(router, http.MethodGet, "/article", "10.0.0.1:1234", forwarded); rec.Code != http.StatusOK {
+		t.Fatalf("expected the forwarded client of a trusted proxy to have its own budget, got %d", rec.Code)
+	}
+}
+
+func TestRateLimit_KeyedByAPIKey(t *testing.T) {
+	keys := memory.NewAPIKeyStore()
+	secrets := make([]string, 2)
+	for i := range secrets {
+		secret, key, err := auth.NewAPIKey("key", []auth.Scope{auth.ScopeArticlesRead}, time.Now())
+		if err != nil {
+			t.Fatalf("NewAPIKey returned error: %v", err)
+		}
+		if _, err := keys.CreateKey(context.Background(), key); err != nil {
+			t.Fatalf("CreateKey returned error: %v", err)
+		}
+		secrets[i] = secret
+	}
+	router := newRateLimitedRouter(WithAPIKeys(keys))
+	const client = "192.0.2.1:1234"
+
+	for range 2 {
+		doFrom(router, http.MethodGet, "/article", client, map[string]string{apiKeyHeader: secrets[0]})
+	}
+	if rec := doFrom(router, http.MethodGet, "/article", client, map[string]string{apiKeyHeader: secrets[0]}); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected the first key to be out of budget, got %d", rec.Code)
+	}
+	if rec := doFrom(router, http.MethodGet, "/article", client, map[string]string{apiKeyHeader: secrets[1]}); rec.Code != http.StatusOK {
+		t.Fatalf("expected the second key to have its own budget, got %d", rec.Code)
+	}
+	if rec := doFrom(router, http.MethodGet, "/article", client, nil); rec.Code != http.StatusOK {
+		t.Fatalf("expected anonymous requests from the same IP to have their own budget, got %d", rec.Code)
+	}
+}
+
+func TestRateLimit_RefusedCredentialsSpendTheAuthBudget(t *testing.T) {
+	service := usecase.NewArticleService(memory.NewArticleRepository())
+	router := NewRouter(httpadapter.NewArticleHandler(service), nil,
+		WithAPIKeys(memory.NewAPIKeyStore()),
+		WithAuthRateLimit(memory.NewRateLimitStore(), ratelimit.Limit{Burst: 2, Period: time.Minute}),
+	)
+	const client = "192.0.2.1:1234"
+	badKey := map[string]string{apiKeyHeader: "ak_guess"}
+
+	for i := range 2 {
+		if rec := doFrom(router, http.MethodGet, "/article", client, badKey); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("attempt %d: expected status %d, got %d", i, http.StatusUnauthorized, rec.Code)
+		}
+	}
+	rec := doFrom(router, http.MethodGet, "/article", client, badKey)
+	if rec.Code != http.StatusTooManyRequests || !strings.Contains(rec.Body.String(), httpadapter.CodeRateLimited) {
+		t.Fatalf("expected 429 %s, got %d: %s", httpadapter.CodeRateLimited, rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Retry-After"); got == "" {
+		t.Fatal("expected a Retry-After header")
+	}
+
+	// Requests without credentials, and other addresses, are not held back.
+	if rec := doFrom(router, http.MethodGet, "/article", client, nil); rec.Code != http.StatusOK {
+		t.Fatalf("expected an anonymous read to get %d, got %d", http.StatusOK, rec.Code)
+	}
+	if rec := doFrom(router, http.MethodGet, "/article", "192.0.2.2:1234", badKey); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected another address to get %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
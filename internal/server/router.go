@@ -3,27 +3,203 @@ package server
 import (
 	"context"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 
 	httpadapter "articles/internal/adapter/http"
+	"articles/internal/auth"
+	"articles/internal/idempotency"
+	"articles/internal/metrics"
+	"articles/internal/ratelimit"
 )
 
 const healthCheckTimeout = time.Second
 
-func NewRouter(articleHandler *httpadapter.ArticleHandler, healthCheck func(context.Context) error) *gin.Engine {
+const (
+	maxRequestBodyBytes = 1 << 20
+	// maxImportBodyBytes lets POST /article/import take whole exports; the
+	// body is streamed, never held in memory at once.
+	maxImportBodyBytes = 256 << 20
+)
+
+type routerOptions struct {
+	metrics            *metrics.Metrics
+	tracingServiceName string
+	idempotencyStore   idempotency.Store
+	idempotencyTTL     time.Duration
+	apiKeys            auth.KeyStore
+	tokens             TokenVerifier
+	rateLimits         ratelimit.Store
+	readLimit          ratelimit.Limit
+	writeLimit         ratelimit.Limit
+	authRateLimits     ratelimit.Store
+	authLimit          ratelimit.Limit
+	trustedProxies     []string
+}
+
+type Option func(*routerOptions)
+
+// WithMetrics records per-route request metrics and serves them on /metrics.
+func WithMetrics(m *metrics.Metrics) Option {
+	return func(o *routerOptions) {
+		o.metrics = m
+	}
+}
+
+// WithTracing starts a server span per request, named after the route
+// template and continuing any incoming traceparent. Probes and scrapes are
+// not traced.
+func WithTracing(serviceName string) Option {
+	return func(o *routerOptions) {
+		o.tracingServiceName = serviceName
+	}
+}
+
+// WithIdempotency honours the Idempotency-Key header on POST /article,
+// replaying stored responses for ttl.
+func WithIdempotency(store idempotency.Store, ttl time.Duration) Option {
+	return func(o *routerOptions) {
+		o.idempotencyStore = store
+		o.idempotencyTTL = ttl
+	}
+}
+
+// WithAPIKeys authenticates requests by their X-API-Key header. Reads are
+// open to anonymous callers, who only see published articles; a key needs
+// articles:read to read beyond that and articles:write for every change.
+func WithAPIKeys(keys auth.KeyStore) Option {
+	return func(o *routerOptions) {
+		o.apiKeys = keys
+	}
+}
+
+// WithBearerTokens also authenticates requests by an Authorization: Bearer
+// token, with the same scope checks as WithAPIKeys; the scopes come from the
+// roles the token carries.
+func WithBearerTokens(tokens TokenVerifier) Option {
+	return func(o *routerOptions) {
+		o.tokens = tokens
+	}
+}
+
+// WithRateLimit gives every client a read budget, spent by GET routes, and
+// a write budget, spent by every change, kept in store. A limit with a zero
+// Burst leaves its routes unlimited. /healthz and /metrics are never limited.
+func WithRateLimit(store ratelimit.Store, read, write ratelimit.Limit) Option {
+	return func(o *routerOptions) {
+		o.rateLimits = store
+		o.readLimit = read
+		o.writeLimit = write
+	}
+}
+
+// WithAuthRateLimit gives every client IP an auth budget, kept in store and
+// spent by each request carrying an API key or bearer token before the
+// credential is checked, so refused credentials cost the caller too. It only
+// applies with API keys or bearer tokens on.
+func WithAuthRateLimit(store ratelimit.Store, limit ratelimit.Limit) Option {
+	return func(o *routerOptions) {
+		o.authRateLimits = store
+		o.authLimit = limit
+	}
+}
+
+// WithTrustedProxies lets the proxies at the given addresses or CIDR ranges
+// report the client IP in X-Forwarded-For. Without it the client IP is the
+// connection's remote address, so clients cannot pick their own rate limit
+// budget.
+func WithTrustedProxies(proxies []string) Option {
+	return func(o *routerOptions) {
+		o.trustedProxies = proxies
+	}
+}
+
+func NewRouter(articleHandler *httpadapter.ArticleHandler, healthCheck func(context.Context) error, opts ...Option) *gin.Engine {
 	gin.SetMode(gin.ReleaseMode)
 
 	if healthCheck == nil {
 		healthCheck = func(context.Context) error { return nil }
 	}
 
+	var options routerOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	router := gin.New()
-	router.Use(gin.Logger(), gin.Recovery(), limitRequestBody(1<<20))
+	if err := router.SetTrustedProxies(options.trustedProxies); err != nil {
+		// The addresses are validated with the configuration.
+		panic(err)
+	}
+	router.Use(requestID())
+	if options.tracingServiceName != "" {
+		router.Use(otelgin.Middleware(options.tracingServiceName, otelgin.WithGinFilter(func(c *gin.Context) bool {
+			return c.FullPath() != "/healthz" && c.FullPath() != "/metrics"
+		})))
+	}
+	if options.metrics != nil {
+		// Registered ahead of Recovery so panics are counted as 500s.
+		router.Use(options.metrics.Middleware())
+	}
+	router.Use(accessLog(), recovery(), limitRequestBody(maxRequestBodyBytes, map[string]int64{
+		"/article/import": maxImportBodyBytes,
+	}))
+
+	authEnabled := options.apiKeys != nil || options.tokens != nil
+	if authEnabled {
+		if options.authRateLimits != nil && options.authLimit.Enabled() {
+			router.Use(RateLimitCredentials(options.authRateLimits, "auth", options.authLimit))
+		}
+		router.Use(Authenticate(options.apiKeys, options.tokens))
+	} else {
+		router.Use(trustCallers())
+	}
+	// read and write put a route's rate limit and scope check ahead of its
+	// handlers; without API keys or bearer tokens every route stays open.
+	guarded := func(budget string, limit ratelimit.Limit, scope auth.Scope, allowAnonymous bool) func(...gin.HandlerFunc) []gin.HandlerFunc {
+		var guards []gin.HandlerFunc
+		if options.rateLimits != nil && limit.Enabled() {
+			guards = append(guards, RateLimit(options.rateLimits, budget, limit))
+		}
+		if authEnabled {
+			guards = append(guards, RequireScope(scope, allowAnonymous))
+		}
+		return func(handlers ...gin.HandlerFunc) []gin.HandlerFunc {
+			return append(slices.Clone(guards), handlers...)
+		}
+	}
+	read := guarded("read", options.readLimit, auth.ScopeArticlesRead, true)
+	write := guarded("write", options.writeLimit, auth.ScopeArticlesWrite, false)
+
+	createArticle := []gin.HandlerFunc{articleHandler.CreateArticle}
+	if options.idempotencyStore != nil {
+		createArticle = append([]gin.HandlerFunc{Idempotency(options.idempotencyStore, options.idempotencyTTL)}, createArticle...)
+	}
 
-	router.POST("/article", articleHandler.CreateArticle)
-	router.GET("/article/:id", articleHandler.GetArticle)
+	router.POST("/article", write(createArticle...)...)
+	router.GET("/article", read(articleHandler.ListArticles)...)
+	router.GET("/article/search", read(articleHandler.SearchArticles)...)
+	router.GET("/article/export", read(articleHandler.ExportArticles)...)
+	router.POST("/article/import", write(articleHandler.ImportArticles)...)
+	router.GET("/article/by-slug/:slug", read(articleHandler.GetArticleBySlug)...)
+	router.GET("/article/:id", read(articleHandler.GetArticle)...)
+	router.PUT("/article/:id", write(articleHandler.ReplaceArticle)...)
+	router.PATCH("/article/:id", write(articleHandler.PatchArticle)...)
+	router.DELETE("/article/:id", write(articleHandler.DeleteArticle)...)
+	router.POST("/article/:id/restore", write(articleHandler.RestoreArticle)...)
+	router.GET("/article/:id/revisions", read(articleHandler.ListRevisions)...)
+	router.GET("/article/:id/revisions/diff", read(articleHandler.DiffRevisions)...)
+	router.GET("/article/:id/revisions/:rev", read(articleHandler.GetRevision)...)
+	router.POST("/article/:id/revisions/:rev/revert", write(articleHandler.RevertArticle)...)
+	router.GET("/articles", read(articleHandler.GetArticles)...)
+	router.GET("/tags", read(articleHandler.ListTags)...)
+	router.POST("/articles:method", write(customMethods(map[string]gin.HandlerFunc{
+		"batchCreate": articleHandler.BatchCreateArticles,
+	}))...)
 	router.GET("/healthz", func(c *gin.Context) {
 		ctx, cancel := context.WithTimeout(c.Request.Context(), healthCheckTimeout)
 		defer cancel()
@@ -36,12 +212,38 @@ func NewRouter(articleHandler *httpadapter.ArticleHandler, healthCheck func(cont
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
 	})
 
+	if options.metrics != nil {
+		router.GET("/metrics", gin.WrapH(options.metrics.Handler()))
+	}
+
 	return router
 }
 
-func limitRequestBody(maxBytes int64) gin.HandlerFunc {
+// customMethods serves Google API style custom methods such as
+// /articles:batchCreate. Gin reads the colon as the start of a path
+// parameter (escaping it only works under Engine.Run), so the route is
+// registered as /articles:method and dispatched here on the name.
+func customMethods(methods map[string]gin.HandlerFunc) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+		// The parameter value keeps the literal colon.
+		handler, ok := methods[strings.TrimPrefix(c.Param("method"), ":")]
+		if !ok {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+		handler(c)
+	}
+}
+
+// limitRequestBody caps request bodies at maxBytes, or at the limit given
+// for the matched route template in perRoute.
+func limitRequestBody(maxBytes int64, perRoute map[string]int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := maxBytes
+		if routeLimit, ok := perRoute[c.FullPath()]; ok {
+			limit = routeLimit
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
 		c.Next()
 	}
 }
@@ -6,9 +6,19 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
 	httpadapter "articles/internal/adapter/http"
+	"articles/internal/adapter/storage/memory"
+	"articles/internal/domain"
+	"articles/internal/metrics"
 	"articles/internal/usecase"
 )
 
@@ -60,3 +70,132 @@ func TestHealthz_DBError(t *testing.T) {
 		t.Fatalf("expected db_error in response, got %v", body)
 	}
 }
+
+func TestMetrics_Exposed(t *testing.T) {
+	m := metrics.New()
+	handler := httpadapter.NewArticleHandler(usecase.NewArticleService(nil), httpadapter.WithErrorObserver(m.ObserveDomainError))
+	router := NewRouter(handler, nil, WithMetrics(m))
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/article/abc", nil))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		`articles_http_requests_total{method="GET",route="/article/:id",status="400"} 1`,
+		`articles_domain_errors_total{code="article.invalid_id"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected metrics to contain %q, got:\n%s", want, body)
+		}
+	}
+}
+
+func TestMetrics_NotRoutedWithoutOption(t *testing.T) {
+	router := NewRouter(httpadapter.NewArticleHandler(usecase.NewArticleService(nil)), nil)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestTracing_ContinuesIncomingTraceparent(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+
+	repo := memory.NewArticleRepository()
+	router := NewRouter(httpadapter.NewArticleHandler(usecase.NewArticleService(repo)), nil, WithTracing("articles"))
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodGet, "/article/1", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	spans := recorder.Ended()
+	names := make(map[string]string, len(spans))
+	for _, span := range spans {
+		names[span.Name()] = span.SpanContext().TraceID().String()
+	}
+
+	if len(spans) != 2 {
+		t.Fatalf("expected a server and a service span only, got %v", names)
+	}
+	for _, name := range []string{"GET /article/:id", "ArticleService.GetArticle"} {
+		got, ok := names[name]
+		if !ok {
+			t.Fatalf("expected span %q, got %v", name, names)
+		}
+		if got != traceID {
+			t.Fatalf("expected span %q in trace %s, got %s", name, traceID, got)
+		}
+	}
+}
+
+func TestRouter_CustomMethods(t *testing.T) {
+	router := NewRouter(httpadapter.NewArticleHandler(usecase.NewArticleService(memory.NewArticleRepository())), nil)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/articles:batchCreate", strings.NewReader(`{"items":[{"title":"One"}]}`)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/articles:batchDelete", strings.NewReader(`{}`)))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d for an unknown method, got %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestRouter_ExportImportRoundTrip(t *testing.T) {
+	exportedAt := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	source := usecase.NewArticleService(memory.NewArticleRepository(memory.WithClock(func() time.Time { return exportedAt })))
+	target := usecase.NewArticleService(memory.NewArticleRepository())
+	ctx := usecase.WithTrusted(context.Background())
+	for _, title := range []string{"Hello", "Hello", "東京"} {
+		if _, err := source.CreateArticle(ctx, domain.ArticleInput{Title: title}); err != nil {
+			t.Fatalf("CreateArticle returned error: %v", err)
+		}
+	}
+	// Takes the slug of the first exported article.
+	if _, err := target.CreateArticle(ctx, domain.ArticleInput{Title: "Hello"}); err != nil {
+		t.Fatalf("CreateArticle returned error: %v", err)
+	}
+
+	export := httptest.NewRecorder()
+	NewRouter(httpadapter.NewArticleHandler(source), nil).
+		ServeHTTP(export, httptest.NewRequest(http.MethodGet, "/article/export", nil))
+	if export.Code != http.StatusOK {
+		t.Fatalf("expected export status %d, got %d", http.StatusOK, export.Code)
+	}
+	imported := httptest.NewRecorder()
+	NewRouter(httpadapter.NewArticleHandler(target), nil).
+		ServeHTTP(imported, httptest.NewRequest(http.MethodPost, "/article/import", export.Body))
+	if imported.Code != http.StatusOK {
+		t.Fatalf("expected import status %d, got %d: %s", http.StatusOK, imported.Code, imported.Body.String())
+	}
+
+	want := map[int64]string{2: "東京", 3: "hello-2", 4: "hello-3"}
+	for id, slug := range want {
+		got, err := target.GetArticle(ctx, id)
+		if err != nil {
+			t.Fatalf("GetArticle returned error: %v", err)
+		}
+		if got.Slug != slug || !got.CreatedAt.Equal(exportedAt) {
+			t.Fatalf("expected article %d with slug %q created at %v, got %q at %v", id, slug, exportedAt, got.Slug, got.CreatedAt)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package storetest
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"articles/internal/idempotency"
+)
+
+var idempotencyStoreTests = []testCase{
+	{"Idempotency/ReserveThenReplay", testReserveThenReplay},
+	{"Idempotency/ReserveReturnsInFlightRecord", testReserveReturnsInFlightRecord},
+	{"Idempotency/ConcurrentReserveHasOneWinner", testConcurrentReserveHasOneWinner},
+	{"Idempotency/ReleaseFreesKey", testReleaseFreesKey},
+	{"Idempotency/ExpiredRecordIsReplaced", testExpiredRecordIsReplaced},
+	{"Idempotency/DeleteExpired", testDeleteExpired},
+}
+
+func newRecord(key string) idempotency.Record {
+	return idempotency.Record{
+		Key:         key,
+		Fingerprint: "fp-" + key,
+		CreatedAt:   base,
+		ExpiresAt:   base.Add(time.Hour),
+	}
+}
+
+func reserve(t *testing.T, store idempotency.Store, record idempotency.Record, now time.Time) (idempotency.Record, bool) {
+	t.Helper()
+
+	existing, reserved, err := store.Reserve(context.Background(), record, now)
+	if err != nil {
+		t.Fatalf("Reserve returned error: %v", err)
+	}
+	return existing, reserved
+}
+
+func testReserveThenReplay(t *testing.T, stores Stores) {
+	store := stores.Idempotency
+	ctx := context.Background()
+
+	if _, reserved := reserve(t, store, newRecord("k1"), base); !reserved {
+		t.Fatal("expected the first Reserve to win")
+	}
+
+	header := http.Header{"Content-Type": {"application/json"}, "Etag": {`"1"`}}
+	body := []byte(`{"id":1,"title":"Ünïcode"}`)
+	if err := store.Complete(ctx, "k1", http.StatusCreated, header, body); err != nil {
+		t.Fatalf("Complete returned error: %v", err)
+	}
+
+	existing, reserved := reserve(t, store, newRecord("k1"), base.Add(time.Minute))
+	if reserved {
+		t.Fatal("expected the second Reserve to return the stored record")
+	}
+	if !existing.Completed() || existing.StatusCode != http.StatusCreated {
+		t.Fatalf("expected a completed 201 record, got %+v", existing)
+	}
+	if existing.Fingerprint != "fp-k1" {
+		t.Fatalf("expected fingerprint fp-k1, got %q", existing.Fingerprint)
+	}
+	if string(existing.Body) != string(body) {
+		t.Fatalf("expected body %s, got %s", body, existing.Body)
+	}
+	if existing.Header.Get("Content-Type") != "application/json" || existing.Header.Get("ETag") != `"1"` {
+		t.Fatalf("expected stored headers, got %v", existing.Header)
+	}
+}
+
+func testReserveReturnsInFlightRecord(t *testing.T, stores Stores) {
+	store := stores.Idempotency
+	reserve(t, store, newRecord("k1"), base)
+
+	existing, reserved := reserve(t, store, newRecord("k1"), base)
+	if reserved {
+		t.Fatal("expected the key to stay reserved")
+	}
+	if existing.Completed() {
+		t.Fatalf("expected an in-flight record, got %+v", existing)
+	}
+}
+
+func testConcurrentReserveHasOneWinner(t *testing.T, stores Stores) {
+	store := stores.Idempotency
+	const workers = 10
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		winners int
+	)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, reserved, err := store.Reserve(context.Background(), newRecord("race"), base)
+			if err != nil {
+				t.Errorf("Reserve returned error: %v", err)
+				return
+			}
+			if reserved {
+				mu.Lock()
+				winners++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if winners != 1 {
+		t.Fatalf("expected exactly one winner, got %d", winners)
+	}
+}
+
+func testReleaseFreesKey(t *testing.T, stores Stores) {
+	store := stores.Idempotency
+	reserve(t, store, newRecord("k1"), base)
+
+	if err := store.Release(context.Background(), "k1"); err != nil {
+		t.Fatalf("Release returned error: %v", err)
+	}
+
+	if _, reserved := reserve(t, store, newRecord("k1"), base); !reserved {
+		t.Fatal("expected a released key to be reservable again")
+	}
+}
+
+func testExpiredRecordIsReplaced(t *testing.T, stores Stores) {
+	store := stores.Idempotency
+	reserve(t, store, newRecord("k1"), base)
+	if err := store.Complete(context.Background(), "k1", http.StatusCreated, nil, []byte("old")); err != nil {
+		t.Fatalf("Complete returned error: %v", err)
+	}
+
+	later := base.Add(2 * time.Hour)
+	fresh := newRecord("k1")
+	fresh.Fingerprint = "fp-new"
+	fresh.CreatedAt, fresh.ExpiresAt = later, later.Add(time.Hour)
+	if _, reserved := reserve(t, store, fresh, later); !reserved {
+		t.Fatal("expected an expired record to be replaced")
+	}
+
+	existing, _ := reserve(t, store, newRecord("k1"), later)
+	if existing.Fingerprint != "fp-new" || existing.Completed() {
+		t.Fatalf("expected the new in-flight record, got %+v", existing)
+	}
+}
+
+func testDeleteExpired(t *testing.T, stores Stores) {
+	store := stores.Idempotency
+	reserve(t, store, newRecord("old"), base)
+	young := newRecord("young")
+	young.ExpiresAt = base.Add(3 * time.Hour)
+	reserve(t, store, young, base)
+
+	deleted, err := store.DeleteExpired(context.Background(), base.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("DeleteExpired returned error: %v", err)
+	}
+	if deleted != 1 {
+		t.Fatalf("expected 1 deleted record, got %d", deleted)
+	}
+
+	if _, reserved := reserve(t, store, newRecord("young"), base.Add(2*time.Hour)); reserved {
+		t.Fatal("expected the unexpired record to survive")
+	}
+}
@@ -0,0 +1,105 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"articles/internal/auth"
+)
+
+var keyStoreTests = []testCase{
+	{"APIKeys/CreateThenGetByHash", testCreateThenGetByHash},
+	{"APIKeys/GetUnknownHash", testGetUnknownHash},
+	{"APIKeys/ListOldestFirst", testListOldestFirst},
+	{"APIKeys/RevokeKeepsFirstTime", testRevokeKeepsFirstTime},
+	{"APIKeys/RevokeUnknownKey", testRevokeUnknownKey},
+}
+
+func createKey(t *testing.T, store auth.KeyStore, name string, at time.Time) (string, auth.APIKey) {
+	t.Helper()
+
+	secret, key, err := auth.NewAPIKey(name, []auth.Scope{auth.ScopeArticlesRead, auth.ScopeArticlesWrite}, at)
+	if err != nil {
+		t.Fatalf("NewAPIKey returned error: %v", err)
+	}
+	created, err := store.CreateKey(context.Background(), key)
+	if err != nil {
+		t.Fatalf("CreateKey returned error: %v", err)
+	}
+	return secret, created
+}
+
+func testCreateThenGetByHash(t *testing.T, stores Stores) {
+	store := stores.Keys
+	secret, created := createKey(t, store, "ci", base)
+	if created.ID == 0 {
+		t.Fatal("expected CreateKey to assign an ID")
+	}
+
+	got, err := store.GetKeyByHash(context.Background(), auth.HashKey(secret))
+	if err != nil {
+		t.Fatalf("GetKeyByHash returned error: %v", err)
+	}
+	if got.ID != created.ID || got.Name != "ci" || got.Prefix != created.Prefix || !got.CreatedAt.Equal(base) || got.Revoked() {
+		t.Fatalf("expected %+v, got %+v", created, got)
+	}
+	if !slices.Equal(got.Scopes, created.Scopes) {
+		t.Fatalf("expected scopes %v, got %v", created.Scopes, got.Scopes)
+	}
+}
+
+func testGetUnknownHash(t *testing.T, stores Stores) {
+	store := stores.Keys
+	createKey(t, store, "ci", base)
+
+	_, err := store.GetKeyByHash(context.Background(), auth.HashKey("ak_unknown"))
+	if !errors.Is(err, auth.ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+}
+
+func testListOldestFirst(t *testing.T, stores Stores) {
+	store := stores.Keys
+	_, first := createKey(t, store, "first", base)
+	_, second := createKey(t, store, "second", base.Add(time.Minute))
+
+	keys, err := store.ListKeys(context.Background())
+	if err != nil {
+		t.Fatalf("ListKeys returned error: %v", err)
+	}
+	if len(keys) != 2 || keys[0].ID != first.ID || keys[1].ID != second.ID {
+		t.Fatalf("expected keys %d and %d, got %+v", first.ID, second.ID, keys)
+	}
+}
+
+func testRevokeKeepsFirstTime(t *testing.T, stores Stores) {
+	store := stores.Keys
+	ctx := context.Background()
+	secret, created := createKey(t, store, "ci", base)
+
+	revokedAt := base.Add(time.Hour)
+	if err := store.RevokeKey(ctx, created.ID, revokedAt); err != nil {
+		t.Fatalf("RevokeKey returned error: %v", err)
+	}
+	if err := store.RevokeKey(ctx, created.ID, revokedAt.Add(time.Hour)); err != nil {
+		t.Fatalf("second RevokeKey returned error: %v", err)
+	}
+
+	got, err := store.GetKeyByHash(ctx, auth.HashKey(secret))
+	if err != nil {
+		t.Fatalf("GetKeyByHash returned error: %v", err)
+	}
+	if !got.Revoked() || !got.RevokedAt.Equal(revokedAt) {
+		t.Fatalf("expected the key to be revoked at %v, got %v", revokedAt, got.RevokedAt)
+	}
+}
+
+func testRevokeUnknownKey(t *testing.T, stores Stores) {
+	store := stores.Keys
+	if err := store.RevokeKey(context.Background(), 999, base); !errors.Is(err, auth.ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+}
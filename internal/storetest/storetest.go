@@ -0,0 +1,38 @@
+// Package storetest holds the behaviour every auth.KeyStore and
+// idempotency.Store must share. Storage adapters call Run once from their own
+// tests with a factory that builds both stores.
+package storetest
+
+import (
+	"testing"
+	"time"
+
+	"articles/internal/auth"
+	"articles/internal/idempotency"
+)
+
+// Stores are the stores of one storage adapter, all empty.
+type Stores struct {
+	Keys        auth.KeyStore
+	Idempotency idempotency.Store
+}
+
+// Factory returns empty stores. It is called once per subtest.
+type Factory func(t *testing.T) Stores
+
+type testCase struct {
+	name string
+	run  func(t *testing.T, stores Stores)
+}
+
+func Run(t *testing.T, newStores Factory) {
+	t.Helper()
+
+	for _, tc := range append(keyStoreTests, idempotencyStoreTests...) {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.run(t, newStores(t))
+		})
+	}
+}
+
+var base = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
@@ -0,0 +1,57 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+
+	"articles/internal/config"
+)
+
+func TestSetup_StdoutExporterWritesFile(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	path := filepath.Join(t.TempDir(), "spans.json")
+	shutdown, err := Setup(context.Background(), config.Tracing{Exporter: config.TracingStdout, File: path})
+	if err != nil {
+		t.Fatalf("Setup returned error: %v", err)
+	}
+
+	_, span := otel.Tracer("test").Start(context.Background(), "exported-span")
+	span.End()
+
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read trace file: %v", err)
+	}
+	for _, want := range []string{`"Name":"exported-span"`, `"Value":"articles"`} {
+		if !strings.Contains(string(data), want) {
+			t.Fatalf("expected trace file to contain %s, got %s", want, data)
+		}
+	}
+}
+
+func TestSetup_NoneInstallsNoProvider(t *testing.T) {
+	previous := otel.GetTracerProvider()
+
+	shutdown, err := Setup(context.Background(), config.Tracing{Exporter: config.TracingNone})
+	if err != nil {
+		t.Fatalf("Setup returned error: %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown returned error: %v", err)
+	}
+
+	if otel.GetTracerProvider() != previous {
+		t.Fatalf("expected the tracer provider to be left alone")
+	}
+}
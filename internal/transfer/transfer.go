@@ -0,0 +1,331 @@
+// Package transfer reads and writes articles in the bulk export and import
+// formats: newline-delimited JSON and CSV.
+package transfer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"articles/internal/domain"
+)
+
+type Format string
+
+const (
+	NDJSON Format = "ndjson"
+	CSV    Format = "csv"
+)
+
+// ParseFormat maps a client-supplied format name to a Format. An empty
+// value selects NDJSON.
+func ParseFormat(raw string) (Format, error) {
+	switch format := Format(strings.ToLower(raw)); format {
+	case "":
+		return NDJSON, nil
+	case NDJSON, CSV:
+		return format, nil
+	default:
+		return "", domain.ErrInvalidFormat
+	}
+}
+
+func (f Format) ContentType() string {
+	if f == CSV {
+		return "text/csv; charset=utf-8"
+	}
+	return "application/x-ndjson"
+}
+
+// columns is the CSV header and the order of fields in every row. Import
+// matches columns by name, so files with other columns or another order
+// are accepted as long as they have a title column. Tags share one column,
+// separated by tagSeparator, which no valid tag contains.
+var columns = []string{"id", "title", "slug", "body", "summary", "author_id", "tags", "status", "publish_at", "version", "created_at", "updated_at"}
+
+const tagSeparator = ","
+
+// record is the NDJSON shape of an article, the same as the API's.
+type record struct {
+	ID        int64      `json:"id,omitempty"`
+	Title     string     `json:"title"`
+	Slug      string     `json:"slug"`
+	Body      string     `json:"body"`
+	Summary   string     `json:"summary"`
+	AuthorID  string     `json:"author_id"`
+	Tags      []string   `json:"tags"`
+	Status    string     `json:"status,omitempty"`
+	PublishAt *time.Time `json:"publish_at"`
+	Version   int64      `json:"version,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// Encoder writes articles in one format. Output is buffered until Flush.
+type Encoder struct {
+	format      Format
+	w           *bufio.Writer
+	csv         *csv.Writer
+	json        *json.Encoder
+	wroteHeader bool
+}
+
+func NewEncoder(w io.Writer, format Format) *Encoder {
+	buffered := bufio.NewWriter(w)
+	e := &Encoder{format: format, w: buffered}
+	if format == CSV {
+		e.csv = csv.NewWriter(buffered)
+	} else {
+		e.json = json.NewEncoder(buffered)
+		e.json.SetEscapeHTML(false)
+	}
+	return e
+}
+
+func (e *Encoder) Encode(article domain.Article) error {
+	if e.format != CSV {
+		tags := article.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+		return e.json.Encode(record{
+			ID:        article.ID,
+			Title:     article.Title,
+			Slug:      article.Slug,
+			Body:      article.Body,
+			Summary:   article.Summary,
+			AuthorID:  article.AuthorID,
+			Tags:      tags,
+			Status:    string(article.Status),
+			PublishAt: article.PublishAt,
+			Version:   article.Version,
+			CreatedAt: article.CreatedAt,
+			UpdatedAt: article.UpdatedAt,
+		})
+	}
+
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	return e.csv.Write([]string{
+		strconv.FormatInt(article.ID, 10),
+		article.Title,
+		article.Slug,
+		article.Body,
+		article.Summary,
+		article.AuthorID,
+		strings.Join(article.Tags, tagSeparator),
+		string(article.Status),
+		formatTime(article.PublishAt),
+		strconv.FormatInt(article.Version, 10),
+		article.CreatedAt.UTC().Format(time.RFC3339Nano),
+		article.UpdatedAt.UTC().Format(time.RFC3339Nano),
+	})
+}
+
+// Flush writes out buffered output. A CSV export of no articles still gets
+// its header row.
+func (e *Encoder) Flush() error {
+	if e.format == CSV {
+		if err := e.writeHeader(); err != nil {
+			return err
+		}
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+	}
+	return e.w.Flush()
+}
+
+func (e *Encoder) writeHeader() error {
+	if e.wroteHeader {
+		return nil
+	}
+	e.wroteHeader = true
+	return e.csv.Write(columns)
+}
+
+// formatTime formats an optional time for a CSV cell, empty when t is nil.
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// Decoder reads import records one at a time. Only title, slug, body,
+// summary, author_id, tags, status, publish_at and created_at are read; IDs,
+// versions and updated_at are assigned anew.
+type Decoder struct {
+	format Format
+	r      *bufio.Reader
+	csv    *csv.Reader
+	// index maps CSV column names to their position; nil until the header
+	// has been read.
+	index map[string]int
+	line  int
+}
+
+func NewDecoder(r io.Reader, format Format) *Decoder {
+	d := &Decoder{format: format}
+	if format == CSV {
+		d.csv = csv.NewReader(r)
+		d.csv.FieldsPerRecord = -1
+	} else {
+		d.r = bufio.NewReader(r)
+	}
+	return d
+}
+
+// Next returns the next record, or io.EOF after the last one. A record that
+// cannot be parsed is returned with Err set; a returned error means the
+// input itself could not be read and decoding must stop.
+func (d *Decoder) Next() (domain.ImportRecord, error) {
+	if d.format == CSV {
+		return d.nextCSV()
+	}
+	return d.nextNDJSON()
+}
+
+func (d *Decoder) nextNDJSON() (domain.ImportRecord, error) {
+	for {
+		raw, err := d.r.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return domain.ImportRecord{}, err
+		}
+		if len(raw) == 0 && errors.Is(err, io.EOF) {
+			return domain.ImportRecord{}, io.EOF
+		}
+		d.line++
+
+		raw = bytes.TrimSpace(raw)
+		if len(raw) == 0 {
+			if errors.Is(err, io.EOF) {
+				return domain.ImportRecord{}, io.EOF
+			}
+			continue
+		}
+
+		rec := domain.ImportRecord{Line: d.line}
+		var decoded record
+		if jsonErr := json.Unmarshal(raw, &decoded); jsonErr != nil {
+			rec.Err = fmt.Errorf("%w: %v", domain.ErrInvalidRecord, jsonErr)
+			return rec, nil
+		}
+		if err := checkSlug(decoded.Slug); err != nil {
+			rec.Err = err
+			return rec, nil
+		}
+		rec.Input = domain.ArticleInput{
+			Title:     decoded.Title,
+			Body:      decoded.Body,
+			Summary:   decoded.Summary,
+			AuthorID:  decoded.AuthorID,
+			Tags:      decoded.Tags,
+			Status:    domain.ArticleStatus(decoded.Status),
+			PublishAt: decoded.PublishAt,
+		}
+		rec.Slug, rec.CreatedAt = decoded.Slug, decoded.CreatedAt
+		return rec, nil
+	}
+}
+
+func (d *Decoder) nextCSV() (domain.ImportRecord, error) {
+	if d.index == nil {
+		if err := d.readHeader(); err != nil {
+			return domain.ImportRecord{}, err
+		}
+	}
+
+	row, err := d.csv.Read()
+	var parseErr *csv.ParseError
+	switch {
+	case errors.As(err, &parseErr):
+		return domain.ImportRecord{Line: parseErr.StartLine, Err: fmt.Errorf("%w: %v", domain.ErrInvalidRecord, parseErr.Err)}, nil
+	case err != nil:
+		return domain.ImportRecord{}, err
+	}
+
+	line, _ := d.csv.FieldPos(0)
+	field := func(name string) string {
+		if i, ok := d.index[name]; ok && i < len(row) {
+			return row[i]
+		}
+		return ""
+	}
+	var tags []string
+	if raw := field("tags"); raw != "" {
+		tags = strings.Split(raw, tagSeparator)
+	}
+	var publishAt *time.Time
+	if raw := field("publish_at"); raw != "" {
+		t, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			return domain.ImportRecord{Line: line, Err: fmt.Errorf("%w: publish_at: %v", domain.ErrInvalidRecord, err)}, nil
+		}
+		publishAt = &t
+	}
+	var createdAt time.Time
+	if raw := field("created_at"); raw != "" {
+		if createdAt, err = time.Parse(time.RFC3339Nano, raw); err != nil {
+			return domain.ImportRecord{Line: line, Err: fmt.Errorf("%w: created_at: %v", domain.ErrInvalidRecord, err)}, nil
+		}
+	}
+	if err := checkSlug(field("slug")); err != nil {
+		return domain.ImportRecord{Line: line, Err: err}, nil
+	}
+	return domain.ImportRecord{
+		Line:      line,
+		Slug:      field("slug"),
+		CreatedAt: createdAt,
+		Input: domain.ArticleInput{
+			Title:     field("title"),
+			Body:      field("body"),
+			Summary:   field("summary"),
+			AuthorID:  field("author_id"),
+			Tags:      tags,
+			Status:    domain.ArticleStatus(field("status")),
+			PublishAt: publishAt,
+		},
+	}, nil
+}
+
+// checkSlug accepts an empty slug and those Slugify could have made.
+func checkSlug(slug string) error {
+	if slug != "" && domain.Slugify(slug) != slug {
+		return fmt.Errorf("%w: slug %q is not a slug", domain.ErrInvalidRecord, slug)
+	}
+	return nil
+}
+
+func (d *Decoder) readHeader() error {
+	header, err := d.csv.Read()
+	switch {
+	case errors.Is(err, io.EOF):
+		return io.EOF
+	case errors.As(err, new(*csv.ParseError)):
+		return fmt.Errorf("%w: %v", domain.ErrInvalidCSVHeader, err)
+	case err != nil:
+		return err
+	}
+
+	d.index = make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, dup := d.index[name]; !dup {
+			d.index[name] = i
+		}
+	}
+	if _, ok := d.index["title"]; !ok {
+		return domain.ErrInvalidCSVHeader
+	}
+	return nil
+}
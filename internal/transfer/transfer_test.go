@@ -0,0 +1,155 @@
+package transfer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"articles/internal/domain"
+)
+
+func decodeAll(t *testing.T, input string, format Format) []domain.ImportRecord {
+	t.Helper()
+
+	d := NewDecoder(strings.NewReader(input), format)
+	var records []domain.ImportRecord
+	for {
+		rec, err := d.Next()
+		if errors.Is(err, io.EOF) {
+			return records
+		}
+		if err != nil {
+			t.Fatalf("Next returned error: %v", err)
+		}
+		records = append(records, rec)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	published := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	articles := []domain.Article{
+		{ID: 1, Title: "Plain", Slug: "plain-2", AuthorID: "a-1", Tags: []string{"c++", "go"}, Status: domain.StatusPublished, PublishAt: &published, Version: 2, CreatedAt: time.Unix(0, 0).UTC()},
+		{ID: 2, Title: `Quotes "and", commas`, Slug: "東京", Body: "line one\nline two <b>", Summary: "ünïcode", CreatedAt: published.Add(time.Nanosecond)},
+	}
+
+	for _, format := range []Format{NDJSON, CSV} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			e := NewEncoder(&buf, format)
+			for _, article := range articles {
+				if err := e.Encode(article); err != nil {
+					t.Fatalf("Encode returned error: %v", err)
+				}
+			}
+			if err := e.Flush(); err != nil {
+				t.Fatalf("Flush returned error: %v", err)
+			}
+
+			records := decodeAll(t, buf.String(), format)
+			if len(records) != len(articles) {
+				t.Fatalf("expected %d records, got %d", len(articles), len(records))
+			}
+			for i, rec := range records {
+				want := articles[i]
+				if rec.Err != nil || rec.Input.Title != want.Title || rec.Input.Body != want.Body ||
+					rec.Input.Summary != want.Summary || rec.Input.AuthorID != want.AuthorID ||
+					!slices.Equal(rec.Input.Tags, want.Tags) || rec.Input.Status != want.Status ||
+					!sameTime(rec.Input.PublishAt, want.PublishAt) ||
+					rec.Slug != want.Slug || !rec.CreatedAt.Equal(want.CreatedAt) {
+					t.Fatalf("expected %+v to round-trip, got %+v", want, rec)
+				}
+			}
+		})
+	}
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func TestEncoder_EmptyCSVHasHeader(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf, CSV).Flush(); err != nil {
+		t.Fatalf("Flush returned error: %v", err)
+	}
+
+	if got := buf.String(); got != strings.Join(columns, ",")+"\n" {
+		t.Fatalf("expected only the header, got %q", got)
+	}
+}
+
+func TestDecoder_NDJSONReportsBadLines(t *testing.T) {
+	input := "{\"title\":\"One\"}\n\nnot json\n{\"title\":\"Four\"}"
+
+	records := decodeAll(t, input, NDJSON)
+
+	if len(records) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(records))
+	}
+	if records[1].Line != 3 || !errors.Is(records[1].Err, domain.ErrInvalidRecord) {
+		t.Fatalf("expected line 3 to be invalid, got %+v", records[1])
+	}
+	if records[2].Line != 4 || records[2].Input.Title != "Four" {
+		t.Fatalf("expected line 4 to be read without a trailing newline, got %+v", records[2])
+	}
+}
+
+func TestDecoder_CSVLinesAndColumns(t *testing.T) {
+	input := "Summary,TITLE,extra\n" +
+		"s1,One,x\n" +
+		"s2,\"Two\nlines\",x\n" +
+		"s3,Three\n"
+
+	records := decodeAll(t, input, CSV)
+
+	if len(records) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(records))
+	}
+	if records[0].Line != 2 || records[0].Input.Title != "One" || records[0].Input.Summary != "s1" {
+		t.Fatalf("unexpected first record: %+v", records[0])
+	}
+	if records[2].Line != 5 || records[2].Input.Title != "Three" {
+		t.Fatalf("expected the multi-line field to count its lines, got %+v", records[2])
+	}
+}
+
+func TestDecoder_RejectsInvalidSlugs(t *testing.T) {
+	ndjson := decodeAll(t, `{"title":"One","slug":"Not a slug"}`, NDJSON)
+	csv := decodeAll(t, "title,slug\nOne,-x-\nTwo,\n", CSV)
+
+	for _, rec := range []domain.ImportRecord{ndjson[0], csv[0]} {
+		if !errors.Is(rec.Err, domain.ErrInvalidRecord) {
+			t.Fatalf("expected ErrInvalidRecord, got %+v", rec)
+		}
+	}
+	if csv[1].Err != nil || csv[1].Slug != "" {
+		t.Fatalf("expected an empty slug to be left for the repository, got %+v", csv[1])
+	}
+}
+
+func TestDecoder_CSVWithoutTitleColumn(t *testing.T) {
+	_, err := NewDecoder(strings.NewReader("name,body\nx,y\n"), CSV).Next()
+
+	if !errors.Is(err, domain.ErrInvalidCSVHeader) {
+		t.Fatalf("expected ErrInvalidCSVHeader, got %v", err)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if format, err := ParseFormat(""); err != nil || format != NDJSON {
+		t.Fatalf("expected ndjson by default, got %q, %v", format, err)
+	}
+	if format, err := ParseFormat("CSV"); err != nil || format != CSV {
+		t.Fatalf("expected csv, got %q, %v", format, err)
+	}
+	if _, err := ParseFormat("xml"); !errors.Is(err, domain.ErrInvalidFormat) {
+		t.Fatalf("expected ErrInvalidFormat, got %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"context"
+
+	"articles/internal/domain"
+)
+
+type actorKey struct{}
+
+// WithActor records who is acting in ctx. Revisions made with the context
+// carry actor as their ChangedBy.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// actorFrom returns the actor set by WithActor, or "" when there is none.
+func actorFrom(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+type authorKey struct{}
+
+// WithAuthor records the author the caller in ctx writes as. Articles
+// created with the context are attributed to author, whatever the input
+// says.
+func WithAuthor(ctx context.Context, author string) context.Context {
+	return context.WithValue(ctx, authorKey{}, author)
+}
+
+// authorFrom returns the author set by WithAuthor, or "" when there is none.
+func authorFrom(ctx context.Context) string {
+	author, _ := ctx.Value(authorKey{}).(string)
+	return author
+}
+
+// attributed returns input with its author replaced by the one set in ctx,
+// if any.
+func attributed(ctx context.Context, input domain.ArticleInput) domain.ArticleInput {
+	if author := authorFrom(ctx); author != "" {
+		input.AuthorID = author
+	}
+	return input
+}
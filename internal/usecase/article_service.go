@@ -2,36 +2,308 @@ package usecase
 
 import (
 	"context"
+	"log/slog"
+	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
+	"articles/internal/auth"
 	"articles/internal/domain"
 )
 
 type ArticleService struct {
-	repo domain.ArticleRepository
+	repo      domain.ArticleRepository
+	revisions domain.RevisionRepository
+	tx        domain.Transactor
+	policy    Policy
+}
+
+type Option func(*ArticleService)
+
+// WithRevisions records a revision of every article the service creates or
+// changes, which the revision history, diffs and reverts are built from.
+func WithRevisions(revisions domain.RevisionRepository) Option {
+	return func(s *ArticleService) {
+		s.revisions = revisions
+	}
+}
+
+// WithTransactor makes every change and the revisions recorded for it one
+// unit of work, so a change whose revision cannot be stored is undone. It
+// should run on the storage behind the repositories. Without one the change
+// is made first and kept if its revision fails, which only suits storage
+// whose revisions cannot fail to save.
+func WithTransactor(tx domain.Transactor) Option {
+	return func(s *ArticleService) {
+		s.tx = tx
+	}
 }
 
-func NewArticleService(repo domain.ArticleRepository) *ArticleService {
-	return &ArticleService{repo: repo}
+// WithPolicy replaces DefaultPolicy as the rules deciding who may change
+// what.
+func WithPolicy(policy Policy) Option {
+	return func(s *ArticleService) {
+		s.policy = policy
+	}
 }
 
-func (s *ArticleService) CreateArticle(ctx context.Context, title string) (domain.Article, error) {
-	article, err := domain.NewArticle(title)
+func NewArticleService(repo domain.ArticleRepository, opts ...Option) *ArticleService {
+	s := &ArticleService{repo: repo, policy: DefaultPolicy}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *ArticleService) CreateArticle(ctx context.Context, input domain.ArticleInput) (_ domain.Article, err error) {
+	ctx, span := startSpan(ctx, "ArticleService.CreateArticle")
+	defer endSpan(span, &err)
+
+	if err := s.policy.Authorize(ctx, ActionCreate, nil); err != nil {
+		return domain.Article{}, err
+	}
+	if schedules(input.PublishAt) {
+		if err := s.policy.Authorize(ctx, ActionPublish, nil); err != nil {
+			return domain.Article{}, err
+		}
+	}
+	article, err := domain.NewArticle(attributed(ctx, input))
 	if err != nil {
 		return domain.Article{}, err
 	}
+	if !domain.CanTransition("", article.Status) {
+		return domain.Article{}, domain.ErrStatusTransition
+	}
 
-	created, err := s.repo.Save(ctx, article)
+	created, err := s.writeArticle(ctx, func(ctx context.Context) (domain.Article, error) {
+		return s.repo.Save(ctx, article)
+	})
 	if err != nil {
 		return domain.Article{}, err
 	}
 
+	slog.InfoContext(ctx, "article created", "article_id", created.ID)
 	return created, nil
 }
 
-func (s *ArticleService) GetArticle(ctx context.Context, id int64) (domain.Article, error) {
+func (s *ArticleService) GetArticle(ctx context.Context, id int64) (_ domain.Article, err error) {
+	ctx, span := startSpan(ctx, "ArticleService.GetArticle", attribute.Int64("article.id", id))
+	defer endSpan(span, &err)
+
+	if id <= 0 {
+		return domain.Article{}, domain.ErrInvalidID
+	}
+
+	return s.getVisible(ctx, id)
+}
+
+// GetArticleBySlug finds an article by its current or a former slug; the
+// article returned carries the current one.
+func (s *ArticleService) GetArticleBySlug(ctx context.Context, slug string) (_ domain.Article, err error) {
+	ctx, span := startSpan(ctx, "ArticleService.GetArticleBySlug", attribute.String("article.slug", slug))
+	defer endSpan(span, &err)
+
+	if slug == "" {
+		return domain.Article{}, domain.ErrArticleNotFound
+	}
+
+	article, err := s.repo.GetBySlug(ctx, slug)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	if !s.visible(ctx, article) {
+		return domain.Article{}, domain.ErrArticleNotFound
+	}
+
+	return article, nil
+}
+
+// ListArticles returns a page of articles, newest first. A non-empty filter
+// keeps only the articles carrying its tags.
+func (s *ArticleService) ListArticles(ctx context.Context, limit int, cursor string, filter domain.TagFilter) (_ domain.ArticlePage, err error) {
+	ctx, span := startSpan(ctx, "ArticleService.ListArticles", attribute.Int("page.limit", limit))
+	defer endSpan(span, &err)
+
+	if limit == 0 {
+		limit = domain.DefaultPageSize
+	}
+	if limit < 0 || limit > domain.MaxPageSize {
+		return domain.ArticlePage{}, domain.ErrInvalidLimit
+	}
+
+	tags, err := normalizeTagFilter(filter)
+	if err != nil {
+		return domain.ArticlePage{}, err
+	}
+
+	scope := s.readScope(ctx)
+	query := domain.ListArticlesQuery{Limit: limit + 1, Tags: tags, Status: scope.status, Owner: scope.owner}
+	if cursor != "" {
+		after, err := domain.ParseArticleCursor(cursor)
+		if err != nil {
+			return domain.ArticlePage{}, err
+		}
+		query.After = &after
+	}
+
+	// One extra row tells us whether another page exists without a COUNT query.
+	articles, err := s.repo.List(ctx, query)
+	if err != nil {
+		return domain.ArticlePage{}, err
+	}
+
+	page := domain.ArticlePage{Items: articles}
+	if len(articles) > limit {
+		page.Items = articles[:limit]
+		last := page.Items[limit-1]
+		page.NextCursor = &domain.ArticleCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	return page, nil
+}
+
+// UpdateArticle applies patch to the article if it is still at
+// expectedVersion. An expectedVersion of 0 skips the check.
+func (s *ArticleService) UpdateArticle(ctx context.Context, id, expectedVersion int64, patch domain.ArticlePatch) (_ domain.Article, err error) {
+	ctx, span := startSpan(ctx, "ArticleService.UpdateArticle", attribute.Int64("article.id", id))
+	defer endSpan(span, &err)
+
 	if id <= 0 {
 		return domain.Article{}, domain.ErrInvalidID
 	}
 
-	return s.repo.GetByID(ctx, id)
+	current, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	if err := s.policy.Authorize(ctx, ActionUpdate, &current); err != nil {
+		return domain.Article{}, err
+	}
+	if publishes(current, patch) {
+		if err := s.policy.Authorize(ctx, ActionPublish, &current); err != nil {
+			return domain.Article{}, err
+		}
+	}
+	if expectedVersion != 0 && current.Version != expectedVersion {
+		return domain.Article{}, domain.ErrVersionConflict
+	}
+
+	input := domain.ArticleInput{
+		Title:    current.Title,
+		Body:     current.Body,
+		Summary:  current.Summary,
+		AuthorID: current.AuthorID,
+		Tags:     current.Tags,
+	}
+	if patch.Title != nil {
+		input.Title = *patch.Title
+	}
+	if patch.Body != nil {
+		input.Body = *patch.Body
+	}
+	if patch.Summary != nil {
+		input.Summary = *patch.Summary
+	}
+	if patch.Tags != nil {
+		input.Tags = *patch.Tags
+	}
+
+	validated, err := domain.NewArticle(input)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	if validated.Title != current.Title {
+		// A new title gets a new slug; the repository keeps the old one
+		// resolving.
+		current.Slug = validated.Slug
+	}
+	current.Title = validated.Title
+	current.Body = validated.Body
+	current.Summary = validated.Summary
+	current.Tags = validated.Tags
+	if err := applyStatusPatch(&current, patch, time.Now().UTC()); err != nil {
+		return domain.Article{}, err
+	}
+
+	updated, err := s.writeArticle(ctx, func(ctx context.Context) (domain.Article, error) {
+		return s.repo.Update(ctx, current)
+	})
+	if err != nil {
+		return domain.Article{}, err
+	}
+
+	slog.InfoContext(ctx, "article updated", "article_id", updated.ID, "version", updated.Version)
+	return updated, nil
+}
+
+func (s *ArticleService) DeleteArticle(ctx context.Context, id int64) (err error) {
+	ctx, span := startSpan(ctx, "ArticleService.DeleteArticle", attribute.Int64("article.id", id))
+	defer endSpan(span, &err)
+
+	if id <= 0 {
+		return domain.ErrInvalidID
+	}
+
+	if _, ok := auth.PrincipalFrom(ctx); ok {
+		// Ownership is only looked up for callers the policy may restrict.
+		current, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.policy.Authorize(ctx, ActionDelete, &current); err != nil {
+			return err
+		}
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "article deleted", "article_id", id)
+	return nil
+}
+
+func (s *ArticleService) RestoreArticle(ctx context.Context, id int64) (_ domain.Article, err error) {
+	ctx, span := startSpan(ctx, "ArticleService.RestoreArticle", attribute.Int64("article.id", id))
+	defer endSpan(span, &err)
+
+	if id <= 0 {
+		return domain.Article{}, domain.ErrInvalidID
+	}
+	if err := s.policy.Authorize(ctx, ActionRestore, nil); err != nil {
+		return domain.Article{}, err
+	}
+
+	restored, err := s.writeArticle(ctx, func(ctx context.Context) (domain.Article, error) {
+		return s.repo.Restore(ctx, id)
+	})
+	if err != nil {
+		return domain.Article{}, err
+	}
+
+	slog.InfoContext(ctx, "article restored", "article_id", id)
+	return restored, nil
+}
+
+// PurgeDeletedArticles permanently removes articles that have been soft
+// deleted for longer than retention. Only admins may purge, and ctx must
+// carry the principal acting.
+func (s *ArticleService) PurgeDeletedArticles(ctx context.Context, retention time.Duration) (_ int64, err error) {
+	ctx, span := startSpan(ctx, "ArticleService.PurgeDeletedArticles")
+	defer endSpan(span, &err)
+
+	if retention <= 0 {
+		return 0, domain.ErrInvalidRetention
+	}
+	if err := s.policy.Authorize(ctx, ActionPurge, nil); err != nil {
+		return 0, err
+	}
+
+	deletedBefore := time.Now().Add(-retention)
+	purged, err := s.repo.PurgeDeleted(ctx, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+
+	slog.InfoContext(ctx, "purged deleted articles", "count", purged, "deleted_before", deletedBefore)
+	return purged, nil
 }
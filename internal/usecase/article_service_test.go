@@ -3,26 +3,78 @@ package usecase
 import (
 	"context"
 	"errors"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
+	"articles/internal/auth"
 	"articles/internal/domain"
 )
 
 type stubArticleRepo struct {
-	saveFn    func(ctx context.Context, article domain.Article) (domain.Article, error)
-	getByIDFn func(ctx context.Context, id int64) (domain.Article, error)
+	saveFn      func(ctx context.Context, article domain.Article) (domain.Article, error)
+	saveManyFn  func(ctx context.Context, articles []domain.Article) ([]domain.Article, error)
+	getByIDFn   func(ctx context.Context, id int64) (domain.Article, error)
+	getByIDsFn  func(ctx context.Context, ids []int64) ([]domain.Article, error)
+	getBySlugFn func(ctx context.Context, slug string) (domain.Article, error)
+	listFn      func(ctx context.Context, query domain.ListArticlesQuery) ([]domain.Article, error)
+	listDueFn   func(ctx context.Context, due time.Time, limit int) ([]domain.Article, error)
+	updateFn    func(ctx context.Context, article domain.Article) (domain.Article, error)
+	deleteFn    func(ctx context.Context, id int64) error
+	restoreFn   func(ctx context.Context, id int64) (domain.Article, error)
+	purgeFn     func(ctx context.Context, deletedBefore time.Time) (int64, error)
+	listTagsFn  func(ctx context.Context, status domain.ArticleStatus) ([]domain.TagCount, error)
 }
 
 func (s *stubArticleRepo) Save(ctx context.Context, article domain.Article) (domain.Article, error) {
 	return s.saveFn(ctx, article)
 }
 
+func (s *stubArticleRepo) SaveMany(ctx context.Context, articles []domain.Article) ([]domain.Article, error) {
+	return s.saveManyFn(ctx, articles)
+}
+
 func (s *stubArticleRepo) GetByID(ctx context.Context, id int64) (domain.Article, error) {
 	return s.getByIDFn(ctx, id)
 }
 
+func (s *stubArticleRepo) GetByIDs(ctx context.Context, ids []int64) ([]domain.Article, error) {
+	return s.getByIDsFn(ctx, ids)
+}
+
+func (s *stubArticleRepo) GetBySlug(ctx context.Context, slug string) (domain.Article, error) {
+	return s.getBySlugFn(ctx, slug)
+}
+
+func (s *stubArticleRepo) List(ctx context.Context, query domain.ListArticlesQuery) ([]domain.Article, error) {
+	return s.listFn(ctx, query)
+}
+
+func (s *stubArticleRepo) ListScheduled(ctx context.Context, due time.Time, limit int) ([]domain.Article, error) {
+	return s.listDueFn(ctx, due, limit)
+}
+
+func (s *stubArticleRepo) Update(ctx context.Context, article domain.Article) (domain.Article, error) {
+	return s.updateFn(ctx, article)
+}
+
+func (s *stubArticleRepo) Delete(ctx context.Context, id int64) error {
+	return s.deleteFn(ctx, id)
+}
+
+func (s *stubArticleRepo) Restore(ctx context.Context, id int64) (domain.Article, error) {
+	return s.restoreFn(ctx, id)
+}
+
+func (s *stubArticleRepo) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	return s.purgeFn(ctx, deletedBefore)
+}
+
+func (s *stubArticleRepo) ListTags(ctx context.Context, status domain.ArticleStatus) ([]domain.TagCount, error) {
+	return s.listTagsFn(ctx, status)
+}
+
 func TestArticleService_CreateArticle_Success(t *testing.T) {
 	want := domain.Article{ID: 1, Title: "Hello", CreatedAt: time.Unix(0, 0)}
 	repo := &stubArticleRepo{
@@ -36,11 +88,11 @@ func TestArticleService_CreateArticle_Success(t *testing.T) {
 	}
 	svc := NewArticleService(repo)
 
-	got, err := svc.CreateArticle(context.Background(), "  Hello  ")
+	got, err := svc.CreateArticle(context.Background(), domain.ArticleInput{Title: "  Hello  "})
 	if err != nil {
 		t.Fatalf("CreateArticle returned error: %v", err)
 	}
-	if got != want {
+	if !reflect.DeepEqual(got, want) {
 		t.Fatalf("expected %+v, got %+v", want, got)
 	}
 }
@@ -48,7 +100,7 @@ func TestArticleService_CreateArticle_Success(t *testing.T) {
 func TestArticleService_CreateArticle_InvalidTitle(t *testing.T) {
 	svc := NewArticleService(&stubArticleRepo{})
 
-	_, err := svc.CreateArticle(context.Background(), "   ")
+	_, err := svc.CreateArticle(context.Background(), domain.ArticleInput{Title: "   "})
 	if !errors.Is(err, domain.ErrInvalidTitle) {
 		t.Fatalf("expected ErrInvalidTitle, got %v", err)
 	}
@@ -57,7 +109,7 @@ func TestArticleService_CreateArticle_InvalidTitle(t *testing.T) {
 func TestArticleService_CreateArticle_TitleTooLong(t *testing.T) {
 	svc := NewArticleService(&stubArticleRepo{})
 
-	_, err := svc.CreateArticle(context.Background(), strings.Repeat("a", domain.MaxTitleLength+1))
+	_, err := svc.CreateArticle(context.Background(), domain.ArticleInput{Title: strings.Repeat("a", domain.MaxTitleLength+1)})
 	if !errors.Is(err, domain.ErrTitleTooLong) {
 		t.Fatalf("expected ErrTitleTooLong, got %v", err)
 	}
@@ -73,14 +125,40 @@ func TestArticleService_CreateArticle_RepoError(t *testing.T) {
 	}
 	svc := NewArticleService(repo)
 
-	_, err := svc.CreateArticle(context.Background(), "Hello")
+	_, err := svc.CreateArticle(context.Background(), domain.ArticleInput{Title: "Hello"})
 	if !errors.Is(err, repoErr) {
 		t.Fatalf("expected repo error to propagate, got %v", err)
 	}
 }
 
+func TestArticleService_CreateArticle_AuthorFromContext(t *testing.T) {
+	repo := &stubArticleRepo{
+		saveFn: func(_ context.Context, article domain.Article) (domain.Article, error) {
+			return article, nil
+		},
+	}
+	svc := NewArticleService(repo)
+
+	ctx := WithAuthor(context.Background(), "user-1")
+	got, err := svc.CreateArticle(ctx, domain.ArticleInput{Title: "Hello", AuthorID: "someone-else"})
+	if err != nil {
+		t.Fatalf("CreateArticle returned error: %v", err)
+	}
+	if got.AuthorID != "user-1" {
+		t.Fatalf("expected author %q, got %q", "user-1", got.AuthorID)
+	}
+
+	got, err = svc.CreateArticle(context.Background(), domain.ArticleInput{Title: "Hello", AuthorID: "someone-else"})
+	if err != nil {
+		t.Fatalf("CreateArticle returned error: %v", err)
+	}
+	if got.AuthorID != "someone-else" {
+		t.Fatalf("expected author %q without one in the context, got %q", "someone-else", got.AuthorID)
+	}
+}
+
 func TestArticleService_GetArticle_Success(t *testing.T) {
-	want := domain.Article{ID: 42, Title: "Hello", CreatedAt: time.Unix(0, 0)}
+	want := domain.Article{ID: 42, Title: "Hello", Status: domain.StatusPublished, CreatedAt: time.Unix(0, 0)}
 	repo := &stubArticleRepo{
 		saveFn: nil,
 		getByIDFn: func(_ context.Context, id int64) (domain.Article, error) {
@@ -96,7 +174,7 @@ func TestArticleService_GetArticle_Success(t *testing.T) {
 	if err != nil {
 		t.Fatalf("GetArticle returned error: %v", err)
 	}
-	if got != want {
+	if !reflect.DeepEqual(got, want) {
 		t.Fatalf("expected %+v, got %+v", want, got)
 	}
 }
@@ -125,3 +203,273 @@ func TestArticleService_GetArticle_RepoError(t *testing.T) {
 		t.Fatalf("expected repo error to propagate, got %v", err)
 	}
 }
+
+func TestArticleService_ListArticles_FirstPage(t *testing.T) {
+	articles := []domain.Article{
+		{ID: 3, Title: "Third", CreatedAt: time.Unix(30, 0)},
+		{ID: 2, Title: "Second", CreatedAt: time.Unix(20, 0)},
+		{ID: 1, Title: "First", CreatedAt: time.Unix(10, 0)},
+	}
+	repo := &stubArticleRepo{
+		listFn: func(_ context.Context, query domain.ListArticlesQuery) ([]domain.Article, error) {
+			if query.Limit != 3 {
+				t.Fatalf("expected repo limit 3, got %d", query.Limit)
+			}
+			if query.After != nil {
+				t.Fatalf("expected no cursor, got %+v", query.After)
+			}
+			return articles, nil
+		},
+	}
+	svc := NewArticleService(repo)
+
+	page, err := svc.ListArticles(context.Background(), 2, "", domain.TagFilter{})
+	if err != nil {
+		t.Fatalf("ListArticles returned error: %v", err)
+	}
+	if len(page.Items) != 2 || page.Items[0].ID != 3 || page.Items[1].ID != 2 {
+		t.Fatalf("unexpected items: %+v", page.Items)
+	}
+	if page.NextCursor == nil || page.NextCursor.ID != 2 || !page.NextCursor.CreatedAt.Equal(time.Unix(20, 0)) {
+		t.Fatalf("unexpected next cursor: %+v", page.NextCursor)
+	}
+}
+
+func TestArticleService_ListArticles_LastPage(t *testing.T) {
+	cursor := domain.ArticleCursor{CreatedAt: time.Unix(20, 0), ID: 2}
+	repo := &stubArticleRepo{
+		listFn: func(_ context.Context, query domain.ListArticlesQuery) ([]domain.Article, error) {
+			if query.Limit != domain.DefaultPageSize+1 {
+				t.Fatalf("expected repo limit %d, got %d", domain.DefaultPageSize+1, query.Limit)
+			}
+			if query.After == nil || query.After.ID != cursor.ID || !query.After.CreatedAt.Equal(cursor.CreatedAt) {
+				t.Fatalf("expected cursor %+v, got %+v", cursor, query.After)
+			}
+			return []domain.Article{{ID: 1, Title: "First", CreatedAt: time.Unix(10, 0)}}, nil
+		},
+	}
+	svc := NewArticleService(repo)
+
+	page, err := svc.ListArticles(context.Background(), 0, cursor.Encode(), domain.TagFilter{})
+	if err != nil {
+		t.Fatalf("ListArticles returned error: %v", err)
+	}
+	if len(page.Items) != 1 {
+		t.Fatalf("expected 1 item, got %d", len(page.Items))
+	}
+	if page.NextCursor != nil {
+		t.Fatalf("expected no next cursor, got %+v", page.NextCursor)
+	}
+}
+
+func TestArticleService_ListArticles_InvalidLimit(t *testing.T) {
+	svc := NewArticleService(&stubArticleRepo{})
+
+	for _, limit := range []int{-1, domain.MaxPageSize + 1} {
+		_, err := svc.ListArticles(context.Background(), limit, "", domain.TagFilter{})
+		if !errors.Is(err, domain.ErrInvalidLimit) {
+			t.Fatalf("expected ErrInvalidLimit for limit %d, got %v", limit, err)
+		}
+	}
+}
+
+func TestArticleService_ListArticles_InvalidCursor(t *testing.T) {
+	svc := NewArticleService(&stubArticleRepo{})
+
+	_, err := svc.ListArticles(context.Background(), 10, "garbage", domain.TagFilter{})
+	if !errors.Is(err, domain.ErrInvalidCursor) {
+		t.Fatalf("expected ErrInvalidCursor, got %v", err)
+	}
+}
+
+func stringPtr(v string) *string { return &v }
+
+func TestArticleService_UpdateArticle_Success(t *testing.T) {
+	current := domain.Article{ID: 7, Title: "Helo", Version: 3, CreatedAt: time.Unix(0, 0)}
+	repo := &stubArticleRepo{
+		getByIDFn: func(_ context.Context, _ int64) (domain.Article, error) {
+			return current, nil
+		},
+		updateFn: func(_ context.Context, article domain.Article) (domain.Article, error) {
+			if article.Title != "Hello" {
+				t.Fatalf("expected title to be trimmed to %q, got %q", "Hello", article.Title)
+			}
+			if article.Version != 3 {
+				t.Fatalf("expected version 3 to be passed through, got %d", article.Version)
+			}
+			article.Version++
+			return article, nil
+		},
+	}
+	svc := NewArticleService(repo)
+
+	got, err := svc.UpdateArticle(context.Background(), 7, 3, domain.ArticlePatch{Title: stringPtr("  Hello ")})
+	if err != nil {
+		t.Fatalf("UpdateArticle returned error: %v", err)
+	}
+	if got.Title != "Hello" || got.Version != 4 {
+		t.Fatalf("unexpected article: %+v", got)
+	}
+}
+
+func TestArticleService_UpdateArticle_EmptyPatchKeepsTitle(t *testing.T) {
+	current := domain.Article{ID: 7, Title: "Hello", Version: 1}
+	repo := &stubArticleRepo{
+		getByIDFn: func(_ context.Context, _ int64) (domain.Article, error) {
+			return current, nil
+		},
+		updateFn: func(_ context.Context, article domain.Article) (domain.Article, error) {
+			if article.Title != current.Title {
+				t.Fatalf("expected title %q to be kept, got %q", current.Title, article.Title)
+			}
+			return article, nil
+		},
+	}
+	svc := NewArticleService(repo)
+
+	if _, err := svc.UpdateArticle(context.Background(), 7, 0, domain.ArticlePatch{}); err != nil {
+		t.Fatalf("UpdateArticle returned error: %v", err)
+	}
+}
+
+func TestArticleService_UpdateArticle_PatchesBodyAndKeepsAuthor(t *testing.T) {
+	current := domain.Article{ID: 7, Title: "Hello", Body: "old", Summary: "Sum", AuthorID: "user-1", Version: 1}
+	repo := &stubArticleRepo{
+		getByIDFn: func(_ context.Context, _ int64) (domain.Article, error) {
+			return current, nil
+		},
+		updateFn: func(_ context.Context, article domain.Article) (domain.Article, error) {
+			if article.Body != "new" {
+				t.Fatalf("expected body %q, got %q", "new", article.Body)
+			}
+			if article.Summary != current.Summary || article.AuthorID != current.AuthorID {
+				t.Fatalf("expected summary and author to be kept, got %+v", article)
+			}
+			return article, nil
+		},
+	}
+	svc := NewArticleService(repo)
+
+	if _, err := svc.UpdateArticle(context.Background(), 7, 1, domain.ArticlePatch{Body: stringPtr("new")}); err != nil {
+		t.Fatalf("UpdateArticle returned error: %v", err)
+	}
+}
+
+func TestArticleService_UpdateArticle_VersionMismatch(t *testing.T) {
+	repo := &stubArticleRepo{
+		getByIDFn: func(_ context.Context, _ int64) (domain.Article, error) {
+			return domain.Article{ID: 7, Title: "Hello", Version: 4}, nil
+		},
+	}
+	svc := NewArticleService(repo)
+
+	_, err := svc.UpdateArticle(context.Background(), 7, 3, domain.ArticlePatch{Title: stringPtr("Hi")})
+	if !errors.Is(err, domain.ErrVersionConflict) {
+		t.Fatalf("expected ErrVersionConflict, got %v", err)
+	}
+}
+
+func TestArticleService_UpdateArticle_InvalidTitle(t *testing.T) {
+	repo := &stubArticleRepo{
+		getByIDFn: func(_ context.Context, _ int64) (domain.Article, error) {
+			return domain.Article{ID: 7, Title: "Hello", Version: 1}, nil
+		},
+	}
+	svc := NewArticleService(repo)
+
+	_, err := svc.UpdateArticle(context.Background(), 7, 1, domain.ArticlePatch{Title: stringPtr("  ")})
+	if !errors.Is(err, domain.ErrInvalidTitle) {
+		t.Fatalf("expected ErrInvalidTitle, got %v", err)
+	}
+}
+
+func TestArticleService_UpdateArticle_NotFound(t *testing.T) {
+	repo := &stubArticleRepo{
+		getByIDFn: func(_ context.Context, _ int64) (domain.Article, error) {
+			return domain.Article{}, domain.ErrArticleNotFound
+		},
+	}
+	svc := NewArticleService(repo)
+
+	_, err := svc.UpdateArticle(context.Background(), 7, 1, domain.ArticlePatch{Title: stringPtr("Hi")})
+	if !errors.Is(err, domain.ErrArticleNotFound) {
+		t.Fatalf("expected ErrArticleNotFound, got %v", err)
+	}
+}
+
+func TestArticleService_UpdateArticle_InvalidID(t *testing.T) {
+	svc := NewArticleService(&stubArticleRepo{})
+
+	_, err := svc.UpdateArticle(context.Background(), 0, 1, domain.ArticlePatch{})
+	if !errors.Is(err, domain.ErrInvalidID) {
+		t.Fatalf("expected ErrInvalidID, got %v", err)
+	}
+}
+
+func TestArticleService_DeleteArticle(t *testing.T) {
+	var deletedID int64
+	repo := &stubArticleRepo{
+		deleteFn: func(_ context.Context, id int64) error {
+			deletedID = id
+			return nil
+		},
+	}
+	svc := NewArticleService(repo)
+
+	if err := svc.DeleteArticle(context.Background(), 9); err != nil {
+		t.Fatalf("DeleteArticle returned error: %v", err)
+	}
+	if deletedID != 9 {
+		t.Fatalf("expected id 9 to be deleted, got %d", deletedID)
+	}
+}
+
+func TestArticleService_DeleteArticle_InvalidID(t *testing.T) {
+	svc := NewArticleService(&stubArticleRepo{})
+
+	if err := svc.DeleteArticle(context.Background(), -1); !errors.Is(err, domain.ErrInvalidID) {
+		t.Fatalf("expected ErrInvalidID, got %v", err)
+	}
+}
+
+func TestArticleService_RestoreArticle_NotFound(t *testing.T) {
+	repo := &stubArticleRepo{
+		restoreFn: func(_ context.Context, _ int64) (domain.Article, error) {
+			return domain.Article{}, domain.ErrArticleNotFound
+		},
+	}
+	svc := NewArticleService(repo)
+
+	_, err := svc.RestoreArticle(context.Background(), 9)
+	if !errors.Is(err, domain.ErrArticleNotFound) {
+		t.Fatalf("expected ErrArticleNotFound, got %v", err)
+	}
+}
+
+func TestArticleService_PurgeDeletedArticles(t *testing.T) {
+	repo := &stubArticleRepo{
+		purgeFn: func(_ context.Context, deletedBefore time.Time) (int64, error) {
+			if age := time.Since(deletedBefore); age < time.Hour || age > time.Hour+time.Minute {
+				t.Fatalf("expected cutoff about an hour ago, got %v", deletedBefore)
+			}
+			return 3, nil
+		},
+	}
+	svc := NewArticleService(repo)
+
+	purged, err := svc.PurgeDeletedArticles(asPrincipal("dave", auth.RoleAdmin), time.Hour)
+	if err != nil {
+		t.Fatalf("PurgeDeletedArticles returned error: %v", err)
+	}
+	if purged != 3 {
+		t.Fatalf("expected 3 purged articles, got %d", purged)
+	}
+}
+
+func TestArticleService_PurgeDeletedArticles_InvalidRetention(t *testing.T) {
+	svc := NewArticleService(&stubArticleRepo{})
+
+	if _, err := svc.PurgeDeletedArticles(context.Background(), 0); !errors.Is(err, domain.ErrInvalidRetention) {
+		t.Fatalf("expected ErrInvalidRetention, got %v", err)
+	}
+}
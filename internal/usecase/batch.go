@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"articles/internal/domain"
+)
+
+// BatchCreateArticles validates every input with the rules of CreateArticle
+// and stores the valid ones with a single SaveMany call. In atomic mode one
+// invalid input keeps the whole batch from being stored; in best-effort mode
+// the valid inputs are stored regardless. Per-item outcomes follow the order
+// of inputs.
+func (s *ArticleService) BatchCreateArticles(ctx context.Context, inputs []domain.ArticleInput, mode domain.BatchMode) (_ domain.BatchCreateResult, err error) {
+	ctx, span := startSpan(ctx, "ArticleService.BatchCreateArticles",
+		attribute.Int("batch.size", len(inputs)),
+		attribute.String("batch.mode", string(mode)),
+	)
+	defer endSpan(span, &err)
+
+	if len(inputs) == 0 || len(inputs) > domain.MaxBatchSize {
+		return domain.BatchCreateResult{}, domain.ErrInvalidBatchSize
+	}
+	if mode != domain.BatchAtomic && mode != domain.BatchBestEffort {
+		return domain.BatchCreateResult{}, domain.ErrInvalidBatchMode
+	}
+
+	if err := s.policy.Authorize(ctx, ActionCreate, nil); err != nil {
+		return domain.BatchCreateResult{}, err
+	}
+
+	result := domain.BatchCreateResult{Mode: mode, Items: make([]domain.BatchItemResult, len(inputs))}
+
+	var (
+		valid      = make([]domain.Article, 0, len(inputs))
+		validIndex = make([]int, 0, len(inputs))
+	)
+	for i, input := range inputs {
+		article, err := domain.NewArticle(attributed(ctx, input))
+		if err == nil && !domain.CanTransition("", article.Status) {
+			err = domain.ErrStatusTransition
+		}
+		if err == nil && schedules(input.PublishAt) {
+			err = s.policy.Authorize(ctx, ActionPublish, nil)
+		}
+		if err != nil {
+			result.Items[i].Err = err
+			continue
+		}
+		valid = append(valid, article)
+		validIndex = append(validIndex, i)
+	}
+
+	failed := len(inputs) - len(valid)
+	if failed > 0 && mode == domain.BatchAtomic {
+		for _, i := range validIndex {
+			result.Items[i].Err = domain.ErrBatchAborted
+		}
+		return result, nil
+	}
+
+	saved, err := s.writeArticles(ctx, func(ctx context.Context) ([]domain.Article, error) {
+		return s.repo.SaveMany(ctx, valid)
+	})
+	if err != nil {
+		return domain.BatchCreateResult{}, err
+	}
+	for j, article := range saved {
+		result.Items[validIndex[j]].Article = article
+	}
+	result.Created = len(saved)
+
+	slog.InfoContext(ctx, "articles batch created", "count", result.Created, "failed", failed, "mode", mode)
+	return result, nil
+}
+
+// GetArticles fetches the articles with the given IDs in one query. Found
+// articles come back in the order of ids, duplicates collapsed; IDs with no
+// live article the caller may read are returned as missing.
+func (s *ArticleService) GetArticles(ctx context.Context, ids []int64) (_ []domain.Article, missing []int64, err error) {
+	ctx, span := startSpan(ctx, "ArticleService.GetArticles", attribute.Int("batch.size", len(ids)))
+	defer endSpan(span, &err)
+
+	if len(ids) == 0 || len(ids) > domain.MaxBatchSize {
+		return nil, nil, domain.ErrInvalidIDs
+	}
+
+	unique := make([]int64, 0, len(ids))
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		if id <= 0 {
+			return nil, nil, domain.ErrInvalidIDs
+		}
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	found, err := s.repo.GetByIDs(ctx, unique)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byID := make(map[int64]domain.Article, len(found))
+	for _, article := range found {
+		if s.visible(ctx, article) {
+			byID[article.ID] = article
+		}
+	}
+
+	articles := make([]domain.Article, 0, len(found))
+	missing = []int64{}
+	for _, id := range unique {
+		if article, ok := byID[id]; ok {
+			articles = append(articles, article)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+
+	return articles, missing, nil
+}
@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"articles/internal/domain"
+)
+
+func saveManyEcho(calls *int) func(context.Context, []domain.Article) ([]domain.Article, error) {
+	return func(_ context.Context, articles []domain.Article) ([]domain.Article, error) {
+		*calls++
+		saved := make([]domain.Article, len(articles))
+		for i, article := range articles {
+			article.ID = int64(i + 1)
+			saved[i] = article
+		}
+		return saved, nil
+	}
+}
+
+func TestArticleService_BatchCreateArticles_AtomicAbortsOnInvalidItem(t *testing.T) {
+	calls := 0
+	svc := NewArticleService(&stubArticleRepo{saveManyFn: saveManyEcho(&calls)})
+
+	result, err := svc.BatchCreateArticles(context.Background(), []domain.ArticleInput{{Title: "One"}, {Title: " "}}, domain.BatchAtomic)
+	if err != nil {
+		t.Fatalf("BatchCreateArticles returned error: %v", err)
+	}
+
+	if calls != 0 {
+		t.Fatalf("expected nothing to be saved, SaveMany called %d times", calls)
+	}
+	if result.Created != 0 {
+		t.Fatalf("expected 0 created, got %d", result.Created)
+	}
+	if !errors.Is(result.Items[0].Err, domain.ErrBatchAborted) {
+		t.Fatalf("expected valid item to be aborted, got %v", result.Items[0].Err)
+	}
+	if !errors.Is(result.Items[1].Err, domain.ErrInvalidTitle) {
+		t.Fatalf("expected ErrInvalidTitle, got %v", result.Items[1].Err)
+	}
+}
+
+func TestArticleService_BatchCreateArticles_BestEffortSavesValidItems(t *testing.T) {
+	calls := 0
+	svc := NewArticleService(&stubArticleRepo{saveManyFn: saveManyEcho(&calls)})
+
+	result, err := svc.BatchCreateArticles(context.Background(),
+		[]domain.ArticleInput{{Title: " "}, {Title: " One "}, {Title: "Two"}}, domain.BatchBestEffort)
+	if err != nil {
+		t.Fatalf("BatchCreateArticles returned error: %v", err)
+	}
+
+	if calls != 1 || result.Created != 2 {
+		t.Fatalf("expected one SaveMany call creating 2 articles, got %d calls and %d created", calls, result.Created)
+	}
+	if !errors.Is(result.Items[0].Err, domain.ErrInvalidTitle) {
+		t.Fatalf("expected ErrInvalidTitle, got %v", result.Items[0].Err)
+	}
+	if result.Items[1].Err != nil || result.Items[1].Article.Title != "One" {
+		t.Fatalf("expected item 1 to be created as %q, got %+v", "One", result.Items[1])
+	}
+	if result.Items[2].Err != nil || result.Items[2].Article.Title != "Two" {
+		t.Fatalf("expected item 2 to be created as %q, got %+v", "Two", result.Items[2])
+	}
+}
+
+func TestArticleService_BatchCreateArticles_RejectsBadRequest(t *testing.T) {
+	svc := NewArticleService(&stubArticleRepo{})
+
+	if _, err := svc.BatchCreateArticles(context.Background(), nil, domain.BatchAtomic); !errors.Is(err, domain.ErrInvalidBatchSize) {
+		t.Fatalf("expected ErrInvalidBatchSize, got %v", err)
+	}
+	tooMany := make([]domain.ArticleInput, domain.MaxBatchSize+1)
+	if _, err := svc.BatchCreateArticles(context.Background(), tooMany, domain.BatchAtomic); !errors.Is(err, domain.ErrInvalidBatchSize) {
+		t.Fatalf("expected ErrInvalidBatchSize, got %v", err)
+	}
+	if _, err := svc.BatchCreateArticles(context.Background(), []domain.ArticleInput{{Title: "One"}}, "some"); !errors.Is(err, domain.ErrInvalidBatchMode) {
+		t.Fatalf("expected ErrInvalidBatchMode, got %v", err)
+	}
+}
+
+func TestArticleService_GetArticles_KeepsRequestOrder(t *testing.T) {
+	repo := &stubArticleRepo{
+		getByIDsFn: func(_ context.Context, ids []int64) ([]domain.Article, error) {
+			if len(ids) != 3 {
+				t.Fatalf("expected duplicates to be collapsed, got %v", ids)
+			}
+			return []domain.Article{{ID: 1, Title: "One", Status: domain.StatusPublished}, {ID: 3, Title: "Three", Status: domain.StatusPublished}}, nil
+		},
+	}
+	svc := NewArticleService(repo)
+
+	articles, missing, err := svc.GetArticles(context.Background(), []int64{3, 2, 1, 3})
+	if err != nil {
+		t.Fatalf("GetArticles returned error: %v", err)
+	}
+
+	if len(articles) != 2 || articles[0].ID != 3 || articles[1].ID != 1 {
+		t.Fatalf("expected articles 3 and 1 in request order, got %+v", articles)
+	}
+	if len(missing) != 1 || missing[0] != 2 {
+		t.Fatalf("expected 2 to be missing, got %v", missing)
+	}
+}
+
+func TestArticleService_GetArticles_RejectsInvalidIDs(t *testing.T) {
+	svc := NewArticleService(&stubArticleRepo{})
+
+	for _, ids := range [][]int64{nil, {1, 0}, make([]int64, domain.MaxBatchSize+1)} {
+		if _, _, err := svc.GetArticles(context.Background(), ids); !errors.Is(err, domain.ErrInvalidIDs) {
+			t.Fatalf("expected ErrInvalidIDs for %d ids, got %v", len(ids), err)
+		}
+	}
+}
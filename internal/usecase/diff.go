@@ -0,0 +1,267 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"articles/internal/domain"
+)
+
+// unifiedContext is the number of unchanged lines shown around a change.
+const unifiedContext = 3
+
+// maxDiffEdits bounds the work of diffTokens. Texts further apart than that
+// are reported as entirely replaced, which is still a correct diff.
+const maxDiffEdits = 1000
+
+// edit is one token of an edit script.
+type edit struct {
+	op   domain.DiffOp
+	text string
+}
+
+// unifiedDiff compares two texts line by line in the format of diff -u,
+// labelling the sides field@from and field@to.
+func unifiedDiff(field string, from, to int64, older, newer string) string {
+	edits := diffTokens(splitLines(older), splitLines(newer))
+
+	// Line numbers on either side before each edit.
+	olderLine := make([]int, len(edits)+1)
+	newerLine := make([]int, len(edits)+1)
+	for i, e := range edits {
+		olderLine[i+1], newerLine[i+1] = olderLine[i], newerLine[i]
+		if e.op != domain.DiffInsert {
+			olderLine[i+1]++
+		}
+		if e.op != domain.DiffDelete {
+			newerLine[i+1]++
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s@%d\n+++ %s@%d\n", field, from, field, to)
+	for i := 0; ; {
+		first := nextChange(edits, i)
+		if first < 0 {
+			break
+		}
+		// Changes closer than twice the context share a hunk.
+		last := first
+		for {
+			next := nextChange(edits, last+1)
+			if next < 0 || next-last-1 > 2*unifiedContext {
+				break
+			}
+			last = next
+		}
+		start := max(first-unifiedContext, i)
+		end := min(last+unifiedContext+1, len(edits))
+
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n",
+			hunkRange(olderLine[start], olderLine[end]-olderLine[start]),
+			hunkRange(newerLine[start], newerLine[end]-newerLine[start]))
+		for _, e := range edits[start:end] {
+			switch e.op {
+			case domain.DiffEqual:
+				b.WriteByte(' ')
+			case domain.DiffDelete:
+				b.WriteByte('-')
+			case domain.DiffInsert:
+				b.WriteByte('+')
+			}
+			b.WriteString(e.text)
+			b.WriteByte('\n')
+		}
+		i = end
+	}
+
+	return b.String()
+}
+
+// hunkRange formats the lines of one side of a hunk starting after line
+// before; diff -u omits a count of 1 and numbers an empty range by the line
+// before it.
+func hunkRange(before, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", before)
+	case 1:
+		return fmt.Sprintf("%d", before+1)
+	default:
+		return fmt.Sprintf("%d,%d", before+1, count)
+	}
+}
+
+func nextChange(edits []edit, from int) int {
+	for i := from; i < len(edits); i++ {
+		if edits[i].op != domain.DiffEqual {
+			return i
+		}
+	}
+	return -1
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// wordDiff compares two texts word by word. Runs of whitespace and single
+// punctuation characters count as words, so the spans joined make up the
+// texts again.
+func wordDiff(older, newer string) []domain.DiffSpan {
+	var spans []domain.DiffSpan
+	for _, e := range diffTokens(splitWords(older), splitWords(newer)) {
+		if n := len(spans); n > 0 && spans[n-1].Op == e.op {
+			spans[n-1].Text += e.text
+			continue
+		}
+		spans = append(spans, domain.DiffSpan{Op: e.op, Text: e.text})
+	}
+	return spans
+}
+
+func splitWords(text string) []string {
+	var words []string
+	class := func(r rune) int {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r):
+			return 1
+		case unicode.IsSpace(r):
+			return 2
+		default:
+			return 0
+		}
+	}
+
+	start, prev := 0, -1
+	for i, r := range text {
+		c := class(r)
+		if i > start && (c != prev || c == 0) {
+			words = append(words, text[start:i])
+			start = i
+		}
+		prev = c
+	}
+	if start < len(text) {
+		words = append(words, text[start:])
+	}
+	return words
+}
+
+// diffTokens returns a shortest edit script turning a into b, deletions
+// before insertions where they meet.
+func diffTokens(a, b []string) []edit {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	edits := make([]edit, 0, len(a)+len(b)-prefix-suffix)
+	for _, token := range a[:prefix] {
+		edits = append(edits, edit{domain.DiffEqual, token})
+	}
+	edits = append(edits, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, token := range a[len(a)-suffix:] {
+		edits = append(edits, edit{domain.DiffEqual, token})
+	}
+	return edits
+}
+
+// myers is the O(ND) algorithm of Eugene Myers, "An O(ND) Difference
+// Algorithm and Its Variations" (1986). trace keeps the furthest reaching
+// x of each diagonal after every round to walk the path back.
+func myers(a, b []string) []edit {
+	n, m := len(a), len(b)
+	limit := min(n+m, maxDiffEdits)
+
+	// v[limit+k] is the furthest x reached on diagonal k = x - y.
+	v := make([]int, 2*limit+2)
+	var trace [][]int
+	for d := 0; d <= limit; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || k != d && v[limit+k-1] < v[limit+k+1] {
+				x = v[limit+k+1]
+			} else {
+				x = v[limit+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[limit+k] = x
+
+			if x >= n && y >= m {
+				trace = append(trace, append([]int(nil), v[limit-d:limit+d+1]...))
+				return backtrack(a, b, trace)
+			}
+		}
+		trace = append(trace, append([]int(nil), v[limit-d:limit+d+1]...))
+	}
+
+	return replaceAll(a, b)
+}
+
+// backtrack follows trace from the end of a and b to their start. trace[d]
+// holds diagonals -d to d.
+func backtrack(a, b []string, trace [][]int) []edit {
+	var reversed []edit
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d > 0; d-- {
+		prev := trace[d-1]
+		at := func(k int) int { return prev[k+d-1] }
+
+		k := x - y
+		var prevK int
+		if k == -d || k != d && at(k-1) < at(k+1) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			reversed = append(reversed, edit{domain.DiffEqual, a[x-1]})
+			x--
+			y--
+		}
+		if prevK == k+1 {
+			reversed = append(reversed, edit{domain.DiffInsert, b[y-1]})
+		} else {
+			reversed = append(reversed, edit{domain.DiffDelete, a[x-1]})
+		}
+		x, y = prevX, prevY
+	}
+	for x > 0 && y > 0 {
+		reversed = append(reversed, edit{domain.DiffEqual, a[x-1]})
+		x--
+		y--
+	}
+
+	edits := make([]edit, 0, len(reversed))
+	for i := len(reversed) - 1; i >= 0; i-- {
+		edits = append(edits, reversed[i])
+	}
+	return edits
+}
+
+func replaceAll(a, b []string) []edit {
+	edits := make([]edit, 0, len(a)+len(b))
+	for _, token := range a {
+		edits = append(edits, edit{domain.DiffDelete, token})
+	}
+	for _, token := range b {
+		edits = append(edits, edit{domain.DiffInsert, token})
+	}
+	return edits
+}
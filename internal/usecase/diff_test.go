@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"articles/internal/domain"
+)
+
+func TestUnifiedDiff_SplitsDistantChanges(t *testing.T) {
+	older := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm"
+	newer := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\nn"
+
+	got := unifiedDiff("body", 1, 2, older, newer)
+	want := strings.Join([]string{
+		"--- body@1",
+		"+++ body@2",
+		"@@ -1,5 +1,5 @@",
+		" a", "-b", "+B", " c", " d", " e",
+		"@@ -11,3 +11,4 @@",
+		" k", " l", " m", "+n",
+		"",
+	}, "\n")
+	if got != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, got)
+	}
+}
+
+func TestWordDiff(t *testing.T) {
+	got := wordDiff("The quick brown fox.", "The slow brown dog!")
+	want := []domain.DiffSpan{
+		{Op: domain.DiffEqual, Text: "The "},
+		{Op: domain.DiffDelete, Text: "quick"},
+		{Op: domain.DiffInsert, Text: "slow"},
+		{Op: domain.DiffEqual, Text: " brown "},
+		{Op: domain.DiffDelete, Text: "fox."},
+		{Op: domain.DiffInsert, Text: "dog!"},
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestDiffTokens_FallsBackToReplacingEverything(t *testing.T) {
+	a := make([]string, maxDiffEdits)
+	b := make([]string, maxDiffEdits)
+	for i := range a {
+		a[i], b[i] = "a", "b"
+	}
+
+	edits := diffTokens(a, b)
+	if len(edits) != 2*maxDiffEdits || edits[0].op != domain.DiffDelete || edits[len(edits)-1].op != domain.DiffInsert {
+		t.Fatalf("expected every token to be replaced, got %d edits", len(edits))
+	}
+}
@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+
+	"articles/internal/auth"
+	"articles/internal/domain"
+)
+
+// Action is something ArticleService does on behalf of a caller.
+type Action string
+
+const (
+	ActionCreate  Action = "create"
+	ActionImport  Action = "import"
+	ActionUpdate  Action = "update"
+	ActionDelete  Action = "delete"
+	ActionRestore Action = "restore"
+	ActionPurge   Action = "purge"
+	// ActionPublish publishes an article or schedules it to be published,
+	// on top of the create or update it comes with.
+	ActionPublish Action = "publish"
+	// ActionReadUnpublished reads articles that are not published. Everyone
+	// reads published articles.
+	ActionReadUnpublished Action = "read_unpublished"
+)
+
+// Grant lists the roles allowed an action: Any on every article, Own only on
+// articles whose AuthorID is the caller's. Actions that do not target an
+// existing article only look at Any.
+type Grant struct {
+	Any []auth.Role
+	Own []auth.Role
+}
+
+// Policy says who may take each action. Actions it does not list are denied
+// to every role.
+type Policy map[Action]Grant
+
+// DefaultPolicy lets authors write and read their own articles and editors
+// every article, but only editors publish: an author takes an article as far
+// as in_review. Import keeps each record's author_id, so it is left to
+// editors; hard deletes are left to admins.
+var DefaultPolicy = Policy{
+	ActionCreate: {Any: []auth.Role{auth.RoleAuthor, auth.RoleEditor, auth.RoleAdmin}},
+	ActionImport: {Any: []auth.Role{auth.RoleEditor, auth.RoleAdmin}},
+	ActionUpdate: {
+		Any: []auth.Role{auth.RoleEditor, auth.RoleAdmin},
+		Own: []auth.Role{auth.RoleAuthor},
+	},
+	ActionDelete: {
+		Any: []auth.Role{auth.RoleEditor, auth.RoleAdmin},
+		Own: []auth.Role{auth.RoleAuthor},
+	},
+	ActionRestore: {Any: []auth.Role{auth.RoleEditor, auth.RoleAdmin}},
+	ActionPublish: {Any: []auth.Role{auth.RoleEditor, auth.RoleAdmin}},
+	ActionPurge:   {Any: []auth.Role{auth.RoleAdmin}},
+	ActionReadUnpublished: {
+		Any: []auth.Role{auth.RoleEditor, auth.RoleAdmin},
+		Own: []auth.Role{auth.RoleAuthor},
+	},
+}
+
+// explicitActions are never taken on behalf of a context without a
+// principal: their callers must say who they act as. Anonymous readers are
+// the ones without a principal once authentication is on; servers with it
+// off mark their callers with WithTrusted instead.
+var explicitActions = []Action{ActionPurge, ActionReadUnpublished}
+
+// Authorize returns domain.ErrForbidden unless the caller in ctx may take
+// action, on article when it is not nil. Contexts without a principal are
+// trusted with every action but explicitActions: they come from the
+// scheduler or a server with authentication off, whose routes already keep
+// anonymous callers from writing when it is on.
+func (p Policy) Authorize(ctx context.Context, action Action, article *domain.Article) error {
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok {
+		if slices.Contains(explicitActions, action) {
+			return domain.ErrForbidden
+		}
+		return nil
+	}
+
+	grant := p[action]
+	for _, role := range principal.Roles {
+		if slices.Contains(grant.Any, role) {
+			return nil
+		}
+		if article != nil && slices.Contains(grant.Own, role) && owns(principal, *article) {
+			return nil
+		}
+	}
+	return domain.ErrForbidden
+}
+
+func owns(principal auth.Principal, article domain.Article) bool {
+	return principal.AuthorID != "" && article.AuthorID == principal.AuthorID
+}
@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"articles/internal/auth"
+	"articles/internal/domain"
+)
+
+func asPrincipal(authorID string, roles ...auth.Role) context.Context {
+	return auth.WithPrincipal(context.Background(), auth.Principal{Subject: authorID, AuthorID: authorID, Roles: roles})
+}
+
+func TestPolicy_Authorize(t *testing.T) {
+	own := &domain.Article{ID: 1, AuthorID: "alice"}
+	other := &domain.Article{ID: 2, AuthorID: "bob"}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		action  Action
+		article *domain.Article
+		allowed bool
+	}{
+		{"no principal is trusted", context.Background(), ActionPublish, nil, true},
+		{"reader cannot create", asPrincipal("alice", auth.RoleReader), ActionCreate, nil, false},
+		{"author creates", asPrincipal("alice", auth.RoleAuthor), ActionCreate, nil, true},
+		{"author updates own", asPrincipal("alice", auth.RoleAuthor), ActionUpdate, own, true},
+		{"author cannot update other", asPrincipal("alice", auth.RoleAuthor), ActionUpdate, other, false},
+		{"author cannot delete other", asPrincipal("alice", auth.RoleAuthor), ActionDelete, other, false},
+		{"author cannot import", asPrincipal("alice", auth.RoleAuthor), ActionImport, nil, false},
+		{"editor updates other", asPrincipal("alice", auth.RoleEditor), ActionUpdate, other, true},
+		{"author cannot publish own", asPrincipal("alice", auth.RoleAuthor), ActionPublish, own, false},
+		{"editor publishes", asPrincipal("alice", auth.RoleEditor), ActionPublish, other, true},
+		{"admin publishes", asPrincipal("alice", auth.RoleAdmin), ActionPublish, nil, true},
+		{"no principal cannot purge", context.Background(), ActionPurge, nil, false},
+		{"editor cannot purge", asPrincipal("alice", auth.RoleEditor), ActionPurge, nil, false},
+		{"admin purges", asPrincipal("alice", auth.RoleAdmin), ActionPurge, nil, true},
+		{"no principal cannot read unpublished", context.Background(), ActionReadUnpublished, own, false},
+		{"reader cannot read unpublished", asPrincipal("alice", auth.RoleReader), ActionReadUnpublished, own, false},
+		{"author reads own unpublished", asPrincipal("alice", auth.RoleAuthor), ActionReadUnpublished, own, true},
+		{"author cannot read other unpublished", asPrincipal("alice", auth.RoleAuthor), ActionReadUnpublished, other, false},
+		{"editor reads unpublished", asPrincipal("alice", auth.RoleEditor), ActionReadUnpublished, other, true},
+		{"no roles", asPrincipal("alice"), ActionCreate, nil, false},
+		{"author without id owns nothing", asPrincipal("", auth.RoleAuthor), ActionUpdate, &domain.Article{ID: 3}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := DefaultPolicy.Authorize(tt.ctx, tt.action, tt.article)
+			if tt.allowed && err != nil {
+				t.Fatalf("expected %s to be allowed, got %v", tt.action, err)
+			}
+			if !tt.allowed && !errors.Is(err, domain.ErrForbidden) {
+				t.Fatalf("expected ErrForbidden for %s, got %v", tt.action, err)
+			}
+		})
+	}
+}
+
+func TestArticleService_UpdateArticle_Forbidden(t *testing.T) {
+	updated := false
+	repo := &stubArticleRepo{
+		getByIDFn: func(_ context.Context, id int64) (domain.Article, error) {
+			return domain.Article{ID: id, Title: "Hello", AuthorID: "bob", Status: domain.StatusDraft, Version: 1}, nil
+		},
+		updateFn: func(_ context.Context, article domain.Article) (domain.Article, error) {
+			updated = true
+			return article, nil
+		},
+	}
+	svc := NewArticleService(repo)
+	title := "Changed"
+
+	_, err := svc.UpdateArticle(asPrincipal("alice", auth.RoleAuthor), 1, 0, domain.ArticlePatch{Title: &title})
+	if !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+	if updated {
+		t.Fatal("expected the article not to be updated")
+	}
+
+	if _, err := svc.UpdateArticle(asPrincipal("bob", auth.RoleAuthor), 1, 0, domain.ArticlePatch{Title: &title}); err != nil {
+		t.Fatalf("expected the author to update their own article, got %v", err)
+	}
+}
+
+func TestArticleService_DeleteArticle_ChecksOwnership(t *testing.T) {
+	deleted := false
+	repo := &stubArticleRepo{
+		getByIDFn: func(_ context.Context, id int64) (domain.Article, error) {
+			return domain.Article{ID: id, AuthorID: "bob"}, nil
+		},
+		deleteFn: func(context.Context, int64) error {
+			deleted = true
+			return nil
+		},
+	}
+	svc := NewArticleService(repo)
+
+	if err := svc.DeleteArticle(asPrincipal("alice", auth.RoleAuthor), 1); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+	if deleted {
+		t.Fatal("expected the article not to be deleted")
+	}
+	if err := svc.DeleteArticle(asPrincipal("carol", auth.RoleEditor), 1); err != nil || !deleted {
+		t.Fatalf("expected an editor to delete the article, got %v", err)
+	}
+}
+
+func TestArticleService_OnlyEditorsPublish(t *testing.T) {
+	updated := false
+	repo := &stubArticleRepo{
+		getByIDFn: func(_ context.Context, id int64) (domain.Article, error) {
+			return domain.Article{ID: id, Title: "Hello", AuthorID: "bob", Status: domain.StatusInReview, Version: 1}, nil
+		},
+		updateFn: func(_ context.Context, article domain.Article) (domain.Article, error) {
+			updated = true
+			return article, nil
+		},
+	}
+	svc := NewArticleService(repo)
+	author := asPrincipal("bob", auth.RoleAuthor)
+	published := domain.StatusPublished
+	tomorrow := time.Now().Add(24 * time.Hour)
+
+	if _, err := svc.UpdateArticle(author, 1, 0, domain.ArticlePatch{Status: &published}); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for an author publishing their own article, got %v", err)
+	}
+	if _, err := svc.UpdateArticle(author, 1, 0, domain.ArticlePatch{PublishAt: &tomorrow}); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for an author scheduling their own article, got %v", err)
+	}
+	if updated {
+		t.Fatal("expected the article not to be updated")
+	}
+	unscheduled := time.Time{}
+	if _, err := svc.UpdateArticle(author, 1, 0, domain.ArticlePatch{PublishAt: &unscheduled}); err != nil {
+		t.Fatalf("expected the author to clear the schedule, got %v", err)
+	}
+
+	got, err := svc.UpdateArticle(asPrincipal("carol", auth.RoleEditor), 1, 0, domain.ArticlePatch{Status: &published})
+	if err != nil {
+		t.Fatalf("expected an editor to publish, got %v", err)
+	}
+	if got.Status != domain.StatusPublished {
+		t.Fatalf("expected status published, got %q", got.Status)
+	}
+
+	input := domain.ArticleInput{Title: "Hello", Status: domain.StatusInReview, PublishAt: &tomorrow}
+	if _, err := svc.CreateArticle(author, input); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for an author creating a scheduled article, got %v", err)
+	}
+}
+
+func TestArticleService_PurgeNeedsAdmin(t *testing.T) {
+	purged := false
+	repo := &stubArticleRepo{
+		purgeFn: func(context.Context, time.Time) (int64, error) {
+			purged = true
+			return 2, nil
+		},
+	}
+	svc := NewArticleService(repo)
+
+	for _, ctx := range []context.Context{
+		context.Background(),
+		asPrincipal("bob", auth.RoleAuthor),
+		asPrincipal("carol", auth.RoleEditor),
+	} {
+		if _, err := svc.PurgeDeletedArticles(ctx, time.Hour); !errors.Is(err, domain.ErrForbidden) {
+			t.Fatalf("expected ErrForbidden, got %v", err)
+		}
+	}
+	if purged {
+		t.Fatal("expected nothing to be purged")
+	}
+	if n, err := svc.PurgeDeletedArticles(asPrincipal("dave", auth.RoleAdmin), time.Hour); err != nil || n != 2 {
+		t.Fatalf("expected an admin to purge 2 articles, got %d, %v", n, err)
+	}
+}
+
+func TestArticleService_WithPolicy(t *testing.T) {
+	svc := NewArticleService(&stubArticleRepo{}, WithPolicy(Policy{}))
+
+	_, err := svc.CreateArticle(asPrincipal("dave", auth.RoleAdmin), domain.ArticleInput{Title: "Hello"})
+	if !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("expected an empty policy to deny everything, got %v", err)
+	}
+}
@@ -0,0 +1,249 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"articles/internal/domain"
+)
+
+// writeArticle is writeArticles for a change to a single article.
+func (s *ArticleService) writeArticle(ctx context.Context, write func(context.Context) (domain.Article, error)) (domain.Article, error) {
+	written, err := s.writeArticles(ctx, func(ctx context.Context) ([]domain.Article, error) {
+		article, err := write(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return []domain.Article{article}, nil
+	})
+	if err != nil {
+		return domain.Article{}, err
+	}
+	return written[0], nil
+}
+
+// writeArticles runs write and records a revision of every article it
+// returns in the same unit of work (see WithTransactor): when either fails,
+// neither is kept.
+func (s *ArticleService) writeArticles(ctx context.Context, write func(context.Context) ([]domain.Article, error)) ([]domain.Article, error) {
+	var written []domain.Article
+	err := s.inTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if written, err = write(ctx); err != nil {
+			return err
+		}
+		return s.recordRevisions(ctx, written...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return written, nil
+}
+
+func (s *ArticleService) inTransaction(ctx context.Context, fn func(context.Context) error) error {
+	if s.tx == nil {
+		return fn(ctx)
+	}
+	return s.tx.WithTx(ctx, fn)
+}
+
+// recordRevisions stores the state articles were left in by a change.
+func (s *ArticleService) recordRevisions(ctx context.Context, articles ...domain.Article) error {
+	if s.revisions == nil || len(articles) == 0 {
+		return nil
+	}
+
+	changedBy := actorFrom(ctx)
+	revisions := make([]domain.Revision, 0, len(articles))
+	for _, article := range articles {
+		createdAt := article.UpdatedAt
+		if createdAt.IsZero() {
+			createdAt = time.Now()
+		}
+		revisions = append(revisions, domain.Revision{
+			ArticleID: article.ID,
+			Number:    article.Version,
+			Title:     article.Title,
+			Slug:      article.Slug,
+			Body:      article.Body,
+			Summary:   article.Summary,
+			Tags:      article.Tags,
+			ChangedBy: changedBy,
+			CreatedAt: createdAt,
+		})
+	}
+
+	if err := s.revisions.SaveRevisions(ctx, revisions); err != nil {
+		return fmt.Errorf("record revisions: %w", err)
+	}
+	return nil
+}
+
+// ListRevisions returns the revisions of a live article the caller may
+// read, oldest first.
+func (s *ArticleService) ListRevisions(ctx context.Context, id int64) (_ []domain.Revision, err error) {
+	ctx, span := startSpan(ctx, "ArticleService.ListRevisions", attribute.Int64("article.id", id))
+	defer endSpan(span, &err)
+
+	if id <= 0 {
+		return nil, domain.ErrInvalidID
+	}
+	if _, err := s.getVisible(ctx, id); err != nil {
+		return nil, err
+	}
+	if s.revisions == nil {
+		return []domain.Revision{}, nil
+	}
+
+	return s.revisions.ListRevisions(ctx, id)
+}
+
+// GetRevision returns revision number of a live article.
+func (s *ArticleService) GetRevision(ctx context.Context, id, number int64) (_ domain.Revision, err error) {
+	ctx, span := startSpan(ctx, "ArticleService.GetRevision",
+		attribute.Int64("article.id", id),
+		attribute.Int64("revision.number", number),
+	)
+	defer endSpan(span, &err)
+
+	if id <= 0 {
+		return domain.Revision{}, domain.ErrInvalidID
+	}
+	if number <= 0 {
+		return domain.Revision{}, domain.ErrInvalidRevision
+	}
+	if _, err := s.getVisible(ctx, id); err != nil {
+		return domain.Revision{}, err
+	}
+
+	return s.getRevision(ctx, id, number)
+}
+
+// DiffRevisions compares revision from with revision to of a live article.
+// A to of 0 selects the current version and a from of 0 the revision before
+// to; the revision before the first is an empty article. Only the fields
+// that differ are listed.
+func (s *ArticleService) DiffRevisions(ctx context.Context, id, from, to int64, format domain.DiffFormat) (_ domain.RevisionDiff, err error) {
+	ctx, span := startSpan(ctx, "ArticleService.DiffRevisions",
+		attribute.Int64("article.id", id),
+		attribute.Int64("revision.from", from),
+		attribute.Int64("revision.to", to),
+	)
+	defer endSpan(span, &err)
+
+	if id <= 0 {
+		return domain.RevisionDiff{}, domain.ErrInvalidID
+	}
+	if from < 0 || to < 0 {
+		return domain.RevisionDiff{}, domain.ErrInvalidRevision
+	}
+	format, err = domain.ParseDiffFormat(string(format))
+	if err != nil {
+		return domain.RevisionDiff{}, err
+	}
+
+	current, err := s.getVisible(ctx, id)
+	if err != nil {
+		return domain.RevisionDiff{}, err
+	}
+	if to == 0 {
+		to = current.Version
+	}
+	if from == 0 {
+		from = to - 1
+	}
+
+	older := domain.Revision{ArticleID: id}
+	if from > 0 {
+		if older, err = s.getRevision(ctx, id, from); err != nil {
+			return domain.RevisionDiff{}, err
+		}
+	}
+	newer, err := s.getRevision(ctx, id, to)
+	if err != nil {
+		return domain.RevisionDiff{}, err
+	}
+
+	return diffRevisions(older, newer, format), nil
+}
+
+// RevertArticle makes the content of revision number the article's current
+// content, as a new version, if the article is still at expectedVersion. An
+// expectedVersion of 0 skips the check.
+func (s *ArticleService) RevertArticle(ctx context.Context, id, number, expectedVersion int64) (_ domain.Article, err error) {
+	ctx, span := startSpan(ctx, "ArticleService.RevertArticle",
+		attribute.Int64("article.id", id),
+		attribute.Int64("revision.number", number),
+	)
+	defer endSpan(span, &err)
+
+	revision, err := s.GetRevision(ctx, id, number)
+	if err != nil {
+		return domain.Article{}, err
+	}
+
+	tags := revision.Tags
+	reverted, err := s.UpdateArticle(ctx, id, expectedVersion, domain.ArticlePatch{
+		Title:   &revision.Title,
+		Body:    &revision.Body,
+		Summary: &revision.Summary,
+		Tags:    &tags,
+	})
+	if err != nil {
+		return domain.Article{}, err
+	}
+
+	slog.InfoContext(ctx, "article reverted", "article_id", id, "revision", number, "version", reverted.Version)
+	return reverted, nil
+}
+
+func (s *ArticleService) getRevision(ctx context.Context, id, number int64) (domain.Revision, error) {
+	if s.revisions == nil {
+		return domain.Revision{}, domain.ErrRevisionNotFound
+	}
+	return s.revisions.GetRevision(ctx, id, number)
+}
+
+func diffRevisions(older, newer domain.Revision, format domain.DiffFormat) domain.RevisionDiff {
+	diff := domain.RevisionDiff{
+		ArticleID: newer.ArticleID,
+		From:      older.Number,
+		To:        newer.Number,
+		Format:    format,
+		Fields:    []domain.FieldDiff{},
+	}
+
+	// Tags go one per line in a unified diff, as words otherwise.
+	tagSeparator := " "
+	if format == domain.DiffUnified {
+		tagSeparator = "\n"
+	}
+	fields := []struct {
+		name         string
+		older, newer string
+	}{
+		{"title", older.Title, newer.Title},
+		{"summary", older.Summary, newer.Summary},
+		{"body", older.Body, newer.Body},
+		{"tags", strings.Join(older.Tags, tagSeparator), strings.Join(newer.Tags, tagSeparator)},
+	}
+	for _, field := range fields {
+		if field.older == field.newer {
+			continue
+		}
+		fieldDiff := domain.FieldDiff{Field: field.name}
+		if format == domain.DiffUnified {
+			fieldDiff.Unified = unifiedDiff(field.name, older.Number, newer.Number, field.older, field.newer)
+		} else {
+			fieldDiff.Spans = wordDiff(field.older, field.newer)
+		}
+		diff.Fields = append(diff.Fields, fieldDiff)
+	}
+
+	return diff
+}
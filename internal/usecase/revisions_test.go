@@ -0,0 +1,251 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"articles/internal/domain"
+)
+
+type stubRevisionRepo struct {
+	saveFn func(ctx context.Context, revisions []domain.Revision) error
+	listFn func(ctx context.Context, articleID int64) ([]domain.Revision, error)
+	getFn  func(ctx context.Context, articleID, number int64) (domain.Revision, error)
+}
+
+func (s *stubRevisionRepo) SaveRevisions(ctx context.Context, revisions []domain.Revision) error {
+	return s.saveFn(ctx, revisions)
+}
+
+func (s *stubRevisionRepo) ListRevisions(ctx context.Context, articleID int64) ([]domain.Revision, error) {
+	return s.listFn(ctx, articleID)
+}
+
+func (s *stubRevisionRepo) GetRevision(ctx context.Context, articleID, number int64) (domain.Revision, error) {
+	return s.getFn(ctx, articleID, number)
+}
+
+// stubTransactor runs the unit of work in place and remembers whether it
+// would have been rolled back.
+type stubTransactor struct {
+	rolledBack bool
+}
+
+func (s *stubTransactor) WithTx(ctx context.Context, fn func(context.Context) error) error {
+	err := fn(ctx)
+	s.rolledBack = err != nil
+	return err
+}
+
+func TestArticleService_RecordsRevisions(t *testing.T) {
+	var recorded []domain.Revision
+	revisions := &stubRevisionRepo{
+		saveFn: func(_ context.Context, saved []domain.Revision) error {
+			recorded = append(recorded, saved...)
+			return nil
+		},
+	}
+	repo := &stubArticleRepo{
+		saveFn: func(_ context.Context, article domain.Article) (domain.Article, error) {
+			article.ID, article.Version = 7, 1
+			return article, nil
+		},
+		getByIDFn: func(_ context.Context, _ int64) (domain.Article, error) {
+			return domain.Article{ID: 7, Title: "Hello", Version: 1}, nil
+		},
+		updateFn: func(_ context.Context, article domain.Article) (domain.Article, error) {
+			article.Version++
+			return article, nil
+		},
+	}
+	svc := NewArticleService(repo, WithRevisions(revisions))
+	ctx := WithActor(context.Background(), "user-1")
+
+	if _, err := svc.CreateArticle(ctx, domain.ArticleInput{Title: "Hello", Tags: []string{"go"}}); err != nil {
+		t.Fatalf("CreateArticle returned error: %v", err)
+	}
+	if _, err := svc.UpdateArticle(ctx, 7, 1, domain.ArticlePatch{Body: stringPtr("Body")}); err != nil {
+		t.Fatalf("UpdateArticle returned error: %v", err)
+	}
+
+	if len(recorded) != 2 {
+		t.Fatalf("expected 2 revisions, got %+v", recorded)
+	}
+	first, second := recorded[0], recorded[1]
+	if first.ArticleID != 7 || first.Number != 1 || first.Title != "Hello" || !slices.Equal(first.Tags, []string{"go"}) ||
+		first.ChangedBy != "user-1" || first.CreatedAt.IsZero() {
+		t.Fatalf("unexpected first revision: %+v", first)
+	}
+	if second.Number != 2 || second.Body != "Body" {
+		t.Fatalf("unexpected second revision: %+v", second)
+	}
+}
+
+func TestArticleService_RevisionFailureRollsBackTheChange(t *testing.T) {
+	dbErr := errors.New("db down")
+	revisions := &stubRevisionRepo{
+		saveFn: func(context.Context, []domain.Revision) error {
+			return dbErr
+		},
+	}
+	saved := false
+	repo := &stubArticleRepo{
+		saveFn: func(_ context.Context, article domain.Article) (domain.Article, error) {
+			saved = true
+			article.ID, article.Version = 7, 1
+			return article, nil
+		},
+	}
+	tx := &stubTransactor{}
+	svc := NewArticleService(repo, WithRevisions(revisions), WithTransactor(tx))
+
+	_, err := svc.CreateArticle(context.Background(), domain.ArticleInput{Title: "Hello"})
+	if !errors.Is(err, dbErr) {
+		t.Fatalf("expected the revision error, got %v", err)
+	}
+	if !saved || !tx.rolledBack {
+		t.Fatalf("expected the saved article to be rolled back, got saved %v, rolled back %v", saved, tx.rolledBack)
+	}
+}
+
+func TestArticleService_GetRevision(t *testing.T) {
+	repo := &stubArticleRepo{
+		getByIDFn: func(_ context.Context, id int64) (domain.Article, error) {
+			if id != 7 {
+				return domain.Article{}, domain.ErrArticleNotFound
+			}
+			return domain.Article{ID: 7, Status: domain.StatusPublished, Version: 2}, nil
+		},
+	}
+	revisions := &stubRevisionRepo{
+		getFn: func(_ context.Context, articleID, number int64) (domain.Revision, error) {
+			if number != 1 {
+				return domain.Revision{}, domain.ErrRevisionNotFound
+			}
+			return domain.Revision{ArticleID: articleID, Number: number, Title: "Hello"}, nil
+		},
+	}
+	svc := NewArticleService(repo, WithRevisions(revisions))
+
+	got, err := svc.GetRevision(context.Background(), 7, 1)
+	if err != nil {
+		t.Fatalf("GetRevision returned error: %v", err)
+	}
+	if got.Title != "Hello" {
+		t.Fatalf("unexpected revision: %+v", got)
+	}
+
+	for _, tc := range []struct {
+		name       string
+		id, number int64
+		want       error
+	}{
+		{"invalid id", 0, 1, domain.ErrInvalidID},
+		{"invalid number", 7, 0, domain.ErrInvalidRevision},
+		{"unknown article", 8, 1, domain.ErrArticleNotFound},
+		{"unknown revision", 7, 5, domain.ErrRevisionNotFound},
+	} {
+		if _, err := svc.GetRevision(context.Background(), tc.id, tc.number); !errors.Is(err, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestArticleService_DiffRevisions(t *testing.T) {
+	stored := map[int64]domain.Revision{
+		1: {ArticleID: 7, Number: 1, Title: "Hello", Body: "one\ntwo", Tags: []string{"go"}},
+		2: {ArticleID: 7, Number: 2, Title: "Hello", Body: "one\n2", Tags: []string{"db", "go"}},
+	}
+	repo := &stubArticleRepo{
+		getByIDFn: func(_ context.Context, _ int64) (domain.Article, error) {
+			return domain.Article{ID: 7, Status: domain.StatusPublished, Version: 2}, nil
+		},
+	}
+	revisions := &stubRevisionRepo{
+		getFn: func(_ context.Context, _ int64, number int64) (domain.Revision, error) {
+			revision, ok := stored[number]
+			if !ok {
+				return domain.Revision{}, domain.ErrRevisionNotFound
+			}
+			return revision, nil
+		},
+	}
+	svc := NewArticleService(repo, WithRevisions(revisions))
+
+	diff, err := svc.DiffRevisions(context.Background(), 7, 0, 0, "")
+	if err != nil {
+		t.Fatalf("DiffRevisions returned error: %v", err)
+	}
+	if diff.From != 1 || diff.To != 2 || diff.Format != domain.DiffUnified {
+		t.Fatalf("unexpected diff: %+v", diff)
+	}
+	if len(diff.Fields) != 2 || diff.Fields[0].Field != "body" || diff.Fields[1].Field != "tags" {
+		t.Fatalf("expected body and tags to differ, got %+v", diff.Fields)
+	}
+	wantBody := "--- body@1\n+++ body@2\n@@ -1,2 +1,2 @@\n one\n-two\n+2\n"
+	if diff.Fields[0].Unified != wantBody {
+		t.Fatalf("expected body diff %q, got %q", wantBody, diff.Fields[0].Unified)
+	}
+
+	diff, err = svc.DiffRevisions(context.Background(), 7, 0, 1, domain.DiffWords)
+	if err != nil {
+		t.Fatalf("DiffRevisions returned error: %v", err)
+	}
+	if diff.From != 0 || diff.To != 1 || len(diff.Fields) != 3 {
+		t.Fatalf("expected the first revision to be diffed against an empty article, got %+v", diff)
+	}
+	if want := []domain.DiffSpan{{Op: domain.DiffInsert, Text: "Hello"}}; !slices.Equal(diff.Fields[0].Spans, want) {
+		t.Fatalf("expected title spans %+v, got %+v", want, diff.Fields[0].Spans)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		from, to int64
+		format   domain.DiffFormat
+		want     error
+	}{
+		{"negative revision", -1, 2, "", domain.ErrInvalidRevision},
+		{"unknown revision", 1, 3, "", domain.ErrRevisionNotFound},
+		{"invalid format", 1, 2, "side-by-side", domain.ErrInvalidDiffFormat},
+	} {
+		if _, err := svc.DiffRevisions(context.Background(), 7, tc.from, tc.to, tc.format); !errors.Is(err, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestArticleService_RevertArticle(t *testing.T) {
+	current := domain.Article{ID: 7, Title: "Hi", Body: "New", Tags: []string{"db"}, Status: domain.StatusPublished, Version: 3}
+	var saved domain.Article
+	repo := &stubArticleRepo{
+		getByIDFn: func(_ context.Context, _ int64) (domain.Article, error) {
+			return current, nil
+		},
+		updateFn: func(_ context.Context, article domain.Article) (domain.Article, error) {
+			saved = article
+			article.Version++
+			return article, nil
+		},
+	}
+	revisions := &stubRevisionRepo{
+		saveFn: func(context.Context, []domain.Revision) error { return nil },
+		getFn: func(_ context.Context, articleID, number int64) (domain.Revision, error) {
+			return domain.Revision{ArticleID: articleID, Number: number, Title: "Hello", Body: "Old", Summary: "Sum"}, nil
+		},
+	}
+	svc := NewArticleService(repo, WithRevisions(revisions))
+
+	reverted, err := svc.RevertArticle(context.Background(), 7, 1, 3)
+	if err != nil {
+		t.Fatalf("RevertArticle returned error: %v", err)
+	}
+	if reverted.Version != 4 || saved.Title != "Hello" || saved.Body != "Old" || saved.Summary != "Sum" || len(saved.Tags) != 0 {
+		t.Fatalf("expected revision 1 as version 4, got %+v", reverted)
+	}
+
+	if _, err := svc.RevertArticle(context.Background(), 7, 1, 2); !errors.Is(err, domain.ErrVersionConflict) {
+		t.Fatalf("expected ErrVersionConflict, got %v", err)
+	}
+}
@@ -0,0 +1,222 @@
+package usecase
+
+import (
+	"context"
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+
+	"articles/internal/domain"
+)
+
+const (
+	snippetRadius   = 80
+	highlightOpen   = "<b>"
+	highlightClose  = "</b>"
+	titleWeight     = 1.0
+	summaryWeight   = 0.4
+	bodyWeight      = 0.2
+	searchPageLimit = domain.MaxPageSize
+)
+
+func (s *ArticleService) SearchArticles(ctx context.Context, text string, limit int) (_ []domain.SearchResult, err error) {
+	ctx, span := startSpan(ctx, "ArticleService.SearchArticles")
+	defer endSpan(span, &err)
+
+	text = strings.TrimSpace(text)
+	if text == "" || len([]rune(text)) > domain.MaxSearchQueryLength {
+		return nil, domain.ErrInvalidSearch
+	}
+	if limit == 0 {
+		limit = domain.DefaultPageSize
+	}
+	if limit < 0 || limit > domain.MaxPageSize {
+		return nil, domain.ErrInvalidLimit
+	}
+
+	scope := s.readScope(ctx)
+	query := domain.SearchQuery{Text: text, Limit: limit, Status: scope.status, Owner: scope.owner}
+	if searcher, ok := s.repo.(domain.ArticleSearcher); ok {
+		return searcher.Search(ctx, query)
+	}
+
+	return searchInProcess(ctx, s.repo, query)
+}
+
+// searchInProcess scans every article through List and ranks them with a
+// weighting that mirrors the title/summary/body weights used by Postgres.
+// It is meant for small data sets such as tests and local development.
+func searchInProcess(ctx context.Context, repo domain.ArticleRepository, query domain.SearchQuery) ([]domain.SearchResult, error) {
+	terms := tokenize(query.Text)
+	if len(terms) == 0 {
+		return []domain.SearchResult{}, nil
+	}
+
+	results := []domain.SearchResult{}
+	listQuery := domain.ListArticlesQuery{Limit: searchPageLimit, Status: query.Status, Owner: query.Owner}
+	for {
+		articles, err := repo.List(ctx, listQuery)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, article := range articles {
+			if rank, ok := rankArticle(article, terms); ok {
+				results = append(results, domain.SearchResult{
+					Article: article,
+					Rank:    rank,
+					Snippet: snippet(article, terms),
+				})
+			}
+		}
+
+		if len(articles) < listQuery.Limit {
+			break
+		}
+		last := articles[len(articles)-1]
+		listQuery.After = &domain.ArticleCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Rank != results[j].Rank {
+			return results[i].Rank > results[j].Rank
+		}
+		return results[i].Article.ID > results[j].Article.ID
+	})
+	if len(results) > query.Limit {
+		results = results[:query.Limit]
+	}
+
+	return results, nil
+}
+
+// rankArticle reports whether every term occurs in the article and, if so,
+// a score based on weighted term frequencies.
+func rankArticle(article domain.Article, terms []string) (float64, bool) {
+	fields := []struct {
+		words  []string
+		weight float64
+	}{
+		{tokenize(article.Title), titleWeight},
+		{tokenize(article.Summary), summaryWeight},
+		{tokenize(article.Body), bodyWeight},
+	}
+
+	var rank float64
+	for _, term := range terms {
+		var termRank float64
+		for _, field := range fields {
+			for _, word := range field.words {
+				if strings.HasPrefix(word, term) {
+					termRank += field.weight
+				}
+			}
+		}
+		if termRank == 0 {
+			return 0, false
+		}
+		rank += termRank
+	}
+
+	return rank, true
+}
+
+func snippet(article domain.Article, terms []string) string {
+	text := article.Body
+	if !containsAnyTerm(text, terms) {
+		text = article.Summary
+	}
+	if !containsAnyTerm(text, terms) {
+		text = article.Title
+	}
+
+	runes := []rune(text)
+	lower := []rune(strings.ToLower(text))
+	if len(lower) != len(runes) {
+		// Case folding changed the length, so offsets would not line up.
+		lower = runes
+	}
+
+	start, end := 0, len(runes)
+	if first := firstTermIndex(lower, terms); first >= 0 {
+		start = max(0, first-snippetRadius)
+		end = min(len(runes), first+snippetRadius)
+	} else if end > 2*snippetRadius {
+		end = 2 * snippetRadius
+	}
+
+	// The text is escaped a stretch at a time so only the highlight tags
+	// remain markup.
+	var b strings.Builder
+	plain := start
+	for i := start; i < end; {
+		if isWordStart(lower, i) {
+			if length := matchLength(lower, i, terms); length > 0 {
+				b.WriteString(html.EscapeString(string(runes[plain:i])))
+				b.WriteString(highlightOpen)
+				b.WriteString(html.EscapeString(string(runes[i : i+length])))
+				b.WriteString(highlightClose)
+				i += length
+				plain = i
+				continue
+			}
+		}
+		i++
+	}
+	if plain < end {
+		b.WriteString(html.EscapeString(string(runes[plain:end])))
+	}
+
+	return strings.TrimSpace(b.String())
+}
+
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+func containsAnyTerm(text string, terms []string) bool {
+	for _, word := range tokenize(text) {
+		for _, term := range terms {
+			if strings.HasPrefix(word, term) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func firstTermIndex(lower []rune, terms []string) int {
+	for i := range lower {
+		if isWordStart(lower, i) && matchLength(lower, i, terms) > 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+func isWordStart(runes []rune, i int) bool {
+	return isWordRune(runes[i]) && (i == 0 || !isWordRune(runes[i-1]))
+}
+
+// matchLength returns the length of the word starting at i if it begins with
+// one of the terms, or 0 otherwise.
+func matchLength(runes []rune, i int, terms []string) int {
+	end := i
+	for end < len(runes) && isWordRune(runes[end]) {
+		end++
+	}
+	word := string(runes[i:end])
+	for _, term := range terms {
+		if strings.HasPrefix(word, term) {
+			return end - i
+		}
+	}
+	return 0
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r)
+}
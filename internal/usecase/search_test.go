@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"articles/internal/domain"
+)
+
+type stubSearchRepo struct {
+	stubArticleRepo
+	searchFn func(ctx context.Context, query domain.SearchQuery) ([]domain.SearchResult, error)
+}
+
+func (s *stubSearchRepo) Search(ctx context.Context, query domain.SearchQuery) ([]domain.SearchResult, error) {
+	return s.searchFn(ctx, query)
+}
+
+func TestArticleService_SearchArticles_UsesNativeSearcher(t *testing.T) {
+	want := []domain.SearchResult{{Article: domain.Article{ID: 1, Title: "Hello"}, Rank: 0.5}}
+	repo := &stubSearchRepo{
+		searchFn: func(_ context.Context, query domain.SearchQuery) ([]domain.SearchResult, error) {
+			if query.Text != "hello" || query.Limit != domain.DefaultPageSize {
+				t.Fatalf("unexpected query: %+v", query)
+			}
+			return want, nil
+		},
+	}
+	svc := NewArticleService(repo)
+
+	got, err := svc.SearchArticles(context.Background(), "  hello ", 0)
+	if err != nil {
+		t.Fatalf("SearchArticles returned error: %v", err)
+	}
+	if len(got) != 1 || got[0].Article.ID != 1 {
+		t.Fatalf("expected native results, got %+v", got)
+	}
+}
+
+func TestArticleService_SearchArticles_InProcessFallback(t *testing.T) {
+	articles := []domain.Article{
+		{ID: 4, Title: "Unrelated", Body: "Nothing to see", CreatedAt: time.Unix(40, 0)},
+		{ID: 3, Title: "Weekly notes", Body: "We tuned the Postgres planner today.", CreatedAt: time.Unix(30, 0)},
+		{ID: 2, Title: "Postgres tips", Body: "Indexes matter.", CreatedAt: time.Unix(20, 0)},
+		{ID: 1, Title: "Postgres planner", Summary: "Planner deep dive", CreatedAt: time.Unix(10, 0)},
+	}
+	repo := &stubArticleRepo{
+		listFn: func(_ context.Context, query domain.ListArticlesQuery) ([]domain.Article, error) {
+			if query.After != nil {
+				t.Fatalf("expected a single page, got cursor %+v", query.After)
+			}
+			return articles, nil
+		},
+	}
+	svc := NewArticleService(repo)
+
+	got, err := svc.SearchArticles(context.Background(), "postgres PLANNER", 10)
+	if err != nil {
+		t.Fatalf("SearchArticles returned error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 results, got %+v", got)
+	}
+	if got[0].Article.ID != 1 || got[1].Article.ID != 3 {
+		t.Fatalf("expected title matches to rank first, got ids %d, %d", got[0].Article.ID, got[1].Article.ID)
+	}
+	if got[0].Rank <= got[1].Rank {
+		t.Fatalf("expected descending ranks, got %v then %v", got[0].Rank, got[1].Rank)
+	}
+	if !strings.Contains(got[1].Snippet, "<b>Postgres</b> <b>planner</b>") {
+		t.Fatalf("expected highlighted snippet, got %q", got[1].Snippet)
+	}
+}
+
+func TestArticleService_SearchArticles_FallbackEscapesSnippet(t *testing.T) {
+	repo := &stubArticleRepo{
+		listFn: func(context.Context, domain.ListArticlesQuery) ([]domain.Article, error) {
+			return []domain.Article{{ID: 1, Title: "Tips", Body: "Postgres <i>tricks</i> & more"}}, nil
+		},
+	}
+	svc := NewArticleService(repo)
+
+	got, err := svc.SearchArticles(context.Background(), "postgres", 10)
+	if err != nil {
+		t.Fatalf("SearchArticles returned error: %v", err)
+	}
+	if want := "<b>Postgres</b> &lt;i&gt;tricks&lt;/i&gt; &amp; more"; len(got) != 1 || got[0].Snippet != want {
+		t.Fatalf("expected snippet %q, got %+v", want, got)
+	}
+}
+
+func TestArticleService_SearchArticles_FallbackPagesThroughRepository(t *testing.T) {
+	var calls int
+	repo := &stubArticleRepo{
+		listFn: func(_ context.Context, query domain.ListArticlesQuery) ([]domain.Article, error) {
+			calls++
+			if query.After == nil {
+				page := make([]domain.Article, query.Limit)
+				for i := range page {
+					page[i] = domain.Article{ID: int64(1000 - i), Title: "Filler", CreatedAt: time.Unix(int64(1000-i), 0)}
+				}
+				return page, nil
+			}
+			return []domain.Article{{ID: 1, Title: "Needle", CreatedAt: time.Unix(1, 0)}}, nil
+		},
+	}
+	svc := NewArticleService(repo)
+
+	got, err := svc.SearchArticles(context.Background(), "needle", 5)
+	if err != nil {
+		t.Fatalf("SearchArticles returned error: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 List calls, got %d", calls)
+	}
+	if len(got) != 1 || got[0].Article.ID != 1 {
+		t.Fatalf("expected the needle on the second page, got %+v", got)
+	}
+}
+
+func TestArticleService_SearchArticles_InvalidQuery(t *testing.T) {
+	svc := NewArticleService(&stubArticleRepo{})
+
+	for _, text := range []string{"   ", strings.Repeat("a", domain.MaxSearchQueryLength+1)} {
+		if _, err := svc.SearchArticles(context.Background(), text, 10); !errors.Is(err, domain.ErrInvalidSearch) {
+			t.Fatalf("expected ErrInvalidSearch, got %v", err)
+		}
+	}
+}
+
+func TestArticleService_SearchArticles_InvalidLimit(t *testing.T) {
+	svc := NewArticleService(&stubArticleRepo{})
+
+	if _, err := svc.SearchArticles(context.Background(), "hello", domain.MaxPageSize+1); !errors.Is(err, domain.ErrInvalidLimit) {
+		t.Fatalf("expected ErrInvalidLimit, got %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"articles/internal/domain"
+)
+
+func TestArticleService_UpdateArticle_NewTitleGetsNewSlug(t *testing.T) {
+	current := domain.Article{ID: 7, Title: "Hello", Slug: "hello-2", Version: 1}
+	var saved domain.Article
+	repo := &stubArticleRepo{
+		getByIDFn: func(_ context.Context, _ int64) (domain.Article, error) {
+			return current, nil
+		},
+		updateFn: func(_ context.Context, article domain.Article) (domain.Article, error) {
+			saved = article
+			return article, nil
+		},
+	}
+	svc := NewArticleService(repo)
+
+	if _, err := svc.UpdateArticle(context.Background(), 7, 1, domain.ArticlePatch{Body: stringPtr("new body")}); err != nil {
+		t.Fatalf("UpdateArticle returned error: %v", err)
+	}
+	if saved.Slug != "hello-2" {
+		t.Fatalf("expected the slug to be kept while the title is, got %q", saved.Slug)
+	}
+
+	if _, err := svc.UpdateArticle(context.Background(), 7, 1, domain.ArticlePatch{Title: stringPtr("Goodbye, all")}); err != nil {
+		t.Fatalf("UpdateArticle returned error: %v", err)
+	}
+	if saved.Slug != "goodbye-all" {
+		t.Fatalf("expected slug goodbye-all, got %q", saved.Slug)
+	}
+}
+
+func TestArticleService_GetArticleBySlug_Empty(t *testing.T) {
+	svc := NewArticleService(&stubArticleRepo{})
+
+	if _, err := svc.GetArticleBySlug(context.Background(), ""); !errors.Is(err, domain.ErrArticleNotFound) {
+		t.Fatalf("expected ErrArticleNotFound, got %v", err)
+	}
+}
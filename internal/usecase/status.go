@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"articles/internal/auth"
+	"articles/internal/domain"
+)
+
+// publishBatchSize is the number of due articles PublishDueArticles loads at
+// a time.
+const publishBatchSize = domain.MaxBatchSize
+
+type trustedKey struct{}
+
+// WithTrusted lets the caller in ctx read articles of every status without
+// being known. Servers running with authentication off trust every request
+// this way; with it on, ActionReadUnpublished in the policy decides.
+func WithTrusted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, trustedKey{}, true)
+}
+
+func trusted(ctx context.Context) bool {
+	marked, _ := ctx.Value(trustedKey{}).(bool)
+	return marked
+}
+
+// readScope is what the caller may read: articles in status, or in every
+// status when it is empty, and those of owner whatever their status.
+type readScope struct {
+	status domain.ArticleStatus
+	owner  string
+}
+
+func (s *ArticleService) readScope(ctx context.Context) readScope {
+	if trusted(ctx) || s.policy.Authorize(ctx, ActionReadUnpublished, nil) == nil {
+		return readScope{}
+	}
+	scope := readScope{status: domain.StatusPublished}
+	// An article of the caller's own tells whether the policy grants it
+	// its own articles.
+	principal, _ := auth.PrincipalFrom(ctx)
+	if s.policy.Authorize(ctx, ActionReadUnpublished, &domain.Article{AuthorID: principal.AuthorID}) == nil {
+		scope.owner = principal.AuthorID
+	}
+	return scope
+}
+
+// visible reports whether the caller may read article. Articles it may not
+// read are reported as not found rather than forbidden, so their existence
+// does not leak.
+func (s *ArticleService) visible(ctx context.Context, article domain.Article) bool {
+	return article.Status == domain.StatusPublished || trusted(ctx) ||
+		s.policy.Authorize(ctx, ActionReadUnpublished, &article) == nil
+}
+
+// getVisible loads a live article the caller may read.
+func (s *ArticleService) getVisible(ctx context.Context, id int64) (domain.Article, error) {
+	article, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return domain.Article{}, err
+	}
+	if !s.visible(ctx, article) {
+		return domain.Article{}, domain.ErrArticleNotFound
+	}
+	return article, nil
+}
+
+// publishes reports whether patch publishes current or schedules it to be
+// published. Clearing a schedule does neither.
+func publishes(current domain.Article, patch domain.ArticlePatch) bool {
+	if patch.Status != nil && *patch.Status == domain.StatusPublished && current.Status != domain.StatusPublished {
+		return true
+	}
+	return schedules(patch.PublishAt)
+}
+
+// schedules reports whether publishAt sets a time to publish at.
+func schedules(publishAt *time.Time) bool {
+	return publishAt != nil && !publishAt.IsZero()
+}
+
+// applyStatusPatch moves current to the status and schedule patch asks for,
+// enforcing the workflow. Publishing records the time of publication as
+// PublishAt.
+func applyStatusPatch(current *domain.Article, patch domain.ArticlePatch, now time.Time) error {
+	if patch.Status != nil && *patch.Status != current.Status {
+		status, err := domain.ParseArticleStatus(string(*patch.Status))
+		if err != nil {
+			return err
+		}
+		if !domain.CanTransition(current.Status, status) {
+			return domain.ErrStatusTransition
+		}
+		current.Status = status
+		if status == domain.StatusPublished {
+			current.PublishAt = &now
+		}
+	}
+
+	if patch.PublishAt != nil {
+		if !current.Status.Schedulable() {
+			return domain.ErrInvalidPublishAt
+		}
+		current.PublishAt = nil
+		if !patch.PublishAt.IsZero() {
+			publishAt := patch.PublishAt.UTC()
+			current.PublishAt = &publishAt
+		}
+	}
+
+	return nil
+}
+
+// PublishDueArticles publishes every in_review article whose PublishAt is at
+// or before now and returns how many it published. An article changed by
+// someone else in the meantime is left for the next run.
+func (s *ArticleService) PublishDueArticles(ctx context.Context, now time.Time) (_ int, err error) {
+	ctx, span := startSpan(ctx, "ArticleService.PublishDueArticles")
+	defer endSpan(span, &err)
+
+	published := 0
+	for {
+		due, err := s.repo.ListScheduled(ctx, now, publishBatchSize)
+		if err != nil {
+			return published, err
+		}
+
+		progress := false
+		for _, article := range due {
+			article.Status = domain.StatusPublished
+			updated, err := s.writeArticle(ctx, func(ctx context.Context) (domain.Article, error) {
+				return s.repo.Update(ctx, article)
+			})
+			if errors.Is(err, domain.ErrVersionConflict) || errors.Is(err, domain.ErrArticleNotFound) {
+				continue
+			}
+			if err != nil {
+				return published, err
+			}
+			published++
+			slog.InfoContext(ctx, "article published", "article_id", updated.ID, "publish_at", updated.PublishAt)
+			progress = true
+		}
+
+		// A short page was the last one; a page of conflicts would only be
+		// read again.
+		if len(due) < publishBatchSize || !progress {
+			return published, nil
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"articles/internal/auth"
+	"articles/internal/domain"
+)
+
+func TestArticleService_GetArticle_DraftVisibility(t *testing.T) {
+	repo := &stubArticleRepo{
+		getByIDFn: func(_ context.Context, id int64) (domain.Article, error) {
+			return domain.Article{ID: id, Title: "Hello", AuthorID: "bob", Status: domain.StatusDraft}, nil
+		},
+	}
+	svc := NewArticleService(repo)
+
+	for name, ctx := range map[string]context.Context{
+		"an anonymous caller": context.Background(),
+		"an actor":            WithActor(context.Background(), "user-1"),
+		"a reader":            asPrincipal("alice", auth.RoleReader),
+		"another author":      asPrincipal("alice", auth.RoleAuthor),
+	} {
+		if _, err := svc.GetArticle(ctx, 1); !errors.Is(err, domain.ErrArticleNotFound) {
+			t.Fatalf("expected ErrArticleNotFound for %s, got %v", name, err)
+		}
+	}
+	for name, ctx := range map[string]context.Context{
+		"its author":       asPrincipal("bob", auth.RoleAuthor),
+		"an editor":        asPrincipal("carol", auth.RoleEditor),
+		"an admin":         asPrincipal("dave", auth.RoleAdmin),
+		"a trusted caller": WithTrusted(context.Background()),
+	} {
+		if _, err := svc.GetArticle(ctx, 1); err != nil {
+			t.Fatalf("expected %s to see the draft, got %v", name, err)
+		}
+	}
+}
+
+func TestArticleService_ListTags_AnonymousCountsPublished(t *testing.T) {
+	var got []domain.ArticleStatus
+	repo := &stubArticleRepo{
+		listTagsFn: func(_ context.Context, status domain.ArticleStatus) ([]domain.TagCount, error) {
+			got = append(got, status)
+			return nil, nil
+		},
+	}
+	svc := NewArticleService(repo)
+
+	for _, ctx := range []context.Context{
+		context.Background(),
+		asPrincipal("alice", auth.RoleReader),
+		asPrincipal("carol", auth.RoleEditor),
+	} {
+		if _, err := svc.ListTags(ctx); err != nil {
+			t.Fatalf("ListTags returned error: %v", err)
+		}
+	}
+	if want := []domain.ArticleStatus{domain.StatusPublished, domain.StatusPublished, ""}; !slices.Equal(got, want) {
+		t.Fatalf("expected status filters %q, got %q", want, got)
+	}
+}
+
+func TestArticleService_ListArticles_AnonymousSeesPublished(t *testing.T) {
+	var got domain.ArticleStatus
+	repo := &stubArticleRepo{
+		listFn: func(_ context.Context, query domain.ListArticlesQuery) ([]domain.Article, error) {
+			got = query.Status
+			return nil, nil
+		},
+	}
+	svc := NewArticleService(repo)
+
+	if _, err := svc.ListArticles(context.Background(), 0, "", domain.TagFilter{}); err != nil {
+		t.Fatalf("ListArticles returned error: %v", err)
+	}
+	if got != domain.StatusPublished {
+		t.Fatalf("expected status filter %q, got %q", domain.StatusPublished, got)
+	}
+}
+
+func TestArticleService_ListArticles_AuthorsSeeTheirOwn(t *testing.T) {
+	var got domain.ListArticlesQuery
+	repo := &stubArticleRepo{
+		listFn: func(_ context.Context, query domain.ListArticlesQuery) ([]domain.Article, error) {
+			got = query
+			return nil, nil
+		},
+	}
+	svc := NewArticleService(repo)
+
+	for _, tc := range []struct {
+		ctx    context.Context
+		status domain.ArticleStatus
+		owner  string
+	}{
+		{asPrincipal("bob", auth.RoleAuthor), domain.StatusPublished, "bob"},
+		{asPrincipal("alice", auth.RoleReader), domain.StatusPublished, ""},
+		{asPrincipal("carol", auth.RoleEditor), "", ""},
+	} {
+		if _, err := svc.ListArticles(tc.ctx, 0, "", domain.TagFilter{}); err != nil {
+			t.Fatalf("ListArticles returned error: %v", err)
+		}
+		if got.Status != tc.status || got.Owner != tc.owner {
+			t.Fatalf("expected status %q and owner %q, got %q and %q", tc.status, tc.owner, got.Status, got.Owner)
+		}
+	}
+}
+
+func TestArticleService_UpdateArticle_Transitions(t *testing.T) {
+	now := time.Now().UTC()
+	scheduled := now.Add(time.Hour)
+
+	for _, tc := range []struct {
+		name    string
+		current domain.Article
+		patch   domain.ArticlePatch
+		want    domain.ArticleStatus
+		wantErr error
+	}{
+		{"submit for review", domain.Article{Status: domain.StatusDraft}, domain.ArticlePatch{Status: statusPtr(domain.StatusInReview)}, domain.StatusInReview, nil},
+		{"publish", domain.Article{Status: domain.StatusInReview}, domain.ArticlePatch{Status: statusPtr(domain.StatusPublished)}, domain.StatusPublished, nil},
+		{"skip review", domain.Article{Status: domain.StatusDraft}, domain.ArticlePatch{Status: statusPtr(domain.StatusPublished)}, "", domain.ErrStatusTransition},
+		{"unarchive", domain.Article{Status: domain.StatusArchived}, domain.ArticlePatch{Status: statusPtr(domain.StatusDraft)}, "", domain.ErrStatusTransition},
+		{"unknown status", domain.Article{Status: domain.StatusDraft}, domain.ArticlePatch{Status: statusPtr("live")}, "", domain.ErrInvalidStatus},
+		{"schedule", domain.Article{Status: domain.StatusInReview}, domain.ArticlePatch{PublishAt: &scheduled}, domain.StatusInReview, nil},
+		{"schedule published", domain.Article{Status: domain.StatusPublished}, domain.ArticlePatch{PublishAt: &scheduled}, "", domain.ErrInvalidPublishAt},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.current.ID, tc.current.Title, tc.current.Version = 7, "Hello", 1
+			repo := &stubArticleRepo{
+				getByIDFn: func(_ context.Context, _ int64) (domain.Article, error) {
+					return tc.current, nil
+				},
+				updateFn: func(_ context.Context, article domain.Article) (domain.Article, error) {
+					article.Version++
+					return article, nil
+				},
+			}
+			svc := NewArticleService(repo)
+
+			got, err := svc.UpdateArticle(context.Background(), 7, 1, tc.patch)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected %v, got %v", tc.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if got.Status != tc.want {
+				t.Fatalf("expected status %q, got %q", tc.want, got.Status)
+			}
+			if got.Status == domain.StatusPublished && (got.PublishAt == nil || got.PublishAt.Before(now)) {
+				t.Fatalf("expected publishing to record the time, got %v", got.PublishAt)
+			}
+			if tc.patch.PublishAt != nil && (got.PublishAt == nil || !got.PublishAt.Equal(scheduled)) {
+				t.Fatalf("expected publish_at %v, got %v", scheduled, got.PublishAt)
+			}
+		})
+	}
+}
+
+func TestArticleService_CreateArticle_RejectsPublished(t *testing.T) {
+	svc := NewArticleService(&stubArticleRepo{})
+
+	_, err := svc.CreateArticle(context.Background(), domain.ArticleInput{Title: "Hello", Status: domain.StatusPublished})
+	if !errors.Is(err, domain.ErrStatusTransition) {
+		t.Fatalf("expected ErrStatusTransition, got %v", err)
+	}
+}
+
+func TestArticleService_PublishDueArticles(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	due := now.Add(-time.Minute)
+	var updated []int64
+	repo := &stubArticleRepo{
+		listDueFn: func(_ context.Context, before time.Time, limit int) ([]domain.Article, error) {
+			if !before.Equal(now) || limit != publishBatchSize {
+				t.Fatalf("expected due %v and limit %d, got %v and %d", now, publishBatchSize, before, limit)
+			}
+			if len(updated) > 0 {
+				return nil, nil
+			}
+			return []domain.Article{
+				{ID: 1, Status: domain.StatusInReview, PublishAt: &due, Version: 1},
+				{ID: 2, Status: domain.StatusInReview, PublishAt: &due, Version: 4},
+			}, nil
+		},
+		updateFn: func(_ context.Context, article domain.Article) (domain.Article, error) {
+			if article.ID == 2 {
+				return domain.Article{}, domain.ErrVersionConflict
+			}
+			if article.Status != domain.StatusPublished || !article.PublishAt.Equal(due) {
+				t.Fatalf("expected a published article keeping publish_at, got %+v", article)
+			}
+			updated = append(updated, article.ID)
+			article.Version++
+			return article, nil
+		},
+	}
+	svc := NewArticleService(repo)
+
+	published, err := svc.PublishDueArticles(context.Background(), now)
+	if err != nil {
+		t.Fatalf("PublishDueArticles returned error: %v", err)
+	}
+	if published != 1 || len(updated) != 1 || updated[0] != 1 {
+		t.Fatalf("expected only article 1 to be published, got %d (%v)", published, updated)
+	}
+}
+
+func statusPtr(status domain.ArticleStatus) *domain.ArticleStatus {
+	return &status
+}
@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+
+	"articles/internal/domain"
+)
+
+// ListTags returns every tag in use with the number of live articles
+// carrying it, most used first. Callers reading every status count every
+// article; the rest count the published ones, their own unpublished
+// articles left out.
+func (s *ArticleService) ListTags(ctx context.Context) (_ []domain.TagCount, err error) {
+	ctx, span := startSpan(ctx, "ArticleService.ListTags")
+	defer endSpan(span, &err)
+
+	return s.repo.ListTags(ctx, s.readScope(ctx).status)
+}
+
+// normalizeTagFilter normalizes the filter's tags the way they are stored
+// and defaults Match to TagMatchAll.
+func normalizeTagFilter(filter domain.TagFilter) (domain.TagFilter, error) {
+	switch filter.Match {
+	case "":
+		filter.Match = domain.TagMatchAll
+	case domain.TagMatchAll, domain.TagMatchAny:
+	default:
+		return domain.TagFilter{}, domain.ErrInvalidTagMatch
+	}
+
+	tags, err := domain.NormalizeTags(filter.Tags)
+	if err != nil {
+		return domain.TagFilter{}, domain.ErrInvalidTagFilter
+	}
+	filter.Tags = tags
+
+	return filter, nil
+}
@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"articles/internal/domain"
+)
+
+func TestArticleService_ListArticles_NormalizesTagFilter(t *testing.T) {
+	repo := &stubArticleRepo{
+		listFn: func(_ context.Context, query domain.ListArticlesQuery) ([]domain.Article, error) {
+			if !slices.Equal(query.Tags.Tags, []string{"db", "go"}) {
+				t.Fatalf("expected tags [db go], got %v", query.Tags.Tags)
+			}
+			if query.Tags.Match != domain.TagMatchAll {
+				t.Fatalf("expected match %q, got %q", domain.TagMatchAll, query.Tags.Match)
+			}
+			return nil, nil
+		},
+	}
+	svc := NewArticleService(repo)
+
+	_, err := svc.ListArticles(context.Background(), 10, "", domain.TagFilter{Tags: []string{" Go", "db", "go"}})
+	if err != nil {
+		t.Fatalf("ListArticles returned error: %v", err)
+	}
+}
+
+func TestArticleService_ListArticles_InvalidTagFilter(t *testing.T) {
+	svc := NewArticleService(&stubArticleRepo{})
+
+	tooMany := make([]string, domain.MaxTagsPerArticle+1)
+	for i := range tooMany {
+		tooMany[i] = string(rune('a' + i))
+	}
+
+	for _, tc := range []struct {
+		name   string
+		filter domain.TagFilter
+		want   error
+	}{
+		{"invalid tag", domain.TagFilter{Tags: []string{"no spaces"}}, domain.ErrInvalidTagFilter},
+		{"too many tags", domain.TagFilter{Tags: tooMany}, domain.ErrInvalidTagFilter},
+		{"invalid match", domain.TagFilter{Tags: []string{"go"}, Match: "some"}, domain.ErrInvalidTagMatch},
+	} {
+		_, err := svc.ListArticles(context.Background(), 10, "", tc.filter)
+		if !errors.Is(err, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestArticleService_UpdateArticle_Tags(t *testing.T) {
+	current := domain.Article{ID: 7, Title: "Hello", Tags: []string{"go"}, Version: 1}
+	var saved domain.Article
+	repo := &stubArticleRepo{
+		getByIDFn: func(_ context.Context, _ int64) (domain.Article, error) {
+			return current, nil
+		},
+		updateFn: func(_ context.Context, article domain.Article) (domain.Article, error) {
+			saved = article
+			return article, nil
+		},
+	}
+	svc := NewArticleService(repo)
+
+	if _, err := svc.UpdateArticle(context.Background(), 7, 1, domain.ArticlePatch{Title: stringPtr("Hi")}); err != nil {
+		t.Fatalf("UpdateArticle returned error: %v", err)
+	}
+	if !slices.Equal(saved.Tags, []string{"go"}) {
+		t.Fatalf("expected tags to be kept, got %v", saved.Tags)
+	}
+
+	tags := []string{"SQL", "db"}
+	if _, err := svc.UpdateArticle(context.Background(), 7, 1, domain.ArticlePatch{Tags: &tags}); err != nil {
+		t.Fatalf("UpdateArticle returned error: %v", err)
+	}
+	if !slices.Equal(saved.Tags, []string{"db", "sql"}) {
+		t.Fatalf("expected tags [db sql], got %v", saved.Tags)
+	}
+
+	tags = []string{}
+	if _, err := svc.UpdateArticle(context.Background(), 7, 1, domain.ArticlePatch{Tags: &tags}); err != nil {
+		t.Fatalf("UpdateArticle returned error: %v", err)
+	}
+	if len(saved.Tags) != 0 {
+		t.Fatalf("expected tags to be cleared, got %v", saved.Tags)
+	}
+}
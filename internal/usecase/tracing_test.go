@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"articles/internal/domain"
+)
+
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	return recorder
+}
+
+func TestArticleService_CreateArticle_StartsChildSpan(t *testing.T) {
+	recorder := recordSpans(t)
+
+	parentCtx, parent := otel.Tracer("test").Start(context.Background(), "parent")
+	repo := &stubArticleRepo{
+		saveFn: func(ctx context.Context, article domain.Article) (domain.Article, error) {
+			return article, nil
+		},
+	}
+
+	if _, err := NewArticleService(repo).CreateArticle(parentCtx, domain.ArticleInput{Title: "Traced"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	child := spans[0]
+	if child.Name() != "ArticleService.CreateArticle" {
+		t.Fatalf("expected span ArticleService.CreateArticle, got %q", child.Name())
+	}
+	if child.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Fatalf("expected span to be a child of the caller's span")
+	}
+}
+
+func TestArticleService_GetArticle_RecordsError(t *testing.T) {
+	recorder := recordSpans(t)
+
+	repo := &stubArticleRepo{
+		getByIDFn: func(context.Context, int64) (domain.Article, error) {
+			return domain.Article{}, domain.ErrArticleNotFound
+		},
+	}
+
+	if _, err := NewArticleService(repo).GetArticle(context.Background(), 7); !errors.Is(err, domain.ErrArticleNotFound) {
+		t.Fatalf("expected ErrArticleNotFound, got %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if spans[0].Status().Code != codes.Error {
+		t.Fatalf("expected error status, got %v", spans[0].Status())
+	}
+	var id int64
+	for _, attr := range spans[0].Attributes() {
+		if attr.Key == "article.id" {
+			id = attr.Value.AsInt64()
+		}
+	}
+	if id != 7 {
+		t.Fatalf("expected article.id attribute 7, got %d", id)
+	}
+}
@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+
+	"articles/internal/domain"
+)
+
+const (
+	// exportPageSize is how many articles ExportArticles reads per query.
+	exportPageSize = 500
+	// maxImportErrors caps the line errors kept in an ImportResult.
+	maxImportErrors = 100
+)
+
+// ImportSource yields the records of an import file. Next returns io.EOF
+// after the last record; any other error aborts the import.
+type ImportSource interface {
+	Next() (domain.ImportRecord, error)
+}
+
+// ExportArticles pages through every live article the caller may read,
+// newest first, and hands each page to emit, so the whole table is never
+// held in memory. It returns the number of articles exported.
+func (s *ArticleService) ExportArticles(ctx context.Context, emit func([]domain.Article) error) (_ int, err error) {
+	ctx, span := startSpan(ctx, "ArticleService.ExportArticles")
+	defer endSpan(span, &err)
+
+	exported := 0
+	scope := s.readScope(ctx)
+	query := domain.ListArticlesQuery{Limit: exportPageSize, Status: scope.status, Owner: scope.owner}
+	for {
+		page, err := s.repo.List(ctx, query)
+		if err != nil {
+			return exported, err
+		}
+		if len(page) == 0 {
+			break
+		}
+		if err := emit(page); err != nil {
+			return exported, err
+		}
+		exported += len(page)
+
+		if len(page) < exportPageSize {
+			break
+		}
+		last := page[len(page)-1]
+		query.After = &domain.ArticleCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	slog.InfoContext(ctx, "articles exported", "count", exported)
+	return exported, nil
+}
+
+// ImportArticles creates an article for every valid record of source.
+// Records are validated like CreateArticle input and saved in batches of
+// domain.MaxBatchSize; invalid records are reported by line and skipped.
+// Their slug and creation time are kept, the slug suffixed when taken.
+// When source fails, the batches saved so far stay and the result counts
+// them.
+func (s *ArticleService) ImportArticles(ctx context.Context, source ImportSource) (_ domain.ImportResult, err error) {
+	ctx, span := startSpan(ctx, "ArticleService.ImportArticles")
+	defer endSpan(span, &err)
+
+	if err := s.policy.Authorize(ctx, ActionImport, nil); err != nil {
+		return domain.ImportResult{}, err
+	}
+
+	var (
+		result  domain.ImportResult
+		pending = make([]domain.Article, 0, domain.MaxBatchSize)
+	)
+	fail := func(line int, err error) {
+		result.Failed++
+		if len(result.Errors) < maxImportErrors {
+			result.Errors = append(result.Errors, domain.ImportLineError{Line: line, Err: err})
+		}
+	}
+	flush := func() error {
+		if len(pending) == 0 {
+			return nil
+		}
+		saved, err := s.writeArticles(ctx, func(ctx context.Context) ([]domain.Article, error) {
+			return s.repo.SaveMany(ctx, pending)
+		})
+		if err != nil {
+			return err
+		}
+		result.Imported += len(saved)
+		pending = pending[:0]
+		return nil
+	}
+
+	for {
+		record, err := source.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return result, err
+		}
+		if record.Err != nil {
+			fail(record.Line, record.Err)
+			continue
+		}
+
+		article, err := domain.NewArticle(record.Input)
+		if err != nil {
+			fail(record.Line, err)
+			continue
+		}
+		if record.Slug != "" {
+			article.Slug = record.Slug
+		}
+		article.CreatedAt = record.CreatedAt
+		pending = append(pending, article)
+		if len(pending) == domain.MaxBatchSize {
+			if err := flush(); err != nil {
+				return result, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return result, err
+	}
+
+	slog.InfoContext(ctx, "articles imported", "count", result.Imported, "failed", result.Failed)
+	return result, nil
+}
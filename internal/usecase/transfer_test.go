@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"articles/internal/domain"
+)
+
+type sliceSource struct {
+	records []domain.ImportRecord
+	err     error
+}
+
+func (s *sliceSource) Next() (domain.ImportRecord, error) {
+	if len(s.records) == 0 {
+		if s.err != nil {
+			return domain.ImportRecord{}, s.err
+		}
+		return domain.ImportRecord{}, io.EOF
+	}
+	record := s.records[0]
+	s.records = s.records[1:]
+	return record, nil
+}
+
+func TestArticleService_ExportArticles_PagesThroughTable(t *testing.T) {
+	total := exportPageSize + 1
+	var queries []domain.ListArticlesQuery
+	repo := &stubArticleRepo{
+		listFn: func(_ context.Context, query domain.ListArticlesQuery) ([]domain.Article, error) {
+			queries = append(queries, query)
+			start := 0
+			if query.After != nil {
+				start = int(query.After.ID)
+			}
+			end := min(start+query.Limit, total)
+			page := make([]domain.Article, 0, end-start)
+			for id := start + 1; id <= end; id++ {
+				page = append(page, domain.Article{ID: int64(id)})
+			}
+			return page, nil
+		},
+	}
+	svc := NewArticleService(repo)
+
+	var pages []int
+	exported, err := svc.ExportArticles(context.Background(), func(page []domain.Article) error {
+		pages = append(pages, len(page))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ExportArticles returned error: %v", err)
+	}
+
+	if exported != total {
+		t.Fatalf("expected %d exported, got %d", total, exported)
+	}
+	if len(pages) != 2 || pages[0] != exportPageSize || pages[1] != 1 {
+		t.Fatalf("expected pages of %d and 1, got %v", exportPageSize, pages)
+	}
+	if queries[1].After == nil || queries[1].After.ID != int64(exportPageSize) {
+		t.Fatalf("expected the second page to start after the first, got %+v", queries[1])
+	}
+}
+
+func TestArticleService_ImportArticles_ReportsLineErrors(t *testing.T) {
+	var saved []domain.Article
+	repo := &stubArticleRepo{
+		saveManyFn: func(_ context.Context, articles []domain.Article) ([]domain.Article, error) {
+			saved = append(saved, articles...)
+			return articles, nil
+		},
+	}
+	svc := NewArticleService(repo)
+
+	records := make([]domain.ImportRecord, 0, domain.MaxBatchSize+3)
+	for i := range domain.MaxBatchSize + 1 {
+		records = append(records, domain.ImportRecord{Line: i + 1, Input: domain.ArticleInput{Title: "Title"}})
+	}
+	records = append(records,
+		domain.ImportRecord{Line: 200, Input: domain.ArticleInput{Title: ""}},
+		domain.ImportRecord{Line: 201, Err: domain.ErrInvalidRecord},
+	)
+
+	result, err := svc.ImportArticles(context.Background(), &sliceSource{records: records})
+	if err != nil {
+		t.Fatalf("ImportArticles returned error: %v", err)
+	}
+
+	if result.Imported != domain.MaxBatchSize+1 || len(saved) != domain.MaxBatchSize+1 {
+		t.Fatalf("expected %d imported, got %d (saved %d)", domain.MaxBatchSize+1, result.Imported, len(saved))
+	}
+	if result.Failed != 2 || len(result.Errors) != 2 {
+		t.Fatalf("expected 2 failures, got %+v", result)
+	}
+	if result.Errors[0].Line != 200 || !errors.Is(result.Errors[0].Err, domain.ErrInvalidTitle) {
+		t.Fatalf("expected line 200 to fail with ErrInvalidTitle, got %+v", result.Errors[0])
+	}
+	if result.Errors[1].Line != 201 || !errors.Is(result.Errors[1].Err, domain.ErrInvalidRecord) {
+		t.Fatalf("expected line 201 to fail with ErrInvalidRecord, got %+v", result.Errors[1])
+	}
+}
+
+func TestArticleService_ImportArticles_StopsOnSourceError(t *testing.T) {
+	readErr := errors.New("connection reset")
+	repo := &stubArticleRepo{
+		saveManyFn: func(_ context.Context, articles []domain.Article) ([]domain.Article, error) {
+			return articles, nil
+		},
+	}
+	svc := NewArticleService(repo)
+
+	source := &sliceSource{records: []domain.ImportRecord{{Line: 1, Input: domain.ArticleInput{Title: "One"}}}, err: readErr}
+	result, err := svc.ImportArticles(context.Background(), source)
+
+	if !errors.Is(err, readErr) {
+		t.Fatalf("expected the read error, got %v", err)
+	}
+	if result.Imported != 0 {
+		t.Fatalf("expected the unsaved batch to be dropped, got %d imported", result.Imported)
+	}
+}